@@ -19,10 +19,14 @@ import (
 	httpAdapter "github.com/orchestrix/orchestrix-api/internal/adapter/driving/http"
 
 	// Driven adapters (Infrastructure)
+	"github.com/orchestrix/orchestrix-api/internal/adapter/driven/catalog"
+	"github.com/orchestrix/orchestrix-api/internal/adapter/driven/envelope"
 	"github.com/orchestrix/orchestrix-api/internal/adapter/driven/postgres"
 	temporalAdapter "github.com/orchestrix/orchestrix-api/internal/adapter/driven/temporal"
+	"github.com/orchestrix/orchestrix-api/internal/workflow"
 
 	// Core services
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
 	"github.com/orchestrix/orchestrix-api/internal/core/service"
 
 	// Auth (middleware)
@@ -76,13 +80,35 @@ func main() {
 	// Driven Adapters (Secondary/Infrastructure)
 	tenantContextSetter := postgres.NewTenantContextSetter(pool)
 	workflowRepo := postgres.NewWorkflowRepository(pool)
+	workflowVersionRepo := postgres.NewWorkflowVersionRepository(pool)
 	executionRepo := postgres.NewExecutionRepository(pool)
+	executionStepRepo := postgres.NewExecutionStepRepository(pool)
 	alertRepo := postgres.NewAlertRepository(pool)
 	auditRepo := postgres.NewAuditRepository(pool)
 	alertRuleRepo := postgres.NewAlertRuleRepository(pool)
 	metricRepo := postgres.NewMetricRepository(pool)
 	metricDefRepo := postgres.NewMetricDefinitionRepository(pool)
+	secretRepo := postgres.NewSecretRepository(pool)
 	workflowExecutor := temporalAdapter.NewWorkflowExecutor(temporalClient)
+	workflowScheduler := temporalAdapter.NewWorkflowScheduler(temporalClient)
+	definitionValidator := workflow.NewDefinitionValidator()
+	templateCatalog, err := catalog.NewTemplateCatalog(definitionValidator)
+	if err != nil {
+		slog.Error("failed to load workflow templates", "error", err)
+		os.Exit(1)
+	}
+	// Secret values can't be set without a master key
+	var secretCipher port.SecretCipher
+	if masterKey := os.Getenv("SECRETS_MASTER_KEY"); masterKey != "" {
+		c, err := envelope.NewCipher(masterKey)
+		if err != nil {
+			slog.Error("invalid SECRETS_MASTER_KEY", "error", err)
+			os.Exit(1)
+		}
+		secretCipher = c
+	} else {
+		slog.Warn("SECRETS_MASTER_KEY is not set, secrets are disabled")
+	}
 
 	// Core Services (Application Layer)
 	auditService := service.NewAuditService(auditRepo, tenantContextSetter)
@@ -94,11 +120,14 @@ func main() {
 		auditService,
 		tenantContextSetter,
 	)
-	executionService := service.NewExecutionService(executionRepo, workflowExecutor, tenantContextSetter)
+	executionService := service.NewExecutionService(executionRepo, executionStepRepo, workflowRepo, workflowVersionRepo, workflowExecutor, auditService, tenantContextSetter)
 	workflowService := service.NewWorkflowService(
 		workflowRepo,
+		workflowVersionRepo,
 		executionRepo,
 		workflowExecutor,
+		workflowScheduler,
+		definitionValidator,
 		auditService,
 		tenantContextSetter,
 	)
@@ -108,13 +137,25 @@ func main() {
 		alertRuleService,
 		tenantContextSetter,
 	)
+	bundleService := service.NewBundleService(
+		workflowRepo,
+		alertRuleRepo,
+		workflowService,
+		metricService,
+		auditService,
+		tenantContextSetter,
+	)
+	templateService := service.NewTemplateService(templateCatalog, workflowService)
+	secretService := service.NewSecretService(secretRepo, secretCipher, auditService, tenantContextSetter)
 
 	// Driving Adapters (Primary/HTTP)
-	workflowHandler := httpAdapter.NewWorkflowHandler(workflowService)
+	workflowHandler := httpAdapter.NewWorkflowHandler(workflowService, bundleService, templateService)
+	templateHandler := httpAdapter.NewTemplateHandler(templateService)
 	executionHandler := httpAdapter.NewExecutionHandler(executionService)
 	alertHandler := httpAdapter.NewAlertHandler(alertService)
 	auditHandler := httpAdapter.NewAuditHandler(auditService)
 	metricHandler := httpAdapter.NewMetricHandler(metricService)
+	secretHandler := httpAdapter.NewSecretHandler(secretService)
 
 	// Legacy handlers (not yet migrated to hexagonal)
 	alertRuleHandler := alertrule.NewHandler(pool)
@@ -183,6 +224,7 @@ func main() {
 
 			// Workflow routes (hexagonal)
 			r.Mount("/workflows", workflowHandler.Routes())
+			r.Mount("/workflow-templates", templateHandler.Routes())
 
 			// Execution routes (hexagonal)
 			r.Mount("/executions", executionHandler.Routes())
@@ -198,6 +240,9 @@ func main() {
 
 			// Metrics routes (hexagonal)
 			r.Mount("/metrics", metricHandler.Routes())
+
+			// Secret routes (hexagonal)
+			r.Mount("/secrets", secretHandler.Routes())
 		})
 	})
 
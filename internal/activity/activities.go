@@ -9,11 +9,14 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"go.temporal.io/sdk/temporal"
 )
 
 // Activities holds all activity implementations
 type Activities struct {
 	HTTPClient *http.Client
+	Secrets    *SecretResolver // resolves ${secrets.NAME} in HTTP requests; nil when secrets aren't configured
 }
 
 // NewActivities creates a new Activities instance
@@ -109,28 +112,56 @@ func (a *Activities) Notify(ctx context.Context, input NotifyInput) (*NotifyResu
 	}, nil
 }
 
-// HTTPInput is the input for the HTTP activity
+// HTTPInput is the input for the HTTP activity. The URL, header values and
+// body may reference secrets as ${secrets.NAME}; they are resolved when the
+// request is sent.
 type HTTPInput struct {
+	TenantID     string            `json:"tenant_id,omitempty"` // owner of the referenced secrets
 	URL          string            `json:"url"`
 	Method       string            `json:"method"`
 	Headers      map[string]string `json:"headers,omitempty"`
 	Body         interface{}       `json:"body,omitempty"`
 	Timeout      int               `json:"timeout_seconds,omitempty"`
 	SuccessCodes []int             `json:"success_codes,omitempty"`
+	Extract      []HTTPExtract     `json:"extract,omitempty"`
+	Assert       []HTTPAssert      `json:"assert,omitempty"`
 }
 
 // HTTPResult is the result of the HTTP activity
 type HTTPResult struct {
-	StatusCode int               `json:"status_code"`
-	Body       string            `json:"body"`
-	Headers    map[string]string `json:"headers"`
-	Success    bool              `json:"success"`
-	Error      string            `json:"error,omitempty"`
+	StatusCode int                    `json:"status_code"`
+	Body       string                 `json:"body"`
+	Headers    map[string]string      `json:"headers"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+	LatencyMs  int64                  `json:"latency_ms"`
+	Extracted  map[string]interface{} `json:"extracted,omitempty"` // values of the extract rules, by name
 }
 
-// HTTP performs an HTTP request
+// HTTP performs an HTTP request. A response that fails an extract or assert
+// rule, or a request that fails while rules are set, fails the activity with
+// a retryable HTTPCheckFailed error, so a retry policy polls until it passes.
+// Secret values are redacted from the result and errors.
 func (a *Activities) HTTP(ctx context.Context, input HTTPInput) (*HTTPResult, error) {
 	slog.Info("HTTP activity started", "url", input.URL, "method", input.Method)
+	logURL := input.URL
+
+	for _, rule := range input.Extract {
+		if err := rule.Check(); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError(fmt.Sprintf("invalid extract rule: %v", err), HTTPRuleErrorType, err)
+		}
+	}
+	for _, rule := range input.Assert {
+		if err := rule.Check(); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError(fmt.Sprintf("invalid assert rule: %v", err), HTTPRuleErrorType, err)
+		}
+	}
+	checked := len(input.Extract) > 0 || len(input.Assert) > 0
+
+	secrets, err := a.resolveSecrets(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
 
 	if input.Method == "" {
 		input.Method = "GET"
@@ -143,14 +174,14 @@ func (a *Activities) HTTP(ctx context.Context, input HTTPInput) (*HTTPResult, er
 	if input.Body != nil {
 		bodyBytes, err := json.Marshal(input.Body)
 		if err != nil {
-			return &HTTPResult{Success: false, Error: err.Error()}, nil
+			return &HTTPResult{Success: false, Error: secrets.redact(err.Error())}, nil
 		}
 		bodyReader = bytes.NewReader(bodyBytes)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, input.Method, input.URL, bodyReader)
 	if err != nil {
-		return &HTTPResult{Success: false, Error: err.Error()}, nil
+		return &HTTPResult{Success: false, Error: secrets.redact(err.Error())}, nil
 	}
 
 	for k, v := range input.Headers {
@@ -165,13 +196,18 @@ func (a *Activities) HTTP(ctx context.Context, input HTTPInput) (*HTTPResult, er
 		client = &http.Client{Timeout: time.Duration(input.Timeout) * time.Second}
 	}
 
+	start := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
-		return &HTTPResult{Success: false, Error: err.Error()}, nil
+		if checked {
+			return nil, temporal.NewApplicationError(secrets.redact(fmt.Sprintf("request failed: %v", err)), HTTPCheckErrorType)
+		}
+		return &HTTPResult{Success: false, Error: secrets.redact(err.Error())}, nil
 	}
 	defer resp.Body.Close()
 
 	body, _ := io.ReadAll(resp.Body)
+	latency := time.Since(start)
 
 	headers := make(map[string]string)
 	for k, v := range resp.Header {
@@ -188,14 +224,22 @@ func (a *Activities) HTTP(ctx context.Context, input HTTPInput) (*HTTPResult, er
 		}
 	}
 
-	slog.Info("HTTP activity completed", "url", input.URL, "status", resp.StatusCode, "success", success)
+	slog.Info("HTTP activity completed", "url", logURL, "status", resp.StatusCode, "success", success, "latency", latency)
 
-	return &HTTPResult{
+	result := &HTTPResult{
 		StatusCode: resp.StatusCode,
 		Body:       string(body),
 		Headers:    headers,
 		Success:    success,
-	}, nil
+		LatencyMs:  latency.Milliseconds(),
+	}
+	if checked {
+		if result.Extracted, err = CheckHTTPResponse(input.Extract, input.Assert, result.Body, headers, latency); err != nil {
+			return nil, temporal.NewApplicationError(secrets.redact(err.Error()), HTTPCheckErrorType)
+		}
+	}
+	secrets.redactResult(result)
+	return result, nil
 }
 
 // DelayInput is the input for the Delay activity
@@ -208,7 +252,10 @@ type DelayResult struct {
 	Delayed bool `json:"delayed"`
 }
 
-// Delay pauses execution for a specified duration
+// Delay pauses execution for a specified duration.
+//
+// Deprecated: delay steps now use durable workflow timers. The activity stays
+// registered for executions started before the change.
 func (a *Activities) Delay(ctx context.Context, input DelayInput) (*DelayResult, error) {
 	duration, err := time.ParseDuration(input.Duration)
 	if err != nil {
@@ -0,0 +1,408 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	sdkactivity "go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+// ExecutionErrorType is the Temporal error type of execution bookkeeping
+// failures that won't succeed on retry (unknown workflow, bad IDs)
+const ExecutionErrorType = "ExecutionError"
+
+// ExecutionActivities holds activities that read and write executions and
+// workflows on behalf of running workflows
+type ExecutionActivities struct {
+	workflowRepo  port.WorkflowRepository
+	executionRepo port.ExecutionRepository
+	stepRepo      port.ExecutionStepRepository
+	tenantSetter  port.TenantContextSetter
+}
+
+// NewExecutionActivities creates a new ExecutionActivities instance
+func NewExecutionActivities(
+	workflowRepo port.WorkflowRepository,
+	executionRepo port.ExecutionRepository,
+	stepRepo port.ExecutionStepRepository,
+	tenantSetter port.TenantContextSetter,
+) *ExecutionActivities {
+	return &ExecutionActivities{
+		workflowRepo:  workflowRepo,
+		executionRepo: executionRepo,
+		stepRepo:      stepRepo,
+		tenantSetter:  tenantSetter,
+	}
+}
+
+// StartChildExecutionInput is the input for the StartChildExecution activity
+type StartChildExecutionInput struct {
+	TenantID          string                 `json:"tenant_id"`
+	ParentExecutionID string                 `json:"parent_execution_id"`
+	WorkflowID        string                 `json:"workflow_id,omitempty"`
+	WorkflowName      string                 `json:"workflow_name,omitempty"`
+	Input             map[string]interface{} `json:"input,omitempty"`
+}
+
+// StartChildExecutionResult describes the execution created for a child
+// workflow. Input is the child's input with the defaults of its input schema
+// filled in.
+type StartChildExecutionResult struct {
+	ExecutionID        string                 `json:"execution_id"`
+	TemporalWorkflowID string                 `json:"temporal_workflow_id"`
+	WorkflowID         string                 `json:"workflow_id"`
+	Name               string                 `json:"name"`
+	Definition         json.RawMessage        `json:"definition"`
+	Input              map[string]interface{} `json:"input,omitempty"`
+}
+
+// StartChildExecution resolves the referenced workflow by ID or name,
+// validates the input against its input schema and creates its execution
+// record linked to the parent execution
+func (a *ExecutionActivities) StartChildExecution(ctx context.Context, input StartChildExecutionInput) (*StartChildExecutionResult, error) {
+	slog.Info("StartChildExecution activity started",
+		"parent_execution_id", input.ParentExecutionID,
+		"workflow_id", input.WorkflowID,
+		"workflow_name", input.WorkflowName)
+
+	tenantID, err := uuid.Parse(input.TenantID)
+	if err != nil {
+		return nil, executionError(fmt.Errorf("invalid tenant id: %w", err))
+	}
+	parentID, err := uuid.Parse(input.ParentExecutionID)
+	if err != nil {
+		return nil, executionError(fmt.Errorf("invalid parent execution id: %w", err))
+	}
+
+	if err := a.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	wf, err := a.findWorkflow(ctx, tenantID, input.WorkflowID, input.WorkflowName)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			return nil, executionError(err)
+		}
+		return nil, err
+	}
+	if !wf.CanExecute() {
+		return nil, executionError(fmt.Errorf("workflow %s: %w", wf.Name, domain.ErrWorkflowCannotExecute))
+	}
+
+	runInput, err := applyInputs(wf, input.Input)
+	if err != nil {
+		return nil, err
+	}
+	inputJSON, _ := json.Marshal(runInput)
+
+	execution := &domain.Execution{
+		ID:                activityExecutionID(ctx),
+		TenantID:          tenantID,
+		WorkflowID:        wf.ID,
+		Status:            domain.ExecutionStatusPending,
+		Input:             inputJSON,
+		CreatedAt:         time.Now(),
+		TriggeredBy:       stringPtr("execution:" + parentID.String()),
+		ParentExecutionID: &parentID,
+		WorkflowVersion:   &wf.Version,
+	}
+	temporalWorkflowID, err := a.startExecution(ctx, execution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start child execution: %w", err)
+	}
+
+	return &StartChildExecutionResult{
+		ExecutionID:        execution.ID.String(),
+		TemporalWorkflowID: temporalWorkflowID,
+		WorkflowID:         wf.ID.String(),
+		Name:               wf.Name,
+		Definition:         wf.Definition,
+		Input:              runInput,
+	}, nil
+}
+
+// StartScheduledExecutionInput is the input for the StartScheduledExecution activity
+type StartScheduledExecutionInput struct {
+	TenantID   string `json:"tenant_id"`
+	WorkflowID string `json:"workflow_id"`
+	ScheduleID string `json:"schedule_id"`
+}
+
+// StartScheduledExecutionResult describes the execution created for a
+// scheduled run
+type StartScheduledExecutionResult struct {
+	ExecutionID        string                 `json:"execution_id"`
+	TemporalWorkflowID string                 `json:"temporal_workflow_id"`
+	Name               string                 `json:"name"`
+	Definition         json.RawMessage        `json:"definition"`
+	Input              map[string]interface{} `json:"input,omitempty"`
+}
+
+// StartScheduledExecution loads the latest version of a scheduled workflow
+// and creates the execution record of the run. Scheduled runs get no input,
+// only the defaults of the workflow's input schema. The record is saved
+// under an ID derived from the activity, so a retried attempt reuses it.
+func (a *ExecutionActivities) StartScheduledExecution(ctx context.Context, input StartScheduledExecutionInput) (*StartScheduledExecutionResult, error) {
+	slog.Info("StartScheduledExecution activity started",
+		"workflow_id", input.WorkflowID,
+		"schedule_id", input.ScheduleID)
+
+	tenantID, err := uuid.Parse(input.TenantID)
+	if err != nil {
+		return nil, executionError(fmt.Errorf("invalid tenant id: %w", err))
+	}
+
+	if err := a.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	wf, err := a.findWorkflow(ctx, tenantID, input.WorkflowID, "")
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			return nil, executionError(err)
+		}
+		return nil, err
+	}
+	// The schedule is paused when the workflow is deactivated, but a tick
+	// may already be on its way
+	if !wf.CanExecute() {
+		return nil, executionError(fmt.Errorf("workflow %s: %w", wf.Name, domain.ErrWorkflowCannotExecute))
+	}
+
+	runInput, err := applyInputs(wf, nil)
+	if err != nil {
+		return nil, err
+	}
+	inputJSON, _ := json.Marshal(runInput)
+
+	execution := &domain.Execution{
+		ID:              activityExecutionID(ctx),
+		TenantID:        tenantID,
+		WorkflowID:      wf.ID,
+		Status:          domain.ExecutionStatusPending,
+		Input:           inputJSON,
+		CreatedAt:       time.Now(),
+		TriggeredBy:     stringPtr(domain.ScheduleTrigger(input.ScheduleID)),
+		WorkflowVersion: &wf.Version,
+	}
+	temporalWorkflowID, err := a.startExecution(ctx, execution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start scheduled execution: %w", err)
+	}
+
+	return &StartScheduledExecutionResult{
+		ExecutionID:        execution.ID.String(),
+		TemporalWorkflowID: temporalWorkflowID,
+		Name:               wf.Name,
+		Definition:         wf.Definition,
+		Input:              runInput,
+	}, nil
+}
+
+// applyInputs validates input against the input schema of a workflow and
+// fills in its defaults
+func applyInputs(wf *domain.Workflow, input map[string]interface{}) (map[string]interface{}, error) {
+	def, err := wf.ParseDefinition()
+	if err != nil {
+		return nil, executionError(fmt.Errorf("workflow %s: %w", wf.Name, err))
+	}
+	if def.Inputs == nil {
+		return input, nil
+	}
+	prepared, inputErrs := def.Inputs.Apply(input)
+	if len(inputErrs) > 0 {
+		return nil, executionError(fmt.Errorf("workflow %s: %w: %s %s",
+			wf.Name, domain.ErrInvalidInput, inputErrs[0].Field, inputErrs[0].Message))
+	}
+	return prepared, nil
+}
+
+// activityExecutionID derives the ID of the execution an activity creates
+// from the workflow run and the activity, so every attempt of the activity
+// uses the same ID
+func activityExecutionID(ctx context.Context) uuid.UUID {
+	info := sdkactivity.GetInfo(ctx)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(info.WorkflowExecution.RunID+"/"+info.ActivityID))
+}
+
+// startExecution saves an execution that is about to run as a child
+// workflow and marks it running. It returns the Temporal workflow ID the
+// child must use; the run ID is only known once the child has started. A
+// retried activity finds the execution its earlier attempt saved.
+func (a *ExecutionActivities) startExecution(ctx context.Context, execution *domain.Execution) (string, error) {
+	_, err := a.executionRepo.FindByID(ctx, execution.ID)
+	switch {
+	case errors.Is(err, domain.ErrExecutionNotFound):
+		if err := a.executionRepo.Save(ctx, execution); err != nil {
+			return "", err
+		}
+	case err != nil:
+		return "", err
+	}
+
+	temporalWorkflowID := domain.TemporalWorkflowID(execution.ID)
+	if _, err := a.executionRepo.MarkRunning(ctx, execution.ID, temporalWorkflowID, ""); err != nil {
+		return "", err
+	}
+	return temporalWorkflowID, nil
+}
+
+// CompleteExecutionInput is the input for the CompleteExecution activity
+type CompleteExecutionInput struct {
+	TenantID    string      `json:"tenant_id"`
+	ExecutionID string      `json:"execution_id"`
+	Status      string      `json:"status"`
+	Output      interface{} `json:"output,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   time.Time   `json:"started_at"` // when the workflow run started
+}
+
+// CompleteExecution records the final status, output and error of an
+// execution. The run may finish before the API marks the execution running,
+// in which case the start time of the run is recorded as well.
+func (a *ExecutionActivities) CompleteExecution(ctx context.Context, input CompleteExecutionInput) error {
+	slog.Info("CompleteExecution activity", "execution_id", input.ExecutionID, "status", input.Status)
+
+	tenantID, err := uuid.Parse(input.TenantID)
+	if err != nil {
+		return executionError(fmt.Errorf("invalid tenant id: %w", err))
+	}
+	executionID, err := uuid.Parse(input.ExecutionID)
+	if err != nil {
+		return executionError(fmt.Errorf("invalid execution id: %w", err))
+	}
+
+	if err := a.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return err
+	}
+
+	execution, err := a.executionRepo.FindByID(ctx, executionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			return executionError(err)
+		}
+		return err
+	}
+
+	var output json.RawMessage
+	if input.Output != nil {
+		if output, err = json.Marshal(input.Output); err != nil {
+			return executionError(fmt.Errorf("invalid execution output: %w", err))
+		}
+	}
+
+	if execution.StartedAt == nil && !input.StartedAt.IsZero() {
+		startedAt := input.StartedAt
+		execution.StartedAt = &startedAt
+	}
+
+	switch domain.ExecutionStatus(input.Status) {
+	case domain.ExecutionStatusCompleted:
+		execution.MarkAsCompleted(output)
+	case domain.ExecutionStatusCancelled:
+		execution.MarkAsCancelled()
+		execution.Output = output
+	case domain.ExecutionStatusTimedOut:
+		execution.MarkAsTimedOut(input.Error)
+		execution.Output = output
+	default:
+		execution.MarkAsFailed(input.Error)
+		execution.Output = output
+	}
+
+	return a.executionRepo.Update(ctx, execution)
+}
+
+// RecordStepInput is the input for the RecordStep activity
+type RecordStepInput struct {
+	TenantID    string      `json:"tenant_id"`
+	ExecutionID string      `json:"execution_id"`
+	Position    int         `json:"position"`
+	StepID      string      `json:"step_id"`
+	StepName    string      `json:"step_name,omitempty"`
+	StepType    string      `json:"step_type"`
+	Status      string      `json:"status"`
+	Attempts    int32       `json:"attempts"`
+	Output      interface{} `json:"output,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   time.Time   `json:"started_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+}
+
+// RecordStep records the outcome of a top-level step on the execution's
+// step timeline
+func (a *ExecutionActivities) RecordStep(ctx context.Context, input RecordStepInput) error {
+	tenantID, err := uuid.Parse(input.TenantID)
+	if err != nil {
+		return executionError(fmt.Errorf("invalid tenant id: %w", err))
+	}
+	executionID, err := uuid.Parse(input.ExecutionID)
+	if err != nil {
+		return executionError(fmt.Errorf("invalid execution id: %w", err))
+	}
+
+	var output json.RawMessage
+	if input.Output != nil {
+		if output, err = json.Marshal(input.Output); err != nil {
+			return executionError(fmt.Errorf("invalid step output: %w", err))
+		}
+	}
+
+	if err := a.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return err
+	}
+
+	step := &domain.ExecutionStep{
+		TenantID:    tenantID,
+		ExecutionID: executionID,
+		Position:    int32(input.Position),
+		StepID:      input.StepID,
+		StepName:    input.StepName,
+		StepType:    input.StepType,
+		Status:      domain.ExecutionStepStatus(input.Status),
+		Attempts:    input.Attempts,
+		Output:      output,
+		StartedAt:   input.StartedAt,
+		CompletedAt: input.CompletedAt,
+	}
+	if input.Error != "" {
+		step.Error = stringPtr(input.Error)
+	}
+	return a.stepRepo.Save(ctx, step)
+}
+
+func (a *ExecutionActivities) findWorkflow(ctx context.Context, tenantID uuid.UUID, id, name string) (*domain.Workflow, error) {
+	if id == "" {
+		return a.workflowRepo.FindByName(ctx, tenantID, name)
+	}
+
+	workflowID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, executionError(fmt.Errorf("invalid workflow id: %w", err))
+	}
+	wf, err := a.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if wf.TenantID != tenantID {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	return wf, nil
+}
+
+func executionError(err error) error {
+	return temporal.NewNonRetryableApplicationError(err.Error(), ExecutionErrorType, err)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,201 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+)
+
+// flakyExecutionRepository fails the next MarkRunning when failNext is set,
+// like a connection dropped after the execution was saved
+type flakyExecutionRepository struct {
+	*mocks.MockExecutionRepository
+	failNext bool
+}
+
+func (r *flakyExecutionRepository) MarkRunning(ctx context.Context, id uuid.UUID, temporalWorkflowID, temporalRunID string) (*domain.Execution, error) {
+	if r.failNext {
+		r.failNext = false
+		return nil, errors.New("connection reset")
+	}
+	return r.MockExecutionRepository.MarkRunning(ctx, id, temporalWorkflowID, temporalRunID)
+}
+
+// startChild runs the StartChildExecution activity the way DynamicWorkflow
+// does, with retries
+func startChild(ctx workflow.Context, input StartChildExecutionInput) (*StartChildExecutionResult, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 3},
+	})
+	var result StartChildExecutionResult
+	if err := workflow.ExecuteActivity(ctx, "StartChildExecution", input).Get(ctx, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func TestStartChildExecution(t *testing.T) {
+	tenantID := uuid.New()
+	parentID := uuid.New()
+
+	setup := func(definition string) (*testsuite.TestWorkflowEnvironment, *flakyExecutionRepository, *domain.Workflow) {
+		var s testsuite.WorkflowTestSuite
+		env := s.NewTestWorkflowEnvironment()
+
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		child := &domain.Workflow{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			Name:       "child",
+			Definition: json.RawMessage(definition),
+			Status:     domain.WorkflowStatusActive,
+			Version:    1,
+		}
+		workflowRepo.AddWorkflow(child)
+		executionRepo := &flakyExecutionRepository{MockExecutionRepository: mocks.NewMockExecutionRepository()}
+
+		a := NewExecutionActivities(workflowRepo, executionRepo, nil, mocks.NewMockTenantContextSetter())
+		env.RegisterActivity(a.StartChildExecution)
+		env.RegisterWorkflow(startChild)
+		return env, executionRepo, child
+	}
+
+	t.Run("retries reuse the execution of the first attempt", func(t *testing.T) {
+		env, executionRepo, child := setup(`{"steps":[{"id":"a","type":"log"}]}`)
+		executionRepo.failNext = true
+
+		env.ExecuteWorkflow(startChild, StartChildExecutionInput{
+			TenantID:          tenantID.String(),
+			ParentExecutionID: parentID.String(),
+			WorkflowID:        child.ID.String(),
+		})
+
+		require.True(t, env.IsWorkflowCompleted())
+		require.NoError(t, env.GetWorkflowError())
+		var result StartChildExecutionResult
+		require.NoError(t, env.GetWorkflowResult(&result))
+
+		executions, err := executionRepo.FindByParent(context.Background(), parentID)
+		require.NoError(t, err)
+		require.Len(t, executions, 1)
+		assert.Equal(t, result.ExecutionID, executions[0].ID.String())
+		assert.Equal(t, domain.ExecutionStatusRunning, executions[0].Status)
+	})
+
+	t.Run("applies the child's input schema", func(t *testing.T) {
+		env, executionRepo, child := setup(`{
+			"inputs": {"properties": {"region": {"type": "string", "default": "eu-west-1"}, "hosts": {"type": "array"}}},
+			"steps": [{"id": "a", "type": "log"}]
+		}`)
+
+		env.ExecuteWorkflow(startChild, StartChildExecutionInput{
+			TenantID:          tenantID.String(),
+			ParentExecutionID: parentID.String(),
+			WorkflowID:        child.ID.String(),
+			Input:             map[string]interface{}{"hosts": []interface{}{"web-1"}},
+		})
+
+		require.NoError(t, env.GetWorkflowError())
+		var result StartChildExecutionResult
+		require.NoError(t, env.GetWorkflowResult(&result))
+		want := map[string]interface{}{"region": "eu-west-1", "hosts": []interface{}{"web-1"}}
+		assert.Equal(t, want, result.Input)
+
+		executions, err := executionRepo.FindByParent(context.Background(), parentID)
+		require.NoError(t, err)
+		require.Len(t, executions, 1)
+		assert.JSONEq(t, `{"region": "eu-west-1", "hosts": ["web-1"]}`, string(executions[0].Input))
+	})
+
+	t.Run("rejects input that doesn't match the schema", func(t *testing.T) {
+		env, executionRepo, child := setup(`{
+			"inputs": {"properties": {"region": {"type": "string"}}, "required": ["region"]},
+			"steps": [{"id": "a", "type": "log"}]
+		}`)
+
+		env.ExecuteWorkflow(startChild, StartChildExecutionInput{
+			TenantID:          tenantID.String(),
+			ParentExecutionID: parentID.String(),
+			WorkflowID:        child.ID.String(),
+		})
+
+		err := env.GetWorkflowError()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid workflow input: region")
+		var appErr *temporal.ApplicationError
+		require.True(t, errors.As(err, &appErr))
+		assert.True(t, appErr.NonRetryable())
+
+		executions, err := executionRepo.FindByParent(context.Background(), parentID)
+		require.NoError(t, err)
+		assert.Empty(t, executions)
+	})
+}
+
+func startScheduled(ctx workflow.Context, input StartScheduledExecutionInput) (*StartScheduledExecutionResult, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{InitialInterval: time.Second, MaximumAttempts: 3},
+	})
+	var result StartScheduledExecutionResult
+	if err := workflow.ExecuteActivity(ctx, "StartScheduledExecution", input).Get(ctx, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func TestStartScheduledExecution(t *testing.T) {
+	tenantID := uuid.New()
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+
+	workflowRepo := mocks.NewMockWorkflowRepository()
+	wf := &domain.Workflow{
+		ID:       uuid.New(),
+		TenantID: tenantID,
+		Name:     "nightly",
+		Definition: json.RawMessage(`{
+			"inputs": {"properties": {"region": {"type": "string", "default": "eu-west-1"}}},
+			"steps": [{"id": "a", "type": "log"}]
+		}`),
+		Status:  domain.WorkflowStatusActive,
+		Version: 1,
+	}
+	workflowRepo.AddWorkflow(wf)
+	executionRepo := &flakyExecutionRepository{MockExecutionRepository: mocks.NewMockExecutionRepository(), failNext: true}
+
+	a := NewExecutionActivities(workflowRepo, executionRepo, nil, mocks.NewMockTenantContextSetter())
+	env.RegisterActivity(a.StartScheduledExecution)
+	env.RegisterWorkflow(startScheduled)
+
+	env.ExecuteWorkflow(startScheduled, StartScheduledExecutionInput{
+		TenantID:   tenantID.String(),
+		WorkflowID: wf.ID.String(),
+		ScheduleID: "workflow-" + wf.ID.String(),
+	})
+
+	require.NoError(t, env.GetWorkflowError())
+	var result StartScheduledExecutionResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1"}, result.Input)
+
+	// The retry after the failed MarkRunning reuses the saved execution
+	executions, err := executionRepo.FindByWorkflow(context.Background(), wf.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	assert.Equal(t, result.ExecutionID, executions[0].ID.String())
+	assert.Equal(t, domain.ExecutionStatusRunning, executions[0].Status)
+}
@@ -0,0 +1,309 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error types of HTTP activities whose response fails an extract or assert
+// rule, and of rules that can't be evaluated
+const (
+	HTTPCheckErrorType = "HTTPCheckFailed"
+	HTTPRuleErrorType  = "HTTPInvalidRule"
+)
+
+// HTTPExtract copies a value from an HTTP response into the extracted
+// values of the step output, under Name. Exactly one source is set.
+type HTTPExtract struct {
+	Name     string `json:"name"`
+	JSONPath string `json:"jsonpath,omitempty"` // e.g. "$.build.version", against the JSON body
+	Regex    string `json:"regex,omitempty"`    // against the body; the first capture group, or the whole match
+	Header   string `json:"header,omitempty"`   // response header value
+}
+
+// HTTPAssert is a condition an HTTP response must meet for the step to
+// succeed. Exactly one of JSONPath, LatencyBelow and Header is set.
+type HTTPAssert struct {
+	JSONPath     string      `json:"jsonpath,omitempty"`      // body field that must exist, and equal Equals when set
+	Equals       interface{} `json:"equals,omitempty"`        // expected value of the JSONPath field
+	LatencyBelow string      `json:"latency_below,omitempty"` // e.g. "500ms"
+	Header       string      `json:"header,omitempty"`        // header that must be present
+}
+
+// Check reports whether the rule is well formed
+func (e HTTPExtract) Check() error {
+	if e.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if countSet(e.JSONPath, e.Regex, e.Header) != 1 {
+		return fmt.Errorf("exactly one of jsonpath, regex or header is required")
+	}
+	if e.JSONPath != "" {
+		if _, err := parseJSONPath(e.JSONPath); err != nil {
+			return err
+		}
+	}
+	if e.Regex != "" {
+		if _, err := regexp.Compile(e.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// Check reports whether the rule is well formed
+func (a HTTPAssert) Check() error {
+	if countSet(a.JSONPath, a.LatencyBelow, a.Header) != 1 {
+		return fmt.Errorf("exactly one of jsonpath, latency_below or header is required")
+	}
+	if a.Equals != nil && a.JSONPath == "" {
+		return fmt.Errorf("equals requires jsonpath")
+	}
+	if a.JSONPath != "" {
+		if _, err := parseJSONPath(a.JSONPath); err != nil {
+			return err
+		}
+	}
+	if a.LatencyBelow != "" {
+		if d, err := time.ParseDuration(a.LatencyBelow); err != nil || d <= 0 {
+			return fmt.Errorf("invalid latency_below %q", a.LatencyBelow)
+		}
+	}
+	return nil
+}
+
+func countSet(values ...string) int {
+	n := 0
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// CheckHTTPResponse applies extract and assert rules to a response. It
+// returns the extracted values by name, or an error naming the values that
+// couldn't be extracted or every assertion that failed. Rules must have
+// passed Check.
+func CheckHTTPResponse(extract []HTTPExtract, assert []HTTPAssert, body string, headers map[string]string, latency time.Duration) (map[string]interface{}, error) {
+	response := &httpResponse{body: body, headers: headers}
+
+	var extracted map[string]interface{}
+	if len(extract) > 0 {
+		extracted = make(map[string]interface{}, len(extract))
+	}
+	for _, rule := range extract {
+		value, err := response.extract(rule)
+		if err != nil {
+			return nil, fmt.Errorf("extract %s: %w", rule.Name, err)
+		}
+		extracted[rule.Name] = value
+	}
+
+	var failures []string
+	for _, rule := range assert {
+		if msg := response.assert(rule, latency); msg != "" {
+			failures = append(failures, msg)
+		}
+	}
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("assertion failed: %s", strings.Join(failures, "; "))
+	}
+	return extracted, nil
+}
+
+// httpResponse decodes the body once, on the first JSONPath rule
+type httpResponse struct {
+	body    string
+	headers map[string]string
+	decoded bool
+	json    interface{}
+	jsonErr error
+}
+
+func (r *httpResponse) bodyJSON() (interface{}, error) {
+	if !r.decoded {
+		r.decoded = true
+		r.jsonErr = json.Unmarshal([]byte(r.body), &r.json)
+		if r.jsonErr != nil {
+			r.jsonErr = fmt.Errorf("body is not JSON")
+		}
+	}
+	return r.json, r.jsonErr
+}
+
+// header looks a header up case-insensitively
+func (r *httpResponse) header(name string) (string, bool) {
+	if v, ok := r.headers[name]; ok {
+		return v, true
+	}
+	for k, v := range r.headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func (r *httpResponse) field(path string) (interface{}, error) {
+	p, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := r.bodyJSON()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := p.lookup(doc)
+	if !ok {
+		return nil, fmt.Errorf("%s not found in body", path)
+	}
+	return value, nil
+}
+
+func (r *httpResponse) extract(rule HTTPExtract) (interface{}, error) {
+	switch {
+	case rule.JSONPath != "":
+		return r.field(rule.JSONPath)
+	case rule.Regex != "":
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		match := re.FindStringSubmatch(r.body)
+		if match == nil {
+			return nil, fmt.Errorf("regex %q doesn't match the body", rule.Regex)
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	default:
+		value, ok := r.header(rule.Header)
+		if !ok {
+			return nil, fmt.Errorf("header %s not found", rule.Header)
+		}
+		return value, nil
+	}
+}
+
+// assert returns why the response fails the rule, or "" when it passes
+func (r *httpResponse) assert(rule HTTPAssert, latency time.Duration) string {
+	switch {
+	case rule.JSONPath != "":
+		value, err := r.field(rule.JSONPath)
+		if err != nil {
+			return err.Error()
+		}
+		if rule.Equals != nil && !jsonEqual(value, rule.Equals) {
+			return fmt.Sprintf("%s is %s, expected %s", rule.JSONPath, jsonString(value), jsonString(rule.Equals))
+		}
+	case rule.LatencyBelow != "":
+		limit, err := time.ParseDuration(rule.LatencyBelow)
+		if err != nil {
+			return fmt.Sprintf("invalid latency_below %q", rule.LatencyBelow)
+		}
+		if latency >= limit {
+			return fmt.Sprintf("latency %s is not below %s", latency.Round(time.Millisecond), limit)
+		}
+	default:
+		if _, ok := r.header(rule.Header); !ok {
+			return fmt.Sprintf("header %s is missing", rule.Header)
+		}
+	}
+	return ""
+}
+
+// jsonEqual compares values as JSON, so 1 and 1.0 are equal
+func jsonEqual(a, b interface{}) bool {
+	var na, nb interface{}
+	if json.Unmarshal([]byte(jsonString(a)), &na) != nil || json.Unmarshal([]byte(jsonString(b)), &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// jsonPath is a parsed JSONPath of the supported subset: "$" followed by
+// .name, ['name'] and [index] segments. Segments are string keys or int
+// indexes.
+type jsonPath []interface{}
+
+func parseJSONPath(s string) (jsonPath, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("invalid jsonpath %q: must start with $", s)
+	}
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid jsonpath %q: %s", s, reason)
+	}
+
+	var path jsonPath
+	for i := 1; i < len(s); {
+		switch s[i] {
+		case '.':
+			end := i + 1
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			if end == i+1 {
+				return nil, invalid("empty name")
+			}
+			path = append(path, s[i+1:end])
+			i = end
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, invalid("unclosed [")
+			}
+			inner := s[i+1 : i+end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				path = append(path, inner[1:len(inner)-1])
+			} else if n, err := strconv.Atoi(inner); err == nil && n >= 0 {
+				path = append(path, n)
+			} else {
+				return nil, invalid("brackets must hold a quoted name or an index")
+			}
+			i += end + 1
+		default:
+			return nil, invalid(fmt.Sprintf("unexpected %q", s[i]))
+		}
+	}
+	return path, nil
+}
+
+// lookup resolves the path in a decoded JSON document
+func (p jsonPath) lookup(doc interface{}) (interface{}, bool) {
+	current := doc
+	for _, segment := range p {
+		switch key := segment.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || key >= len(arr) {
+				return nil, false
+			}
+			current = arr[key]
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,120 @@
+package activity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const healthBody = `{"status": "ok", "build": {"version": "1.4.2"}, "checks": [{"name": "db", "ok": true}], "uptime": 3600}`
+
+func TestCheckHTTPResponse(t *testing.T) {
+	headers := map[string]string{"X-Request-Id": "req-42"}
+
+	t.Run("extracts values", func(t *testing.T) {
+		extracted, err := CheckHTTPResponse([]HTTPExtract{
+			{Name: "version", JSONPath: "$.build.version"},
+			{Name: "db", JSONPath: "$.checks[0]['ok']"},
+			{Name: "uptime", Regex: `"uptime": (\d+)`},
+			{Name: "request_id", Header: "x-request-id"},
+		}, nil, healthBody, headers, time.Millisecond)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"version":    "1.4.2",
+			"db":         true,
+			"uptime":     "3600",
+			"request_id": "req-42",
+		}, extracted)
+	})
+
+	t.Run("fails when a value can't be extracted", func(t *testing.T) {
+		_, err := CheckHTTPResponse([]HTTPExtract{{Name: "commit", JSONPath: "$.build.commit"}}, nil, healthBody, headers, 0)
+
+		assert.ErrorContains(t, err, "extract commit: $.build.commit not found in body")
+	})
+
+	t.Run("passes assertions", func(t *testing.T) {
+		_, err := CheckHTTPResponse(nil, []HTTPAssert{
+			{JSONPath: "$.status", Equals: "ok"},
+			{JSONPath: "$.uptime", Equals: 3600},
+			{JSONPath: "$.build"},
+			{LatencyBelow: "500ms"},
+			{Header: "X-Request-Id"},
+		}, healthBody, headers, 120*time.Millisecond)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports every failed assertion", func(t *testing.T) {
+		_, err := CheckHTTPResponse(nil, []HTTPAssert{
+			{JSONPath: "$.status", Equals: "degraded"},
+			{LatencyBelow: "100ms"},
+			{Header: "X-Version"},
+		}, healthBody, headers, 120*time.Millisecond)
+
+		assert.ErrorContains(t, err, `assertion failed: $.status is "ok", expected "degraded"; `+
+			`latency 120ms is not below 100ms; header X-Version is missing`)
+	})
+
+	t.Run("fails JSONPath rules on bodies that aren't JSON", func(t *testing.T) {
+		_, err := CheckHTTPResponse(nil, []HTTPAssert{{JSONPath: "$.status"}}, "OK", nil, 0)
+
+		assert.ErrorContains(t, err, "assertion failed: body is not JSON")
+	})
+}
+
+func TestHTTPRules_Check(t *testing.T) {
+	assert.NoError(t, HTTPExtract{Name: "v", JSONPath: `$["build"].items[2]`}.Check())
+	assert.ErrorContains(t, HTTPExtract{JSONPath: "$.a"}.Check(), "name is required")
+	assert.ErrorContains(t, HTTPExtract{Name: "v", JSONPath: "build.version"}.Check(), `invalid jsonpath "build.version": must start with $`)
+	assert.ErrorContains(t, HTTPExtract{Name: "v", JSONPath: "$.items[-1]"}.Check(), `invalid jsonpath "$.items[-1]": brackets must hold a quoted name or an index`)
+	assert.Error(t, HTTPExtract{Name: "v", Regex: "("}.Check())
+	assert.ErrorContains(t, HTTPAssert{Header: "X-Id", LatencyBelow: "1s"}.Check(), "exactly one of jsonpath, latency_below or header is required")
+	assert.ErrorContains(t, HTTPAssert{Header: "X-Id", Equals: "a"}.Check(), "equals requires jsonpath")
+	assert.ErrorContains(t, HTTPAssert{LatencyBelow: "fast"}.Check(), `invalid latency_below "fast"`)
+}
+
+func TestHTTP_Checks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-42")
+		_, _ = w.Write([]byte(healthBody))
+	}))
+	defer server.Close()
+	a := NewActivities()
+
+	t.Run("returns extracted values", func(t *testing.T) {
+		result, err := a.HTTP(context.Background(), HTTPInput{
+			URL:     server.URL,
+			Extract: []HTTPExtract{{Name: "version", JSONPath: "$.build.version"}},
+			Assert:  []HTTPAssert{{JSONPath: "$.status", Equals: "ok"}, {LatencyBelow: "10s"}},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, map[string]interface{}{"version": "1.4.2"}, result.Extracted)
+	})
+
+	t.Run("fails on a failed assertion", func(t *testing.T) {
+		_, err := a.HTTP(context.Background(), HTTPInput{
+			URL:    server.URL,
+			Assert: []HTTPAssert{{JSONPath: "$.status", Equals: "degraded"}},
+		})
+
+		assert.ErrorContains(t, err, `$.status is "ok", expected "degraded"`)
+	})
+
+	t.Run("fails on a failed request when rules are set", func(t *testing.T) {
+		_, err := a.HTTP(context.Background(), HTTPInput{
+			URL:    "http://127.0.0.1:1",
+			Assert: []HTTPAssert{{Header: "X-Request-Id"}},
+		})
+
+		assert.ErrorContains(t, err, "request failed")
+	})
+}
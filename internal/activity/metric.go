@@ -0,0 +1,158 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+// MetricActivities holds activities that read the metrics store
+type MetricActivities struct {
+	metricRepo   port.MetricRepository
+	tenantSetter port.TenantContextSetter
+}
+
+// NewMetricActivities creates a new MetricActivities instance
+func NewMetricActivities(metricRepo port.MetricRepository, tenantSetter port.TenantContextSetter) *MetricActivities {
+	return &MetricActivities{
+		metricRepo:   metricRepo,
+		tenantSetter: tenantSetter,
+	}
+}
+
+// CheckMetricInput is the input for the CheckMetric activity
+type CheckMetricInput struct {
+	TenantID    string            `json:"tenant_id"`
+	Metric      string            `json:"metric"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Aggregation string            `json:"aggregation"` // avg, sum, min, max, count, p50, p95, p99 or last
+	Window      string            `json:"window"`      // e.g., "5m", ignored for last
+	Operator    string            `json:"operator"`    // <, <=, >, >=, ==, !=
+	Threshold   float64           `json:"threshold"`
+}
+
+// CheckMetricResult is a single observation of a metric condition
+type CheckMetricResult struct {
+	Value     *float64  `json:"value"` // nil when there is no data
+	Samples   int64     `json:"samples"`
+	Satisfied bool      `json:"satisfied"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CheckMetric evaluates a metric condition once against the metrics store
+func (a *MetricActivities) CheckMetric(ctx context.Context, input CheckMetricInput) (*CheckMetricResult, error) {
+	tenantID, err := uuid.Parse(input.TenantID)
+	if err != nil {
+		return nil, executionError(fmt.Errorf("invalid tenant id: %w", err))
+	}
+
+	if err := a.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := &CheckMetricResult{CheckedAt: now}
+
+	if domain.AggregationType(input.Aggregation) == domain.AggregationLast {
+		metric, err := a.metricRepo.FindLatest(ctx, tenantID, input.Metric, input.Labels)
+		if err != nil && !errors.Is(err, domain.ErrMetricNotFound) {
+			return nil, err
+		}
+		if metric != nil {
+			result.Value = &metric.Value
+			result.Samples = 1
+		}
+	} else {
+		window, err := time.ParseDuration(input.Window)
+		if err != nil {
+			return nil, executionError(fmt.Errorf("invalid window %q: %w", input.Window, err))
+		}
+
+		agg, err := a.metricRepo.GetAggregate(ctx, domain.MetricQuery{
+			TenantID:  tenantID,
+			Name:      input.Metric,
+			Labels:    input.Labels,
+			StartTime: now.Add(-window),
+			EndTime:   now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Samples = agg.Count
+		if agg.Count > 0 {
+			value, err := aggregateValue(agg, domain.AggregationType(input.Aggregation))
+			if err != nil {
+				return nil, executionError(err)
+			}
+			result.Value = value
+		}
+	}
+
+	if result.Value != nil {
+		satisfied, err := CompareMetric(*result.Value, input.Operator, input.Threshold)
+		if err != nil {
+			return nil, executionError(err)
+		}
+		result.Satisfied = satisfied
+	}
+
+	slog.Info("CheckMetric activity",
+		"metric", input.Metric,
+		"aggregation", input.Aggregation,
+		"samples", result.Samples,
+		"satisfied", result.Satisfied)
+
+	return result, nil
+}
+
+// CompareMetric compares a metric value against a threshold
+func CompareMetric(value float64, operator string, threshold float64) (bool, error) {
+	switch operator {
+	case "<":
+		return value < threshold, nil
+	case "<=":
+		return value <= threshold, nil
+	case ">":
+		return value > threshold, nil
+	case ">=":
+		return value >= threshold, nil
+	case "==":
+		return value == threshold, nil
+	case "!=":
+		return value != threshold, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", operator)
+	}
+}
+
+func aggregateValue(agg *domain.MetricAggregate, aggregation domain.AggregationType) (*float64, error) {
+	var value float64
+	switch aggregation {
+	case domain.AggregationAvg:
+		value = agg.Average
+	case domain.AggregationSum:
+		value = agg.Sum
+	case domain.AggregationMin:
+		value = agg.Min
+	case domain.AggregationMax:
+		value = agg.Max
+	case domain.AggregationCount:
+		value = float64(agg.Count)
+	case domain.AggregationP50:
+		return agg.P50, nil
+	case domain.AggregationP95:
+		return agg.P95, nil
+	case domain.AggregationP99:
+		return agg.P99, nil
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %q", aggregation)
+	}
+	return &value, nil
+}
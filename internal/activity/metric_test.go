@@ -0,0 +1,77 @@
+package activity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+)
+
+func TestCheckMetric(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	t.Run("compares the aggregate over the window", func(t *testing.T) {
+		metricRepo := mocks.NewMockMetricRepository()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+		a := NewMetricActivities(metricRepo, tenantSetter)
+
+		result, err := a.CheckMetric(ctx, CheckMetricInput{
+			TenantID:    tenantID.String(),
+			Metric:      "cpu_usage",
+			Aggregation: "avg",
+			Window:      "5m",
+			Operator:    "<",
+			Threshold:   70,
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, result.Value)
+		assert.Equal(t, 50.5, *result.Value)
+		assert.Equal(t, int64(100), result.Samples)
+		assert.True(t, result.Satisfied)
+		assert.True(t, tenantSetter.SetCalled)
+	})
+
+	t.Run("uses the latest value", func(t *testing.T) {
+		metricRepo := mocks.NewMockMetricRepository()
+		metricRepo.AddMetric(&domain.Metric{TenantID: tenantID, Name: "cpu_usage", Value: 92})
+		a := NewMetricActivities(metricRepo, mocks.NewMockTenantContextSetter())
+
+		result, err := a.CheckMetric(ctx, CheckMetricInput{
+			TenantID:    tenantID.String(),
+			Metric:      "cpu_usage",
+			Aggregation: "last",
+			Operator:    "<",
+			Threshold:   70,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 92.0, *result.Value)
+		assert.False(t, result.Satisfied)
+	})
+
+	t.Run("no data is not satisfied", func(t *testing.T) {
+		metricRepo := mocks.NewMockMetricRepository()
+		metricRepo.Aggregate = &domain.MetricAggregate{}
+		a := NewMetricActivities(metricRepo, mocks.NewMockTenantContextSetter())
+
+		result, err := a.CheckMetric(ctx, CheckMetricInput{
+			TenantID:    tenantID.String(),
+			Metric:      "cpu_usage",
+			Aggregation: "max",
+			Window:      "5m",
+			Operator:    "<",
+			Threshold:   70,
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Value)
+		assert.False(t, result.Satisfied)
+	})
+}
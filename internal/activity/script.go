@@ -0,0 +1,229 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"runtime/metrics"
+	"time"
+
+	"github.com/dop251/goja"
+	"go.temporal.io/sdk/temporal"
+)
+
+// ScriptLanguageJavaScript is the only supported script language
+const ScriptLanguageJavaScript = "javascript"
+
+// Script sandbox limits
+const (
+	DefaultScriptTimeout     = 5 * time.Second
+	MaxScriptTimeout         = time.Minute
+	DefaultScriptMaxMemoryMB = 64
+	MaxScriptMemoryMB        = 256
+	MaxScriptCodeBytes       = 64 * 1024
+	MaxScriptOutputBytes     = 1024 * 1024
+	scriptMaxCallStackSize   = 1024
+	scriptMemoryCheckPeriod  = 10 * time.Millisecond
+)
+
+// ScriptErrorType is the Temporal error type of script failures. Script
+// errors are not retried since the same code and input fail the same way.
+const ScriptErrorType = "ScriptError"
+
+var (
+	errScriptTimeout = errors.New("script exceeded its time limit")
+	errScriptMemory  = errors.New("script exceeded its memory limit")
+)
+
+// ScriptInput is the input for the Script activity
+type ScriptInput struct {
+	Language    string                 `json:"language"`
+	Code        string                 `json:"code"`
+	Input       map[string]interface{} `json:"input,omitempty"`
+	Steps       map[string]interface{} `json:"steps,omitempty"`
+	Now         time.Time              `json:"now"`  // value returned by Date.now()
+	Seed        int64                  `json:"seed"` // seed for Math.random()
+	TimeoutMs   int64                  `json:"timeout_ms,omitempty"`
+	MaxMemoryMB int                    `json:"max_memory_mb,omitempty"`
+}
+
+// ScriptResult is the result of the Script activity
+type ScriptResult struct {
+	Output     interface{} `json:"output"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// scriptLimits returns the time and memory limits of a script, defaulted
+// when unset and capped at MaxScriptTimeout and MaxScriptMemoryMB
+func scriptLimits(input ScriptInput) (time.Duration, int) {
+	timeout := time.Duration(input.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = DefaultScriptTimeout
+	}
+	if timeout > MaxScriptTimeout {
+		timeout = MaxScriptTimeout
+	}
+	maxMemoryMB := input.MaxMemoryMB
+	if maxMemoryMB <= 0 {
+		maxMemoryMB = DefaultScriptMaxMemoryMB
+	}
+	if maxMemoryMB > MaxScriptMemoryMB {
+		maxMemoryMB = MaxScriptMemoryMB
+	}
+	return timeout, maxMemoryMB
+}
+
+// Script runs JavaScript code in an isolated interpreter. The code is the body
+// of a function receiving `input` and `steps` and its return value, which must
+// be JSON serializable, becomes the step output. The interpreter has no access
+// to the filesystem, network or host environment, and the clock and random
+// source are fixed by the caller so that the same input gives the same output.
+func (a *Activities) Script(ctx context.Context, input ScriptInput) (*ScriptResult, error) {
+	slog.Info("Script activity started", "language", input.Language, "code_bytes", len(input.Code))
+
+	if input.Language != "" && input.Language != ScriptLanguageJavaScript {
+		return nil, scriptError(fmt.Errorf("unsupported script language: %s", input.Language))
+	}
+	if len(input.Code) > MaxScriptCodeBytes {
+		return nil, scriptError(fmt.Errorf("script code exceeds %d bytes", MaxScriptCodeBytes))
+	}
+
+	timeout, maxMemoryMB := scriptLimits(input)
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(scriptMaxCallStackSize)
+	now := input.Now
+	vm.SetTimeSource(func() time.Time { return now })
+	vm.SetRandSource(rand.New(rand.NewSource(input.Seed)).Float64)
+
+	start := time.Now()
+
+	timer := time.AfterFunc(timeout, func() { vm.Interrupt(errScriptTimeout) })
+	defer timer.Stop()
+	stopCancel := context.AfterFunc(ctx, func() { vm.Interrupt(ctx.Err()) })
+	defer stopCancel()
+	done := make(chan struct{})
+	defer close(done)
+	go watchScriptMemory(vm, uint64(maxMemoryMB)*1024*1024, done)
+
+	value, err := runScript(vm, input)
+	if err != nil {
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok && (errors.Is(cause, context.Canceled) || errors.Is(cause, context.DeadlineExceeded)) {
+				return nil, cause
+			}
+		}
+		return nil, scriptError(err)
+	}
+
+	output, err := exportScriptValue(value)
+	if err != nil {
+		return nil, scriptError(err)
+	}
+
+	duration := time.Since(start)
+	slog.Info("Script activity completed", "duration_ms", duration.Milliseconds())
+
+	return &ScriptResult{
+		Output:     output,
+		DurationMs: duration.Milliseconds(),
+	}, nil
+}
+
+// runScript compiles the code as a function body and calls it with the
+// input and step state converted to native JavaScript values
+func runScript(vm *goja.Runtime, input ScriptInput) (goja.Value, error) {
+	fnValue, err := vm.RunScript("script", "(function(input, steps) {\n"+input.Code+"\n})")
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := goja.AssertFunction(fnValue)
+	if !ok {
+		return nil, fmt.Errorf("script did not compile to a function")
+	}
+
+	jsInput, err := toScriptValue(vm, input.Input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script input: %w", err)
+	}
+	jsSteps, err := toScriptValue(vm, input.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script steps: %w", err)
+	}
+
+	return fn(goja.Undefined(), jsInput, jsSteps)
+}
+
+// toScriptValue converts a Go value into a plain JavaScript value through JSON
+// so that scripts get regular objects and arrays rather than Go wrappers
+func toScriptValue(vm *goja.Runtime, v interface{}) (goja.Value, error) {
+	if v == nil {
+		return vm.NewObject(), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	parse, ok := goja.AssertFunction(vm.Get("JSON").ToObject(vm).Get("parse"))
+	if !ok {
+		return nil, fmt.Errorf("JSON.parse is not available")
+	}
+	return parse(goja.Undefined(), vm.ToValue(string(data)))
+}
+
+// exportScriptValue converts the script result into a JSON value
+func exportScriptValue(v goja.Value) (interface{}, error) {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v.Export())
+	if err != nil {
+		return nil, fmt.Errorf("script result is not JSON serializable: %w", err)
+	}
+	if len(data) > MaxScriptOutputBytes {
+		return nil, fmt.Errorf("script result exceeds %d bytes", MaxScriptOutputBytes)
+	}
+	var output interface{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// watchScriptMemory interrupts the script when the heap grows by more than
+// limit bytes while it runs. The heap is shared by the whole worker process,
+// so this is an approximation that guards against runaway allocations.
+func watchScriptMemory(vm *goja.Runtime, limit uint64, done <-chan struct{}) {
+	baseline := heapObjectBytes()
+	ticker := time.NewTicker(scriptMemoryCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if current := heapObjectBytes(); current > baseline && current-baseline > limit {
+				vm.Interrupt(errScriptMemory)
+				return
+			}
+		}
+	}
+}
+
+func heapObjectBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
+
+func scriptError(err error) error {
+	return temporal.NewNonRetryableApplicationError(fmt.Sprintf("script failed: %v", err), ScriptErrorType, err)
+}
@@ -0,0 +1,105 @@
+package activity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScript(t *testing.T) {
+	a := NewActivities()
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	t.Run("transforms input and step outputs", func(t *testing.T) {
+		result, err := a.Script(context.Background(), ScriptInput{
+			Code: `
+				const pods = steps.list_pods.output.items.filter(p => p.ready === false);
+				return { host: input.host, unhealthy: pods.map(p => p.name), count: pods.length };
+			`,
+			Input: map[string]interface{}{"host": "db-1"},
+			Steps: map[string]interface{}{
+				"list_pods": map[string]interface{}{
+					"output": map[string]interface{}{
+						"items": []interface{}{
+							map[string]interface{}{"name": "a", "ready": true},
+							map[string]interface{}{"name": "b", "ready": false},
+						},
+					},
+				},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"host":      "db-1",
+			"unhealthy": []interface{}{"b"},
+			"count":     float64(1),
+		}, result.Output)
+	})
+
+	t.Run("clock and random source are deterministic", func(t *testing.T) {
+		input := ScriptInput{
+			Code: "return [Date.now(), Math.random()];",
+			Now:  now,
+			Seed: 42,
+		}
+
+		first, err := a.Script(context.Background(), input)
+		require.NoError(t, err)
+		second, err := a.Script(context.Background(), input)
+		require.NoError(t, err)
+
+		assert.Equal(t, first.Output, second.Output)
+		assert.Equal(t, float64(now.UnixMilli()), first.Output.([]interface{})[0])
+	})
+
+	t.Run("no return gives null output", func(t *testing.T) {
+		result, err := a.Script(context.Background(), ScriptInput{Code: "const x = 1;"})
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Output)
+	})
+
+	t.Run("host environment is not reachable", func(t *testing.T) {
+		_, err := a.Script(context.Background(), ScriptInput{Code: "return require('fs');"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("thrown error fails the script", func(t *testing.T) {
+		_, err := a.Script(context.Background(), ScriptInput{Code: "throw new Error('boom');"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "boom")
+	})
+
+	t.Run("infinite loop is interrupted", func(t *testing.T) {
+		_, err := a.Script(context.Background(), ScriptInput{Code: "while (true) {}", TimeoutMs: 50})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "time limit")
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		_, err := a.Script(context.Background(), ScriptInput{Language: "python", Code: "return 1"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestScriptLimits(t *testing.T) {
+	timeout, memory := scriptLimits(ScriptInput{})
+	assert.Equal(t, DefaultScriptTimeout, timeout)
+	assert.Equal(t, DefaultScriptMaxMemoryMB, memory)
+
+	timeout, memory = scriptLimits(ScriptInput{TimeoutMs: 10 * 60 * 1000, MaxMemoryMB: 4096})
+	assert.Equal(t, MaxScriptTimeout, timeout)
+	assert.Equal(t, MaxScriptMemoryMB, memory)
+
+	timeout, memory = scriptLimits(ScriptInput{TimeoutMs: 500, MaxMemoryMB: 128})
+	assert.Equal(t, 500*time.Millisecond, timeout)
+	assert.Equal(t, 128, memory)
+}
@@ -0,0 +1,255 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+// SecretErrorType is the Temporal error type of activities referencing a
+// secret that can't be resolved: unknown, undecryptable, or secrets aren't
+// configured on the worker
+const SecretErrorType = "SecretUnavailable"
+
+// redactedSecret replaces secret values in activity results
+const redactedSecret = "[REDACTED]"
+
+// secretReference matches ${secrets.NAME} and the escaped $${secrets.NAME}
+var secretReference = regexp.MustCompile(`\$?\$\{\s*secrets\.([A-Za-z_][A-Za-z0-9_]*)\s*\}`)
+
+// SecretResolver decrypts the secrets referenced by an activity input. It
+// runs only in the worker, at activity time, so secret values never reach
+// workflow history, executions or step results.
+type SecretResolver struct {
+	secretRepo   port.SecretRepository
+	cipher       port.SecretCipher
+	tenantSetter port.TenantContextSetter
+}
+
+// NewSecretResolver creates a new SecretResolver
+func NewSecretResolver(
+	secretRepo port.SecretRepository,
+	cipher port.SecretCipher,
+	tenantSetter port.TenantContextSetter,
+) *SecretResolver {
+	return &SecretResolver{
+		secretRepo:   secretRepo,
+		cipher:       cipher,
+		tenantSetter: tenantSetter,
+	}
+}
+
+// secretValues holds the values resolved for one activity, by name
+type secretValues map[string]string
+
+// secretRenderer replaces the secret references of one activity input,
+// keeping the first error
+type secretRenderer struct {
+	ctx      context.Context
+	resolver *SecretResolver
+	tenantID string
+	tenant   *uuid.UUID // set on the first lookup
+	values   secretValues
+	err      error
+}
+
+// resolveSecrets replaces the secret references in the URL, header values
+// and body of input, and returns the values used so results can be
+// redacted. Escaped references become a literal ${secrets.NAME}.
+func (a *Activities) resolveSecrets(ctx context.Context, input *HTTPInput) (secretValues, error) {
+	if !input.referencesSecrets() {
+		return nil, nil
+	}
+
+	rr := &secretRenderer{ctx: ctx, resolver: a.Secrets, tenantID: input.TenantID, values: secretValues{}}
+	input.URL = rr.render(input.URL)
+	if input.Headers != nil {
+		headers := make(map[string]string, len(input.Headers))
+		for k, v := range input.Headers {
+			headers[k] = rr.render(v)
+		}
+		input.Headers = headers
+	}
+	input.Body = rr.renderValue(input.Body)
+	if rr.err != nil {
+		return nil, rr.err
+	}
+	return rr.values, nil
+}
+
+func (rr *secretRenderer) render(s string) string {
+	if !strings.Contains(s, "secrets.") {
+		return s
+	}
+	return secretReference.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		name := secretReference.FindStringSubmatch(ref)[1]
+		value, err := rr.lookup(name)
+		if err != nil {
+			if rr.err == nil {
+				rr.err = err
+			}
+			return ref
+		}
+		return value
+	})
+}
+
+func (rr *secretRenderer) renderValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return rr.render(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = rr.renderValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = rr.renderValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func (rr *secretRenderer) lookup(name string) (string, error) {
+	if value, ok := rr.values[name]; ok {
+		return value, nil
+	}
+	if rr.resolver == nil {
+		return "", temporal.NewNonRetryableApplicationError("secrets are not configured on this worker", SecretErrorType, domain.ErrSecretsNotConfigured)
+	}
+	if rr.tenant == nil {
+		tenantID, err := uuid.Parse(rr.tenantID)
+		if err != nil {
+			return "", temporal.NewNonRetryableApplicationError("secrets need the tenant of the execution", SecretErrorType, err)
+		}
+		if err := rr.resolver.tenantSetter.SetTenantContext(rr.ctx, tenantID); err != nil {
+			return "", err
+		}
+		rr.tenant = &tenantID
+	}
+
+	secret, err := rr.resolver.secretRepo.FindByName(rr.ctx, *rr.tenant, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("secret %q not found", name), SecretErrorType, err)
+		}
+		return "", fmt.Errorf("failed to load secret %q: %w", name, err)
+	}
+	plaintext, err := rr.resolver.cipher.Decrypt(secret.EncryptedValue, secret.EncryptedKey)
+	if err != nil {
+		return "", temporal.NewNonRetryableApplicationError(fmt.Sprintf("failed to decrypt secret %q", name), SecretErrorType, err)
+	}
+	rr.values[name] = string(plaintext)
+	return rr.values[name], nil
+}
+
+// referencesSecrets reports whether the URL, header values or body of an
+// HTTP input reference a secret
+func (input *HTTPInput) referencesSecrets() bool {
+	if secretReference.MatchString(input.URL) {
+		return true
+	}
+	for _, v := range input.Headers {
+		if secretReference.MatchString(v) {
+			return true
+		}
+	}
+	return valueReferencesSecrets(input.Body)
+}
+
+func valueReferencesSecrets(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return secretReference.MatchString(val)
+	case map[string]interface{}:
+		for _, item := range val {
+			if valueReferencesSecrets(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if valueReferencesSecrets(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redact replaces the secret values in s, longest first so a value
+// containing another is replaced whole
+func (values secretValues) redact(s string) string {
+	if len(values) == 0 {
+		return s
+	}
+	secrets := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			secrets = append(secrets, v)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, redactedSecret)
+	}
+	return s
+}
+
+// redactValue redacts the strings of a decoded JSON value
+func (values secretValues) redactValue(v interface{}) interface{} {
+	if len(values) == 0 {
+		return v
+	}
+	switch val := v.(type) {
+	case string:
+		return values.redact(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = values.redactValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = values.redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactResult redacts the secret values from everything an HTTP result
+// carries back to the workflow
+func (values secretValues) redactResult(result *HTTPResult) {
+	if len(values) == 0 {
+		return
+	}
+	result.Body = values.redact(result.Body)
+	result.Error = values.redact(result.Error)
+	for k, v := range result.Headers {
+		result.Headers[k] = values.redact(v)
+	}
+	if result.Extracted != nil {
+		result.Extracted = values.redactValue(result.Extracted).(map[string]interface{})
+	}
+}
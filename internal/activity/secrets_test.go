@@ -0,0 +1,114 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+)
+
+func TestHTTP_Secrets(t *testing.T) {
+	tenantID := uuid.New()
+	var received struct {
+		auth string
+		body map[string]interface{}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(data, &received.body)
+		w.Header().Set("X-Echo", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"token": "` + r.URL.Query().Get("token") + `"}`))
+	}))
+	defer server.Close()
+
+	cipher := mocks.NewMockSecretCipher()
+	secretRepo := mocks.NewMockSecretRepository()
+	value, key, err := cipher.Encrypt([]byte("s3cr3t"))
+	require.NoError(t, err)
+	secretRepo.AddSecret(&domain.Secret{ID: uuid.New(), TenantID: tenantID, Name: "API_TOKEN", EncryptedValue: value, EncryptedKey: key})
+	tenantSetter := mocks.NewMockTenantContextSetter()
+
+	a := NewActivities()
+	a.Secrets = NewSecretResolver(secretRepo, cipher, tenantSetter)
+
+	t.Run("resolves references and redacts the result", func(t *testing.T) {
+		headers := map[string]string{"Authorization": "Bearer ${secrets.API_TOKEN}"}
+
+		result, err := a.HTTP(context.Background(), HTTPInput{
+			TenantID: tenantID.String(),
+			URL:      server.URL + "?token=${secrets.API_TOKEN}",
+			Method:   "POST",
+			Headers:  headers,
+			Body:     map[string]interface{}{"token": "${secrets.API_TOKEN}", "literal": "$${secrets.API_TOKEN}"},
+			Extract:  []HTTPExtract{{Name: "token", JSONPath: "$.token"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer s3cr3t", received.auth)
+		assert.Equal(t, map[string]interface{}{"token": "s3cr3t", "literal": "${secrets.API_TOKEN}"}, received.body)
+		assert.Equal(t, tenantID, tenantSetter.TenantID)
+
+		assert.Equal(t, `{"token": "[REDACTED]"}`, result.Body)
+		assert.Equal(t, "Bearer [REDACTED]", result.Headers["X-Echo"])
+		assert.Equal(t, map[string]interface{}{"token": "[REDACTED]"}, result.Extracted)
+		assert.Equal(t, "Bearer ${secrets.API_TOKEN}", headers["Authorization"])
+	})
+
+	t.Run("redacts failed checks", func(t *testing.T) {
+		_, err := a.HTTP(context.Background(), HTTPInput{
+			TenantID: tenantID.String(),
+			URL:      server.URL + "?token=${secrets.API_TOKEN}",
+			Assert:   []HTTPAssert{{JSONPath: "$.token", Equals: "other"}},
+		})
+
+		assert.ErrorContains(t, err, `$.token is "[REDACTED]", expected "other"`)
+		assert.NotContains(t, err.Error(), "s3cr3t")
+	})
+
+	t.Run("fails on an unknown secret", func(t *testing.T) {
+		_, err := a.HTTP(context.Background(), HTTPInput{
+			TenantID: tenantID.String(),
+			URL:      server.URL,
+			Headers:  map[string]string{"Authorization": "Bearer ${secrets.MISSING}"},
+		})
+
+		assert.ErrorContains(t, err, `secret "MISSING" not found`)
+	})
+
+	t.Run("fails when secrets aren't configured", func(t *testing.T) {
+		_, err := NewActivities().HTTP(context.Background(), HTTPInput{
+			TenantID: tenantID.String(),
+			URL:      server.URL + "?token=${secrets.API_TOKEN}",
+		})
+
+		assert.ErrorContains(t, err, "secrets are not configured on this worker")
+	})
+
+	t.Run("sends escaped references literally without secrets configured", func(t *testing.T) {
+		result, err := NewActivities().HTTP(context.Background(), HTTPInput{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "$${secrets.API_TOKEN}"},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, "${secrets.API_TOKEN}", received.auth)
+	})
+}
+
+func TestSecretValues_Redact(t *testing.T) {
+	values := secretValues{"SHORT": "abc", "LONG": "abcdef"}
+
+	assert.Equal(t, "x [REDACTED] [REDACTED]", values.redact("x abcdef abc"))
+	assert.Equal(t, "x abcdef", secretValues(nil).redact("x abcdef"))
+}
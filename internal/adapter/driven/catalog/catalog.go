@@ -0,0 +1,115 @@
+package catalog
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+//go:embed templates/*.json
+var templateFiles embed.FS
+
+// TemplateCatalog implements port.TemplateCatalog with the templates
+// embedded in the binary. Each template version is a file named
+// <name>.v<version>.json under templates/; published versions are never
+// edited, changes go in a new file.
+type TemplateCatalog struct {
+	versions map[string][]*domain.WorkflowTemplate // by name, oldest version first
+}
+
+// NewTemplateCatalog loads the embedded templates. Every template must
+// instantiate with its example parameters into a definition the validator
+// accepts, so a broken template stops the API from starting rather than
+// failing the tenants who pick it.
+func NewTemplateCatalog(validator port.DefinitionValidator) (*TemplateCatalog, error) {
+	return loadTemplates(templateFiles, validator)
+}
+
+func loadTemplates(files fs.FS, validator port.DefinitionValidator) (*TemplateCatalog, error) {
+	names, err := fs.Glob(files, "templates/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &TemplateCatalog{versions: map[string][]*domain.WorkflowTemplate{}}
+	for _, name := range names {
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+		template, err := parseTemplate(data, validator)
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+		if want := fmt.Sprintf("%s.v%d.json", template.Name, template.Version); path.Base(name) != want {
+			return nil, fmt.Errorf("template %s: must be named %s", name, want)
+		}
+		c.versions[template.Name] = append(c.versions[template.Name], template)
+	}
+
+	for _, versions := range c.versions {
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	}
+	return c, nil
+}
+
+// parseTemplate decodes a template file and checks it
+func parseTemplate(data []byte, validator port.DefinitionValidator) (*domain.WorkflowTemplate, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var template domain.WorkflowTemplate
+	if err := dec.Decode(&template); err != nil {
+		return nil, err
+	}
+
+	if errs := template.Check(); len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", errs[0].Path, errs[0].Message)
+	}
+	definition, paramErrs := template.Instantiate(template.Example)
+	if len(paramErrs) > 0 {
+		return nil, fmt.Errorf("example parameter %s %s", paramErrs[0].Field, paramErrs[0].Message)
+	}
+	if errs := validator.Validate(definition); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Path + ": " + e.Message
+		}
+		return nil, fmt.Errorf("invalid definition: %s", strings.Join(messages, "; "))
+	}
+	return &template, nil
+}
+
+// List returns the latest version of each template, by name
+func (c *TemplateCatalog) List() []*domain.WorkflowTemplate {
+	templates := make([]*domain.WorkflowTemplate, 0, len(c.versions))
+	for _, versions := range c.versions {
+		templates = append(templates, versions[len(versions)-1])
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// Find returns a version of a template, 0 being the latest
+func (c *TemplateCatalog) Find(name string, version int) (*domain.WorkflowTemplate, error) {
+	versions := c.versions[name]
+	if len(versions) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+	if version == 0 {
+		return versions[len(versions)-1], nil
+	}
+	for _, template := range versions {
+		if template.Version == version {
+			return template, nil
+		}
+	}
+	return nil, domain.ErrTemplateNotFound
+}
@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/workflow"
+)
+
+const pingTemplate = `{
+  "name": "ping",
+  "version": %d,
+  "title": "Ping",
+  "description": "Calls a URL",
+  "parameters": {"type": "object", "required": ["url"], "properties": {"url": {"type": "string"}}},
+  "example": {"url": "https://example.com"},
+  "definition": {"steps": [{"id": "ping", "type": "http", "config": {"url": "${params.url}"}}]}
+}`
+
+func TestNewTemplateCatalog(t *testing.T) {
+	c, err := NewTemplateCatalog(workflow.NewDefinitionValidator())
+	require.NoError(t, err)
+
+	var names []string
+	for _, template := range c.List() {
+		names = append(names, template.Name)
+	}
+	assert.Equal(t, []string{"http-health-check", "open-incident", "restart-and-verify", "scale-up-then-verify"}, names)
+}
+
+func TestLoadTemplates(t *testing.T) {
+	validator := workflow.NewDefinitionValidator()
+	file := func(version int) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(fmt.Sprintf(pingTemplate, version))}
+	}
+
+	t.Run("finds versions", func(t *testing.T) {
+		c, err := loadTemplates(fstest.MapFS{
+			"templates/ping.v1.json": file(1),
+			"templates/ping.v2.json": file(2),
+		}, validator)
+		require.NoError(t, err)
+
+		latest, err := c.Find("ping", 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, latest.Version)
+		first, err := c.Find("ping", 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, first.Version)
+		require.Len(t, c.List(), 1)
+		assert.Equal(t, 2, c.List()[0].Version)
+
+		_, err = c.Find("ping", 3)
+		assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+		_, err = c.Find("pong", 0)
+		assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+	})
+
+	t.Run("rejects misnamed files", func(t *testing.T) {
+		_, err := loadTemplates(fstest.MapFS{"templates/ping.json": file(1)}, validator)
+
+		assert.ErrorContains(t, err, "must be named ping.v1.json")
+	})
+
+	t.Run("rejects templates whose example is invalid", func(t *testing.T) {
+		broken := strings.Replace(fmt.Sprintf(pingTemplate, 1), `"https://example.com"`, `"example.com"`, 1)
+
+		_, err := loadTemplates(fstest.MapFS{"templates/ping.v1.json": {Data: []byte(broken)}}, validator)
+
+		assert.ErrorContains(t, err, "must be an absolute http or https URL")
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		broken := strings.Replace(fmt.Sprintf(pingTemplate, 1), `"title"`, `"titel"`, 1)
+
+		_, err := loadTemplates(fstest.MapFS{"templates/ping.v1.json": {Data: []byte(broken)}}, validator)
+
+		assert.ErrorContains(t, err, "unknown field")
+	})
+}
@@ -0,0 +1,99 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of master and data keys, in bytes (AES-256)
+const KeySize = 32
+
+// Cipher implements port.SecretCipher with AES-256-GCM. Every value gets a
+// random data key; the master key only ever encrypts data keys, so it can
+// be rotated by re-wrapping them. Sealed values are nonce || ciphertext.
+type Cipher struct {
+	master cipher.AEAD
+}
+
+// NewCipher creates a cipher from a base64-encoded 32-byte master key
+func NewCipher(masterKey string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(masterKey))
+	if err != nil {
+		return nil, fmt.Errorf("master key must be base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", KeySize, len(key))
+	}
+	master, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{master: master}, nil
+}
+
+// Encrypt seals plaintext with a new data key and returns it with the data
+// key sealed by the master key
+func (c *Cipher) Encrypt(plaintext []byte) (value, key []byte, err error) {
+	dataKey := make([]byte, KeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if value, err = seal(data, plaintext); err != nil {
+		return nil, nil, err
+	}
+	if key, err = seal(c.master, dataKey); err != nil {
+		return nil, nil, err
+	}
+	return value, key, nil
+}
+
+// Decrypt unwraps the data key with the master key and opens the value.
+// It fails if either was tampered with or sealed under another master key.
+func (c *Cipher) Decrypt(value, key []byte) ([]byte, error) {
+	dataKey, err := open(c.master, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	data, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := open(data, value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, sealed []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
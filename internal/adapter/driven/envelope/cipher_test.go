@@ -0,0 +1,68 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, KeySize))
+}
+
+func TestCipher_RoundTrip(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	require.NoError(t, err)
+
+	value, key, err := c.Encrypt([]byte("Bearer s3cr3t"))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(value, []byte("s3cr3t")))
+
+	plaintext, err := c.Decrypt(value, key)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer s3cr3t", string(plaintext))
+
+	// Every value gets its own data key
+	_, other, err := c.Encrypt([]byte("Bearer s3cr3t"))
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestCipher_Decrypt(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	require.NoError(t, err)
+	value, key, err := c.Encrypt([]byte("token"))
+	require.NoError(t, err)
+
+	t.Run("rejects a tampered value", func(t *testing.T) {
+		tampered := append([]byte(nil), value...)
+		tampered[len(tampered)-1] ^= 1
+
+		_, err := c.Decrypt(tampered, key)
+		assert.ErrorContains(t, err, "failed to decrypt value")
+	})
+
+	t.Run("rejects another master key", func(t *testing.T) {
+		other, err := NewCipher(testKey(2))
+		require.NoError(t, err)
+
+		_, err = other.Decrypt(value, key)
+		assert.ErrorContains(t, err, "failed to decrypt data key")
+	})
+
+	t.Run("rejects a truncated key", func(t *testing.T) {
+		_, err := c.Decrypt(value, key[:4])
+		assert.ErrorContains(t, err, "ciphertext too short")
+	})
+}
+
+func TestNewCipher(t *testing.T) {
+	_, err := NewCipher("not base64!")
+	assert.ErrorContains(t, err, "master key must be base64")
+
+	_, err = NewCipher(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "master key must be 32 bytes, got 5")
+}
@@ -81,6 +81,38 @@ func (r *AlertRuleRepository) FindEnabledByTenant(ctx context.Context, tenantID
 	return rules, nil
 }
 
+// FindByName finds the most recently created alert rule with the given name
+func (r *AlertRuleRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.AlertRule, error) {
+	row, err := r.queries.GetAlertRuleByName(ctx, db.GetAlertRuleByNameParams{
+		TenantID: tenantID,
+		Name:     name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAlertRuleNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
+// FindByWorkflow finds the alert rules that trigger a workflow
+func (r *AlertRuleRepository) FindByWorkflow(ctx context.Context, tenantID, workflowID uuid.UUID) ([]*domain.AlertRule, error) {
+	rows, err := r.queries.ListAlertRulesByWorkflow(ctx, db.ListAlertRulesByWorkflowParams{
+		TenantID:          tenantID,
+		TriggerWorkflowID: uuidToPgtype(&workflowID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]*domain.AlertRule, len(rows))
+	for i, row := range rows {
+		rules[i] = r.toDomain(row)
+	}
+	return rules, nil
+}
+
 // CountByTenant counts alert rules for a tenant
 func (r *AlertRuleRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
 	return r.queries.CountAlertRules(ctx, tenantID)
@@ -88,40 +120,48 @@ func (r *AlertRuleRepository) CountByTenant(ctx context.Context, tenantID uuid.U
 
 // Save saves a new alert rule
 func (r *AlertRuleRepository) Save(ctx context.Context, rule *domain.AlertRule) error {
-	_, err := r.queries.CreateAlertRule(ctx, db.CreateAlertRuleParams{
-		TenantID:             rule.TenantID,
-		Name:                 rule.Name,
-		Description:          rule.Description,
-		Enabled:              rule.Enabled,
-		ConditionType:        rule.ConditionType,
-		ConditionConfig:      rule.ConditionConfig,
-		Severity:             string(rule.Severity),
-		AlertTitleTemplate:   rule.AlertTitleTemplate,
-		AlertMessageTemplate: rule.AlertMessageTemplate,
-		TriggerWorkflowID:    uuidToPgtype(rule.TriggerWorkflowID),
-		TriggerInputTemplate: rule.TriggerInputTemplate,
-		CooldownSeconds:      rule.CooldownSeconds,
-		CreatedBy:            uuidToPgtype(rule.CreatedBy),
+	row, err := r.queries.CreateAlertRule(ctx, db.CreateAlertRuleParams{
+		TenantID:               rule.TenantID,
+		Name:                   rule.Name,
+		Description:            rule.Description,
+		Enabled:                rule.Enabled,
+		ConditionType:          rule.ConditionType,
+		ConditionConfig:        rule.ConditionConfig,
+		Severity:               string(rule.Severity),
+		AlertTitleTemplate:     rule.AlertTitleTemplate,
+		AlertMessageTemplate:   rule.AlertMessageTemplate,
+		TriggerWorkflowID:      uuidToPgtype(rule.TriggerWorkflowID),
+		TriggerWorkflowVersion: rule.TriggerWorkflowVersion,
+		TriggerInputTemplate:   rule.TriggerInputTemplate,
+		CooldownSeconds:        rule.CooldownSeconds,
+		CreatedBy:              uuidToPgtype(rule.CreatedBy),
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	// The database generates the ID
+	rule.ID = row.ID
+	rule.CreatedAt = row.CreatedAt
+	return nil
 }
 
 // Update updates an existing alert rule
 func (r *AlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
 	_, err := r.queries.UpdateAlertRule(ctx, db.UpdateAlertRuleParams{
-		ID:                   rule.ID,
-		TenantID:             rule.TenantID,
-		Name:                 rule.Name,
-		Description:          rule.Description,
-		Enabled:              rule.Enabled,
-		ConditionType:        rule.ConditionType,
-		ConditionConfig:      rule.ConditionConfig,
-		Severity:             string(rule.Severity),
-		AlertTitleTemplate:   rule.AlertTitleTemplate,
-		AlertMessageTemplate: rule.AlertMessageTemplate,
-		TriggerWorkflowID:    uuidToPgtype(rule.TriggerWorkflowID),
-		TriggerInputTemplate: rule.TriggerInputTemplate,
-		CooldownSeconds:      rule.CooldownSeconds,
+		ID:                     rule.ID,
+		TenantID:               rule.TenantID,
+		Name:                   rule.Name,
+		Description:            rule.Description,
+		Enabled:                rule.Enabled,
+		ConditionType:          rule.ConditionType,
+		ConditionConfig:        rule.ConditionConfig,
+		Severity:               string(rule.Severity),
+		AlertTitleTemplate:     rule.AlertTitleTemplate,
+		AlertMessageTemplate:   rule.AlertMessageTemplate,
+		TriggerWorkflowID:      uuidToPgtype(rule.TriggerWorkflowID),
+		TriggerWorkflowVersion: rule.TriggerWorkflowVersion,
+		TriggerInputTemplate:   rule.TriggerInputTemplate,
+		CooldownSeconds:        rule.CooldownSeconds,
 	})
 	return err
 }
@@ -165,23 +205,24 @@ func (r *AlertRuleRepository) toDomain(row db.AlertRule) *domain.AlertRule {
 	}
 
 	return &domain.AlertRule{
-		ID:                   row.ID,
-		TenantID:             row.TenantID,
-		Name:                 row.Name,
-		Description:          row.Description,
-		Enabled:              row.Enabled,
-		ConditionType:        row.ConditionType,
-		ConditionConfig:      row.ConditionConfig,
-		Severity:             domain.AlertSeverity(row.Severity),
-		AlertTitleTemplate:   row.AlertTitleTemplate,
-		AlertMessageTemplate: row.AlertMessageTemplate,
-		TriggerWorkflowID:    triggerWorkflowID,
-		TriggerInputTemplate: row.TriggerInputTemplate,
-		CooldownSeconds:      row.CooldownSeconds,
-		LastTriggeredAt:      lastTriggeredAt,
-		CreatedBy:            createdBy,
-		CreatedAt:            row.CreatedAt,
-		UpdatedAt:            row.UpdatedAt,
+		ID:                     row.ID,
+		TenantID:               row.TenantID,
+		Name:                   row.Name,
+		Description:            row.Description,
+		Enabled:                row.Enabled,
+		ConditionType:          row.ConditionType,
+		ConditionConfig:        row.ConditionConfig,
+		Severity:               domain.AlertSeverity(row.Severity),
+		AlertTitleTemplate:     row.AlertTitleTemplate,
+		AlertMessageTemplate:   row.AlertMessageTemplate,
+		TriggerWorkflowID:      triggerWorkflowID,
+		TriggerWorkflowVersion: row.TriggerWorkflowVersion,
+		TriggerInputTemplate:   row.TriggerInputTemplate,
+		CooldownSeconds:        row.CooldownSeconds,
+		LastTriggeredAt:        lastTriggeredAt,
+		CreatedBy:              createdBy,
+		CreatedAt:              row.CreatedAt,
+		UpdatedAt:              row.UpdatedAt,
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
@@ -75,6 +76,20 @@ func (r *ExecutionRepository) FindByWorkflow(ctx context.Context, workflowID uui
 	return executions, nil
 }
 
+// FindByParent finds the child executions started by an execution
+func (r *ExecutionRepository) FindByParent(ctx context.Context, parentID uuid.UUID) ([]*domain.Execution, error) {
+	rows, err := r.queries.ListChildExecutions(ctx, pgtype.UUID{Bytes: parentID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	executions := make([]*domain.Execution, len(rows))
+	for i, row := range rows {
+		executions[i] = r.toDomain(row)
+	}
+	return executions, nil
+}
+
 // CountByTenant counts executions for a tenant
 func (r *ExecutionRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
 	return r.queries.CountExecutions(ctx, tenantID)
@@ -82,23 +97,34 @@ func (r *ExecutionRepository) CountByTenant(ctx context.Context, tenantID uuid.U
 
 // Save saves a new execution
 func (r *ExecutionRepository) Save(ctx context.Context, execution *domain.Execution) error {
-	_, err := r.queries.CreateExecution(ctx, db.CreateExecutionParams{
+	row, err := r.queries.CreateExecution(ctx, db.CreateExecutionParams{
+		ID:                 execution.ID,
 		TenantID:           execution.TenantID,
 		WorkflowID:         execution.WorkflowID,
 		TemporalWorkflowID: execution.TemporalWorkflowID,
 		Status:             string(execution.Status),
 		Input:              execution.Input,
 		TriggeredBy:        execution.TriggeredBy,
+		ParentExecutionID:  uuidToPgtype(execution.ParentExecutionID),
+		WorkflowVersion:    execution.WorkflowVersion,
+		RetryOf:            uuidToPgtype(execution.RetryOf),
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	execution.CreatedAt = row.CreatedAt
+	return nil
 }
 
 // Update updates an existing execution
 func (r *ExecutionRepository) Update(ctx context.Context, execution *domain.Execution) error {
-	return r.queries.UpdateExecutionStatus(ctx, db.UpdateExecutionStatusParams{
-		ID:     execution.ID,
-		Status: string(execution.Status),
-		Error:  execution.Error,
+	return r.queries.UpdateExecution(ctx, db.UpdateExecutionParams{
+		ID:          execution.ID,
+		Status:      string(execution.Status),
+		Output:      execution.Output,
+		Error:       execution.Error,
+		StartedAt:   timeToPgtype(execution.StartedAt),
+		CompletedAt: timeToPgtype(execution.CompletedAt),
 	})
 }
 
@@ -120,6 +146,23 @@ func (r *ExecutionRepository) UpdateTemporalIDs(ctx context.Context, id uuid.UUI
 	})
 }
 
+// MarkRunning records the temporal IDs of a started execution and marks it
+// running unless it already finished
+func (r *ExecutionRepository) MarkRunning(ctx context.Context, id uuid.UUID, temporalWorkflowID, temporalRunID string) (*domain.Execution, error) {
+	row, err := r.queries.MarkExecutionRunning(ctx, db.MarkExecutionRunningParams{
+		ID:                 id,
+		TemporalWorkflowID: &temporalWorkflowID,
+		TemporalRunID:      &temporalRunID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrExecutionNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
 // toDomain converts a db.Execution to domain.Execution
 func (r *ExecutionRepository) toDomain(row db.Execution) *domain.Execution {
 	var createdBy *uuid.UUID
@@ -128,6 +171,18 @@ func (r *ExecutionRepository) toDomain(row db.Execution) *domain.Execution {
 		createdBy = &id
 	}
 
+	var parentExecutionID *uuid.UUID
+	if row.ParentExecutionID.Valid {
+		id := uuid.UUID(row.ParentExecutionID.Bytes)
+		parentExecutionID = &id
+	}
+
+	var retryOf *uuid.UUID
+	if row.RetryOf.Valid {
+		id := uuid.UUID(row.RetryOf.Bytes)
+		retryOf = &id
+	}
+
 	var startedAt, completedAt *time.Time
 	if row.StartedAt.Valid {
 		t := row.StartedAt.Time
@@ -153,5 +208,15 @@ func (r *ExecutionRepository) toDomain(row db.Execution) *domain.Execution {
 		CreatedBy:          createdBy,
 		CreatedAt:          row.CreatedAt,
 		TriggeredBy:        row.TriggeredBy,
+		ParentExecutionID:  parentExecutionID,
+		WorkflowVersion:    row.WorkflowVersion,
+		RetryOf:            retryOf,
+	}
+}
+
+func timeToPgtype(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{Valid: false}
 	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
 }
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/db"
+)
+
+// ExecutionStepRepository implements port.ExecutionStepRepository
+type ExecutionStepRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+// NewExecutionStepRepository creates a new execution step repository
+func NewExecutionStepRepository(pool *pgxpool.Pool) *ExecutionStepRepository {
+	return &ExecutionStepRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+// Save inserts a step, replacing the one recorded at the same position so
+// retried activities don't duplicate steps
+func (r *ExecutionStepRepository) Save(ctx context.Context, step *domain.ExecutionStep) error {
+	var stepName *string
+	if step.StepName != "" {
+		stepName = &step.StepName
+	}
+
+	row, err := r.queries.UpsertExecutionStep(ctx, db.UpsertExecutionStepParams{
+		TenantID:    step.TenantID,
+		ExecutionID: step.ExecutionID,
+		Position:    step.Position,
+		StepID:      step.StepID,
+		StepName:    stepName,
+		StepType:    step.StepType,
+		Status:      string(step.Status),
+		Attempts:    step.Attempts,
+		Output:      step.Output,
+		Error:       step.Error,
+		StartedAt:   step.StartedAt,
+		CompletedAt: timeToPgtype(step.CompletedAt),
+	})
+	if err != nil {
+		return err
+	}
+	// The database generates the ID
+	step.ID = row.ID
+	return nil
+}
+
+// FindByExecution finds the steps of an execution in definition order
+func (r *ExecutionStepRepository) FindByExecution(ctx context.Context, executionID uuid.UUID) ([]*domain.ExecutionStep, error) {
+	rows, err := r.queries.ListExecutionSteps(ctx, executionID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]*domain.ExecutionStep, len(rows))
+	for i, row := range rows {
+		steps[i] = r.toDomain(row)
+	}
+	return steps, nil
+}
+
+// toDomain converts a db.ExecutionStep to domain.ExecutionStep
+func (r *ExecutionStepRepository) toDomain(row db.ExecutionStep) *domain.ExecutionStep {
+	var stepName string
+	if row.StepName != nil {
+		stepName = *row.StepName
+	}
+
+	var completedAt *time.Time
+	if row.CompletedAt.Valid {
+		t := row.CompletedAt.Time
+		completedAt = &t
+	}
+
+	return &domain.ExecutionStep{
+		ID:          row.ID,
+		TenantID:    row.TenantID,
+		ExecutionID: row.ExecutionID,
+		Position:    row.Position,
+		StepID:      row.StepID,
+		StepName:    stepName,
+		StepType:    row.StepType,
+		Status:      domain.ExecutionStepStatus(row.Status),
+		Attempts:    row.Attempts,
+		Output:      row.Output,
+		Error:       row.Error,
+		StartedAt:   row.StartedAt,
+		CompletedAt: completedAt,
+	}
+}
@@ -81,6 +81,7 @@ func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 		version INTEGER NOT NULL DEFAULT 1,
 		definition JSONB,
 		trigger_config JSONB,
+		schedule_options JSONB,
 		created_at TIMESTAMPTZ DEFAULT NOW(),
 		updated_at TIMESTAMPTZ DEFAULT NOW()
 	);
@@ -96,6 +97,8 @@ func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 		output JSONB,
 		error TEXT,
 		triggered_by TEXT,
+		parent_execution_id UUID REFERENCES executions(id),
+		workflow_version INTEGER,
 		started_at TIMESTAMPTZ,
 		completed_at TIMESTAMPTZ,
 		created_at TIMESTAMPTZ DEFAULT NOW()
@@ -138,7 +141,20 @@ func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
 		last_triggered_at TIMESTAMPTZ,
 		created_by UUID,
 		created_at TIMESTAMPTZ DEFAULT NOW(),
-		updated_at TIMESTAMPTZ DEFAULT NOW()
+		updated_at TIMESTAMPTZ DEFAULT NOW(),
+		trigger_workflow_version INTEGER
+	);
+
+	CREATE TABLE IF NOT EXISTS workflow_versions (
+		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+		tenant_id UUID NOT NULL REFERENCES tenants(id),
+		workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+		version INTEGER NOT NULL,
+		definition JSONB NOT NULL,
+		restored_from INTEGER,
+		created_by UUID,
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		UNIQUE (workflow_id, version)
 	);
 
 	CREATE TABLE IF NOT EXISTS audit_logs (
@@ -131,10 +131,25 @@ func (r *MetricRepository) CountByQuery(ctx context.Context, query domain.Metric
 
 // FindLatest finds the latest metric value for a given name and labels
 func (r *MetricRepository) FindLatest(ctx context.Context, tenantID uuid.UUID, name string, labels map[string]string) (*domain.Metric, error) {
-	row, err := r.queries.GetLatestMetric(ctx, db.GetLatestMetricParams{
-		TenantID: tenantID,
-		Name:     name,
-	})
+	var row db.Metric
+	var err error
+
+	if len(labels) > 0 {
+		labelsJSON, marshalErr := json.Marshal(labels)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		row, err = r.queries.GetLatestMetricByLabels(ctx, db.GetLatestMetricByLabelsParams{
+			TenantID: tenantID,
+			Name:     name,
+			Labels:   labelsJSON,
+		})
+	} else {
+		row, err = r.queries.GetLatestMetric(ctx, db.GetLatestMetricParams{
+			TenantID: tenantID,
+			Name:     name,
+		})
+	}
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrMetricNotFound
@@ -146,12 +161,31 @@ func (r *MetricRepository) FindLatest(ctx context.Context, tenantID uuid.UUID, n
 
 // GetAggregate gets aggregated stats for metrics
 func (r *MetricRepository) GetAggregate(ctx context.Context, query domain.MetricQuery) (*domain.MetricAggregate, error) {
-	row, err := r.queries.GetMetricsAggregateWithPercentiles(ctx, db.GetMetricsAggregateWithPercentilesParams{
-		TenantID:    query.TenantID,
-		Name:        query.Name,
-		Timestamp:   query.StartTime,
-		Timestamp_2: query.EndTime,
-	})
+	var row db.GetMetricsAggregateWithPercentilesRow
+	var err error
+
+	if len(query.Labels) > 0 {
+		labels, marshalErr := json.Marshal(query.Labels)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+		var labeled db.GetMetricsAggregateWithLabelsRow
+		labeled, err = r.queries.GetMetricsAggregateWithLabels(ctx, db.GetMetricsAggregateWithLabelsParams{
+			TenantID:    query.TenantID,
+			Name:        query.Name,
+			Labels:      labels,
+			Timestamp:   query.StartTime,
+			Timestamp_2: query.EndTime,
+		})
+		row = db.GetMetricsAggregateWithPercentilesRow(labeled)
+	} else {
+		row, err = r.queries.GetMetricsAggregateWithPercentiles(ctx, db.GetMetricsAggregateWithPercentilesParams{
+			TenantID:    query.TenantID,
+			Name:        query.Name,
+			Timestamp:   query.StartTime,
+			Timestamp_2: query.EndTime,
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
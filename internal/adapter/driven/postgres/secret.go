@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/db"
+)
+
+// SecretRepository implements port.SecretRepository
+type SecretRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+// NewSecretRepository creates a new secret repository
+func NewSecretRepository(pool *pgxpool.Pool) *SecretRepository {
+	return &SecretRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+// FindByID finds a secret by ID
+func (r *SecretRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Secret, error) {
+	row, err := r.queries.GetSecret(ctx, db.GetSecretParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSecretNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
+// FindByName finds a secret by name
+func (r *SecretRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Secret, error) {
+	row, err := r.queries.GetSecretByName(ctx, db.GetSecretByNameParams{
+		TenantID: tenantID,
+		Name:     name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSecretNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
+// FindByTenant finds the secrets of a tenant, ordered by name
+func (r *SecretRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.Secret, error) {
+	rows, err := r.queries.ListSecrets(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make([]*domain.Secret, len(rows))
+	for i, row := range rows {
+		secrets[i] = r.toDomain(row)
+	}
+	return secrets, nil
+}
+
+// Save saves a new secret
+func (r *SecretRepository) Save(ctx context.Context, secret *domain.Secret) error {
+	row, err := r.queries.CreateSecret(ctx, db.CreateSecretParams{
+		TenantID:       secret.TenantID,
+		Name:           secret.Name,
+		Description:    secret.Description,
+		EncryptedValue: secret.EncryptedValue,
+		EncryptedKey:   secret.EncryptedKey,
+		CreatedBy:      uuidToPgtype(secret.CreatedBy),
+	})
+	if err != nil {
+		return err
+	}
+	// The database generates the ID
+	secret.ID = row.ID
+	secret.CreatedAt = row.CreatedAt
+	secret.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// Update updates the description and value of a secret
+func (r *SecretRepository) Update(ctx context.Context, secret *domain.Secret) error {
+	row, err := r.queries.UpdateSecret(ctx, db.UpdateSecretParams{
+		ID:             secret.ID,
+		TenantID:       secret.TenantID,
+		Description:    secret.Description,
+		EncryptedValue: secret.EncryptedValue,
+		EncryptedKey:   secret.EncryptedKey,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrSecretNotFound
+		}
+		return err
+	}
+	secret.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// Delete deletes a secret
+func (r *SecretRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	return r.queries.DeleteSecret(ctx, db.DeleteSecretParams{
+		ID:       id,
+		TenantID: tenantID,
+	})
+}
+
+// toDomain converts a db.Secret to domain.Secret
+func (r *SecretRepository) toDomain(row db.Secret) *domain.Secret {
+	var createdBy *uuid.UUID
+	if row.CreatedBy.Valid {
+		id := uuid.UUID(row.CreatedBy.Bytes)
+		createdBy = &id
+	}
+
+	return &domain.Secret{
+		ID:             row.ID,
+		TenantID:       row.TenantID,
+		Name:           row.Name,
+		Description:    row.Description,
+		EncryptedValue: row.EncryptedValue,
+		EncryptedKey:   row.EncryptedKey,
+		CreatedBy:      createdBy,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
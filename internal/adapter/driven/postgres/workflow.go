@@ -2,7 +2,9 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"log/slog"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -39,6 +41,21 @@ func (r *WorkflowRepository) FindByID(ctx context.Context, id uuid.UUID) (*domai
 	return r.toDomain(row), nil
 }
 
+// FindByName finds the most recently created workflow with the given name
+func (r *WorkflowRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Workflow, error) {
+	row, err := r.queries.GetWorkflowByName(ctx, db.GetWorkflowByNameParams{
+		TenantID: tenantID,
+		Name:     name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
 // FindByTenant finds workflows by tenant with pagination
 func (r *WorkflowRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Workflow, error) {
 	rows, err := r.queries.ListWorkflows(ctx, db.ListWorkflowsParams{
@@ -64,27 +81,57 @@ func (r *WorkflowRepository) CountByTenant(ctx context.Context, tenantID uuid.UU
 
 // Save saves a new workflow
 func (r *WorkflowRepository) Save(ctx context.Context, workflow *domain.Workflow) error {
-	_, err := r.queries.CreateWorkflow(ctx, db.CreateWorkflowParams{
-		TenantID:    workflow.TenantID,
-		Name:        workflow.Name,
-		Description: workflow.Description,
-		Definition:  workflow.Definition,
-		Schedule:    workflow.Schedule,
-		Status:      string(workflow.Status),
-		CreatedBy:   uuidToPgtype(workflow.CreatedBy),
+	scheduleOptions, err := marshalScheduleOptions(workflow.ScheduleOptions)
+	if err != nil {
+		return err
+	}
+	comments, err := marshalDefinitionComments(workflow.DefinitionComments)
+	if err != nil {
+		return err
+	}
+
+	row, err := r.queries.CreateWorkflow(ctx, db.CreateWorkflowParams{
+		TenantID:           workflow.TenantID,
+		Name:               workflow.Name,
+		Description:        workflow.Description,
+		Definition:         workflow.Definition,
+		Schedule:           workflow.Schedule,
+		Status:             string(workflow.Status),
+		CreatedBy:          uuidToPgtype(workflow.CreatedBy),
+		ScheduleOptions:    scheduleOptions,
+		DefinitionComments: comments,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	// The database generates the ID
+	workflow.ID = row.ID
+	workflow.Version = row.Version
+	workflow.CreatedAt = row.CreatedAt
+	return nil
 }
 
 // Update updates an existing workflow
 func (r *WorkflowRepository) Update(ctx context.Context, workflow *domain.Workflow) error {
-	_, err := r.queries.UpdateWorkflow(ctx, db.UpdateWorkflowParams{
-		ID:          workflow.ID,
-		Name:        workflow.Name,
-		Description: workflow.Description,
-		Definition:  workflow.Definition,
-		Schedule:    workflow.Schedule,
-		Status:      string(workflow.Status),
+	scheduleOptions, err := marshalScheduleOptions(workflow.ScheduleOptions)
+	if err != nil {
+		return err
+	}
+	comments, err := marshalDefinitionComments(workflow.DefinitionComments)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.UpdateWorkflow(ctx, db.UpdateWorkflowParams{
+		ID:                 workflow.ID,
+		Name:               workflow.Name,
+		Description:        workflow.Description,
+		Definition:         workflow.Definition,
+		Schedule:           workflow.Schedule,
+		Status:             string(workflow.Status),
+		Version:            workflow.Version,
+		ScheduleOptions:    scheduleOptions,
+		DefinitionComments: comments,
 	})
 	return err
 }
@@ -102,19 +149,55 @@ func (r *WorkflowRepository) toDomain(row db.Workflow) *domain.Workflow {
 		createdBy = &id
 	}
 
+	var scheduleOptions *domain.ScheduleOptions
+	if len(row.ScheduleOptions) > 0 {
+		var opts domain.ScheduleOptions
+		if err := json.Unmarshal(row.ScheduleOptions, &opts); err != nil {
+			slog.Warn("failed to unmarshal schedule options", "workflow_id", row.ID, "error", err)
+		} else {
+			scheduleOptions = &opts
+		}
+	}
+
+	var comments domain.DefinitionComments
+	if len(row.DefinitionComments) > 0 {
+		if err := json.Unmarshal(row.DefinitionComments, &comments); err != nil {
+			slog.Warn("failed to unmarshal definition comments", "workflow_id", row.ID, "error", err)
+		}
+	}
+
 	return &domain.Workflow{
-		ID:          row.ID,
-		TenantID:    row.TenantID,
-		Name:        row.Name,
-		Description: row.Description,
-		Definition:  row.Definition,
-		Schedule:    row.Schedule,
-		Status:      domain.WorkflowStatus(row.Status),
-		Version:     row.Version,
-		CreatedBy:   createdBy,
-		CreatedAt:   row.CreatedAt,
-		UpdatedAt:   row.UpdatedAt,
+		ID:                 row.ID,
+		TenantID:           row.TenantID,
+		Name:               row.Name,
+		Description:        row.Description,
+		Definition:         row.Definition,
+		DefinitionComments: comments,
+		Schedule:           row.Schedule,
+		ScheduleOptions:    scheduleOptions,
+		Status:             domain.WorkflowStatus(row.Status),
+		Version:            row.Version,
+		CreatedBy:          createdBy,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+	}
+}
+
+// marshalScheduleOptions converts schedule options to their JSONB column value
+func marshalScheduleOptions(opts *domain.ScheduleOptions) ([]byte, error) {
+	if opts == nil {
+		return nil, nil
+	}
+	return json.Marshal(opts)
+}
+
+// marshalDefinitionComments converts definition comments to their JSONB
+// column value
+func marshalDefinitionComments(comments domain.DefinitionComments) ([]byte, error) {
+	if len(comments) == 0 {
+		return nil, nil
 	}
+	return json.Marshal(comments)
 }
 
 // uuidToPgtype converts *uuid.UUID to pgtype.UUID
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/db"
+)
+
+// WorkflowVersionRepository implements port.WorkflowVersionRepository
+type WorkflowVersionRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+// NewWorkflowVersionRepository creates a new workflow version repository
+func NewWorkflowVersionRepository(pool *pgxpool.Pool) *WorkflowVersionRepository {
+	return &WorkflowVersionRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+// Save saves a new workflow version
+func (r *WorkflowVersionRepository) Save(ctx context.Context, version *domain.WorkflowVersion) error {
+	row, err := r.queries.CreateWorkflowVersion(ctx, db.CreateWorkflowVersionParams{
+		TenantID:     version.TenantID,
+		WorkflowID:   version.WorkflowID,
+		Version:      version.Version,
+		Definition:   version.Definition,
+		RestoredFrom: version.RestoredFrom,
+		CreatedBy:    uuidToPgtype(version.CreatedBy),
+	})
+	if err != nil {
+		return err
+	}
+	// The database generates the ID
+	version.ID = row.ID
+	version.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// FindByVersion finds a specific version of a workflow
+func (r *WorkflowVersionRepository) FindByVersion(ctx context.Context, workflowID uuid.UUID, version int32) (*domain.WorkflowVersion, error) {
+	row, err := r.queries.GetWorkflowVersion(ctx, db.GetWorkflowVersionParams{
+		WorkflowID: workflowID,
+		Version:    version,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWorkflowVersionNotFound
+		}
+		return nil, err
+	}
+	return r.toDomain(row), nil
+}
+
+// LatestVersion returns the highest version of a workflow, 0 if it has none
+func (r *WorkflowVersionRepository) LatestVersion(ctx context.Context, workflowID uuid.UUID) (int32, error) {
+	return r.queries.GetLatestWorkflowVersion(ctx, workflowID)
+}
+
+// FindByWorkflow finds the versions of a workflow, newest first
+func (r *WorkflowVersionRepository) FindByWorkflow(ctx context.Context, workflowID uuid.UUID, limit, offset int) ([]*domain.WorkflowVersion, error) {
+	rows, err := r.queries.ListWorkflowVersions(ctx, db.ListWorkflowVersionsParams{
+		WorkflowID: workflowID,
+		Limit:      int32(limit),
+		Offset:     int32(offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*domain.WorkflowVersion, len(rows))
+	for i, row := range rows {
+		versions[i] = r.toDomain(row)
+	}
+	return versions, nil
+}
+
+// CountByWorkflow counts the versions of a workflow
+func (r *WorkflowVersionRepository) CountByWorkflow(ctx context.Context, workflowID uuid.UUID) (int64, error) {
+	return r.queries.CountWorkflowVersions(ctx, workflowID)
+}
+
+// toDomain converts a db.WorkflowVersion to domain.WorkflowVersion
+func (r *WorkflowVersionRepository) toDomain(row db.WorkflowVersion) *domain.WorkflowVersion {
+	var createdBy *uuid.UUID
+	if row.CreatedBy.Valid {
+		id := uuid.UUID(row.CreatedBy.Bytes)
+		createdBy = &id
+	}
+
+	return &domain.WorkflowVersion{
+		ID:           row.ID,
+		TenantID:     row.TenantID,
+		WorkflowID:   row.WorkflowID,
+		Version:      row.Version,
+		Definition:   row.Definition,
+		RestoredFrom: row.RestoredFrom,
+		CreatedBy:    createdBy,
+		CreatedAt:    row.CreatedAt,
+	}
+}
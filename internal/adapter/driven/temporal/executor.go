@@ -2,13 +2,18 @@ package temporal
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/google/uuid"
 	"go.temporal.io/sdk/client"
 
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
 	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/internal/workflow"
 )
 
 // WorkflowExecutor implements port.WorkflowExecutor using Temporal
@@ -19,38 +24,63 @@ type WorkflowExecutor struct {
 
 // NewWorkflowExecutor creates a new workflow executor
 func NewWorkflowExecutor(c client.Client) *WorkflowExecutor {
+	return &WorkflowExecutor{
+		client:    c,
+		taskQueue: taskQueueFromEnv(),
+	}
+}
+
+// taskQueueFromEnv returns the task queue the worker listens on
+func taskQueueFromEnv() string {
 	taskQueue := os.Getenv("TEMPORAL_TASK_QUEUE")
 	if taskQueue == "" {
 		taskQueue = "orchestrix-queue"
 	}
-
-	return &WorkflowExecutor{
-		client:    c,
-		taskQueue: taskQueue,
-	}
+	return taskQueue
 }
 
 // Execute starts a workflow execution in Temporal
-func (e *WorkflowExecutor) Execute(ctx context.Context, workflow *domain.Workflow, input map[string]interface{}) (*port.ExecuteResult, error) {
-	if !workflow.CanExecute() {
+func (e *WorkflowExecutor) Execute(ctx context.Context, wf *domain.Workflow, executionID uuid.UUID, input map[string]interface{}, resume *domain.ExecutionResume) (*port.ExecuteResult, error) {
+	if !wf.CanExecute() {
 		return nil, domain.ErrWorkflowCannotExecute
 	}
 
-	workflowID := fmt.Sprintf("workflow-%s-%d", workflow.ID.String(), workflow.Version)
+	// Validate the workflow definition before handing it to the worker
+	if _, err := wf.ParseDefinition(); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow definition: %w", err)
+	}
+	timeout, err := definitionTimeout(wf)
+	if err != nil {
+		return nil, err
+	}
 
 	options := client.StartWorkflowOptions{
-		ID:        workflowID,
+		ID:        domain.TemporalWorkflowID(executionID),
 		TaskQueue: e.taskQueue,
 	}
+	if timeout > 0 {
+		// The run enforces the timeout itself; Temporal stops it if it
+		// overruns the grace period it has to clean up
+		options.WorkflowExecutionTimeout = timeout + workflow.TimeoutGrace
+	}
 
-	// Parse workflow definition to get the workflow type
-	def, err := workflow.ParseDefinition()
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse workflow definition: %w", err)
+	workflowInput := workflow.DynamicWorkflowInput{
+		ExecutionID: executionID.String(),
+		WorkflowID:  wf.ID.String(),
+		Name:        wf.Name,
+		Definition:  wf.Definition,
+		Input:       input,
+		TenantID:    wf.TenantID.String(),
+	}
+	if resume != nil {
+		resumeInput, err := toResumeInput(resume)
+		if err != nil {
+			return nil, err
+		}
+		workflowInput.Resume = resumeInput
 	}
 
-	// Start the workflow with the definition and input
-	run, err := e.client.ExecuteWorkflow(ctx, options, "DynamicWorkflow", def, input)
+	run, err := e.client.ExecuteWorkflow(ctx, options, "DynamicWorkflow", workflowInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start workflow: %w", err)
 	}
@@ -61,6 +91,116 @@ func (e *WorkflowExecutor) Execute(ctx context.Context, workflow *domain.Workflo
 	}, nil
 }
 
+// dryRunTimeout bounds how long a dry run may take. Simulated steps don't
+// wait, so only very large loops get near it.
+const dryRunTimeout = time.Minute
+
+// DryRun simulates a run of the workflow on the worker and waits for its
+// trace. Dry runs have no execution record.
+func (e *WorkflowExecutor) DryRun(ctx context.Context, wf *domain.Workflow, input map[string]interface{}, fixtures map[string]interface{}) (*domain.DryRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, dryRunTimeout)
+	defer cancel()
+
+	options := client.StartWorkflowOptions{
+		ID:                       "dry-run-" + uuid.NewString(),
+		TaskQueue:                e.taskQueue,
+		WorkflowExecutionTimeout: dryRunTimeout,
+	}
+
+	run, err := e.client.ExecuteWorkflow(ctx, options, "DynamicWorkflow", workflow.DynamicWorkflowInput{
+		WorkflowID: wf.ID.String(),
+		Name:       wf.Name,
+		Definition: wf.Definition,
+		Input:      input,
+		TenantID:   wf.TenantID.String(),
+		DryRun:     &workflow.DryRunInput{Fixtures: fixtures},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start dry run: %w", err)
+	}
+
+	var output workflow.DynamicWorkflowOutput
+	if err := run.Get(ctx, &output); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, domain.ErrDryRunTimedOut
+		}
+		return nil, fmt.Errorf("dry run failed: %w", err)
+	}
+
+	return &domain.DryRun{
+		Status:     output.Status,
+		Error:      output.Error,
+		Steps:      toDryRunSteps(output.StepResults),
+		Output:     output.Output,
+		DurationMs: output.Duration,
+	}, nil
+}
+
+// toDryRunSteps converts the step results of a dry run to its trace
+func toDryRunSteps(results []workflow.StepResult) []domain.DryRunStep {
+	steps := make([]domain.DryRunStep, 0, len(results))
+	for _, r := range results {
+		steps = append(steps, domain.DryRunStep{
+			StepID:   r.StepID,
+			StepName: r.StepName,
+			StepType: r.StepType,
+			Success:  r.Success,
+			Config:   r.Config,
+			Output:   r.Output,
+			Error:    r.Error,
+			Children: toDryRunSteps(r.Children),
+		})
+	}
+	return steps
+}
+
+// definitionTimeout returns the timeout set by the workflow definition, 0
+// when there is none
+func definitionTimeout(wf *domain.Workflow) (time.Duration, error) {
+	if len(wf.Definition) == 0 {
+		return 0, nil
+	}
+	var def workflow.WorkflowDefinition
+	if err := json.Unmarshal(wf.Definition, &def); err != nil {
+		return 0, fmt.Errorf("failed to parse workflow definition: %w", err)
+	}
+	timeout, err := def.TimeoutDuration()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", domain.ErrInvalidDefinition, err)
+	}
+	return timeout, nil
+}
+
+// toResumeInput converts the steps a retry reuses to the workflow's format
+func toResumeInput(resume *domain.ExecutionResume) (*workflow.ResumeInput, error) {
+	input := &workflow.ResumeInput{
+		StartAt: int(resume.StartAt),
+		Steps:   make([]workflow.StepState, 0, len(resume.Steps)),
+	}
+	for _, step := range resume.Steps {
+		state := workflow.StepState{
+			Position:    int(step.Position),
+			StepID:      step.StepID,
+			StepName:    step.StepName,
+			StepType:    step.StepType,
+			Status:      string(step.Status),
+			Attempts:    step.Attempts,
+			StartedAt:   step.StartedAt,
+			CompletedAt: step.CompletedAt,
+		}
+		if len(step.Output) > 0 {
+			if err := json.Unmarshal(step.Output, &state.Output); err != nil {
+				return nil, fmt.Errorf("failed to decode output of step %s: %w", step.StepID, err)
+			}
+		}
+		if step.Error != nil {
+			state.Error = *step.Error
+		}
+		input.Steps = append(input.Steps, state)
+	}
+	return input, nil
+}
+
 // Cancel requests cancellation of a running workflow
 func (e *WorkflowExecutor) Cancel(ctx context.Context, temporalWorkflowID string) error {
 	return e.client.CancelWorkflow(ctx, temporalWorkflowID, "")
@@ -79,3 +219,154 @@ func (e *WorkflowExecutor) GetStatus(ctx context.Context, temporalWorkflowID str
 
 	return resp.WorkflowExecutionInfo.Status.String(), nil
 }
+
+// ListApprovals queries a running workflow for the approvals it is waiting on
+func (e *WorkflowExecutor) ListApprovals(ctx context.Context, temporalWorkflowID string) ([]*domain.Approval, error) {
+	value, err := e.client.QueryWorkflow(ctx, temporalWorkflowID, "", workflow.PendingApprovalsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query approvals: %w", err)
+	}
+
+	var pending []workflow.PendingApproval
+	if err := value.Get(&pending); err != nil {
+		return nil, fmt.Errorf("failed to decode approvals: %w", err)
+	}
+
+	approvals := make([]*domain.Approval, 0, len(pending))
+	for _, p := range pending {
+		approvals = append(approvals, &domain.Approval{
+			ID:            p.ID,
+			StepID:        p.StepID,
+			StepName:      p.StepName,
+			Message:       p.Message,
+			DefaultAction: domain.ApprovalAction(p.DefaultAction),
+			RequestedAt:   p.RequestedAt,
+			ExpiresAt:     p.ExpiresAt,
+		})
+	}
+	return approvals, nil
+}
+
+// ListSteps queries a running workflow for its step timeline
+func (e *WorkflowExecutor) ListSteps(ctx context.Context, temporalWorkflowID string) ([]*domain.ExecutionStep, error) {
+	value, err := e.client.QueryWorkflow(ctx, temporalWorkflowID, "", workflow.StepsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query steps: %w", err)
+	}
+
+	var states []workflow.StepState
+	if err := value.Get(&states); err != nil {
+		return nil, fmt.Errorf("failed to decode steps: %w", err)
+	}
+
+	steps := make([]*domain.ExecutionStep, 0, len(states))
+	for _, s := range states {
+		step, err := executionStep(s)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// executionStep converts a step of the workflow timeline
+func executionStep(s workflow.StepState) (*domain.ExecutionStep, error) {
+	step := &domain.ExecutionStep{
+		Position:    int32(s.Position),
+		StepID:      s.StepID,
+		StepName:    s.StepName,
+		StepType:    s.StepType,
+		Status:      domain.ExecutionStepStatus(s.Status),
+		Attempts:    s.Attempts,
+		StartedAt:   s.StartedAt,
+		CompletedAt: s.CompletedAt,
+	}
+	if s.Output != nil {
+		output, err := json.Marshal(s.Output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode step output: %w", err)
+		}
+		step.Output = output
+	}
+	if s.Error != "" {
+		errMsg := s.Error
+		step.Error = &errMsg
+	}
+	return step, nil
+}
+
+// Pause signals a running workflow to pause before its next step
+func (e *WorkflowExecutor) Pause(ctx context.Context, temporalWorkflowID string, pause domain.ExecutionPause) error {
+	if err := e.client.SignalWorkflow(ctx, temporalWorkflowID, "", workflow.PauseSignalName, pauseSignal(pause)); err != nil {
+		return fmt.Errorf("failed to signal pause: %w", err)
+	}
+	return nil
+}
+
+// Resume signals a paused workflow to continue
+func (e *WorkflowExecutor) Resume(ctx context.Context, temporalWorkflowID string, resume domain.ExecutionPause) error {
+	if err := e.client.SignalWorkflow(ctx, temporalWorkflowID, "", workflow.ResumeSignalName, pauseSignal(resume)); err != nil {
+		return fmt.Errorf("failed to signal resume: %w", err)
+	}
+	return nil
+}
+
+func pauseSignal(pause domain.ExecutionPause) workflow.PauseSignal {
+	return workflow.PauseSignal{
+		UserID: pause.UserID.String(),
+		User:   pause.User,
+		At:     pause.At,
+	}
+}
+
+// GetProgress queries a running workflow for its progress
+func (e *WorkflowExecutor) GetProgress(ctx context.Context, temporalWorkflowID string) (*domain.ExecutionProgress, error) {
+	value, err := e.client.QueryWorkflow(ctx, temporalWorkflowID, "", workflow.ProgressQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query progress: %w", err)
+	}
+
+	var p workflow.Progress
+	if err := value.Get(&p); err != nil {
+		return nil, fmt.Errorf("failed to decode progress: %w", err)
+	}
+
+	progress := &domain.ExecutionProgress{
+		Paused:         p.Paused,
+		PausedAt:       p.PausedAt,
+		PausedBy:       p.PausedBy,
+		CompletedSteps: make([]*domain.ExecutionStep, 0, len(p.CompletedSteps)),
+		TotalSteps:     p.TotalSteps,
+		Outputs:        p.Outputs,
+	}
+	if p.CurrentStep != nil {
+		if progress.CurrentStep, err = executionStep(*p.CurrentStep); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range p.CompletedSteps {
+		step, err := executionStep(s)
+		if err != nil {
+			return nil, err
+		}
+		progress.CompletedSteps = append(progress.CompletedSteps, step)
+	}
+	return progress, nil
+}
+
+// SubmitApproval signals an approval decision to a running workflow
+func (e *WorkflowExecutor) SubmitApproval(ctx context.Context, temporalWorkflowID string, decision domain.ApprovalDecision) error {
+	signal := workflow.ApprovalSignal{
+		ApprovalID: decision.ApprovalID,
+		Action:     string(decision.Action),
+		UserID:     decision.UserID.String(),
+		User:       decision.User,
+		Comment:    decision.Comment,
+		DecidedAt:  decision.DecidedAt,
+	}
+	if err := e.client.SignalWorkflow(ctx, temporalWorkflowID, "", workflow.ApprovalSignalName, signal); err != nil {
+		return fmt.Errorf("failed to signal approval: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package temporal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/workflow"
+)
+
+var overlapPolicies = map[string]enumspb.ScheduleOverlapPolicy{
+	domain.ScheduleOverlapSkip:           enumspb.SCHEDULE_OVERLAP_POLICY_SKIP,
+	domain.ScheduleOverlapBufferOne:      enumspb.SCHEDULE_OVERLAP_POLICY_BUFFER_ONE,
+	domain.ScheduleOverlapBufferAll:      enumspb.SCHEDULE_OVERLAP_POLICY_BUFFER_ALL,
+	domain.ScheduleOverlapCancelOther:    enumspb.SCHEDULE_OVERLAP_POLICY_CANCEL_OTHER,
+	domain.ScheduleOverlapTerminateOther: enumspb.SCHEDULE_OVERLAP_POLICY_TERMINATE_OTHER,
+	domain.ScheduleOverlapAllowAll:       enumspb.SCHEDULE_OVERLAP_POLICY_ALLOW_ALL,
+}
+
+// WorkflowScheduler implements port.WorkflowScheduler using Temporal
+// Schedules. Every scheduled workflow has one schedule that starts
+// ScheduledWorkflow, which records and runs each execution.
+type WorkflowScheduler struct {
+	client    client.Client
+	taskQueue string
+}
+
+// NewWorkflowScheduler creates a new workflow scheduler
+func NewWorkflowScheduler(c client.Client) *WorkflowScheduler {
+	return &WorkflowScheduler{
+		client:    c,
+		taskQueue: taskQueueFromEnv(),
+	}
+}
+
+// Sync creates or updates the workflow's schedule, paused unless the
+// workflow is active. Workflows without a cron expression lose their
+// schedule.
+func (s *WorkflowScheduler) Sync(ctx context.Context, wf *domain.Workflow) error {
+	if !wf.IsScheduled() {
+		return s.Delete(ctx, wf.ID)
+	}
+
+	spec, overlap, err := scheduleSpec(wf)
+	if err != nil {
+		return err
+	}
+	scheduleID := domain.ScheduleID(wf.ID)
+	action := &client.ScheduleWorkflowAction{
+		ID:        scheduleID,
+		Workflow:  "ScheduledWorkflow",
+		TaskQueue: s.taskQueue,
+		Args: []interface{}{workflow.ScheduledWorkflowInput{
+			WorkflowID: wf.ID.String(),
+			TenantID:   wf.TenantID.String(),
+			ScheduleID: scheduleID,
+		}},
+	}
+	paused := !wf.CanExecute()
+	note := fmt.Sprintf("workflow %s is %s", wf.Name, wf.Status)
+
+	_, err = s.client.ScheduleClient().Create(ctx, client.ScheduleOptions{
+		ID:      scheduleID,
+		Spec:    *spec,
+		Action:  action,
+		Overlap: overlap,
+		Paused:  paused,
+		Note:    note,
+	})
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, temporal.ErrScheduleAlreadyRunning) {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	// The schedule exists, replace its spec, action, policy and state
+	handle := s.client.ScheduleClient().GetHandle(ctx, scheduleID)
+	err = handle.Update(ctx, client.ScheduleUpdateOptions{
+		DoUpdate: func(input client.ScheduleUpdateInput) (*client.ScheduleUpdate, error) {
+			schedule := input.Description.Schedule
+			schedule.Spec = spec
+			schedule.Action = action
+			if schedule.Policy == nil {
+				schedule.Policy = &client.SchedulePolicies{}
+			}
+			schedule.Policy.Overlap = overlap
+			schedule.State = &client.ScheduleState{Paused: paused, Note: note}
+			return &client.ScheduleUpdate{Schedule: &schedule}, nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update schedule: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the workflow's schedule. Runs that already started are not
+// affected.
+func (s *WorkflowScheduler) Delete(ctx context.Context, workflowID uuid.UUID) error {
+	handle := s.client.ScheduleClient().GetHandle(ctx, domain.ScheduleID(workflowID))
+	if err := handle.Delete(ctx); err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete schedule: %w", err)
+	}
+	return nil
+}
+
+// scheduleSpec converts the workflow's cron expression and schedule options
+// to a Temporal schedule spec and overlap policy
+func scheduleSpec(wf *domain.Workflow) (*client.ScheduleSpec, enumspb.ScheduleOverlapPolicy, error) {
+	spec := &client.ScheduleSpec{
+		CronExpressions: []string{*wf.Schedule},
+	}
+	overlap := enumspb.SCHEDULE_OVERLAP_POLICY_SKIP
+
+	if opts := wf.ScheduleOptions; opts != nil {
+		spec.TimeZoneName = opts.Timezone
+		if opts.Jitter != "" {
+			jitter, err := time.ParseDuration(opts.Jitter)
+			if err != nil {
+				return nil, overlap, fmt.Errorf("invalid schedule jitter %q: %w", opts.Jitter, err)
+			}
+			spec.Jitter = jitter
+		}
+		if opts.Overlap != "" {
+			policy, ok := overlapPolicies[opts.Overlap]
+			if !ok {
+				return nil, overlap, fmt.Errorf("unknown schedule overlap policy %q", opts.Overlap)
+			}
+			overlap = policy
+		}
+	}
+	return spec, overlap, nil
+}
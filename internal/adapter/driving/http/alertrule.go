@@ -136,18 +136,19 @@ func (h *AlertRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, _ := uuid.Parse(user.ID)
 
 	input := port.CreateAlertRuleInput{
-		TenantID:             user.TenantID,
-		Name:                 req.Name,
-		Description:          req.Description,
-		ConditionType:        req.ConditionType,
-		ConditionConfig:      conditionConfig,
-		Severity:             severity,
-		AlertTitleTemplate:   req.AlertTitleTemplate,
-		AlertMessageTemplate: req.AlertMessageTemplate,
-		TriggerWorkflowID:    req.TriggerWorkflowID,
-		TriggerInputTemplate: triggerInputTemplate,
-		CooldownSeconds:      cooldownSeconds,
-		CreatedBy:            userID,
+		TenantID:               user.TenantID,
+		Name:                   req.Name,
+		Description:            req.Description,
+		ConditionType:          req.ConditionType,
+		ConditionConfig:        conditionConfig,
+		Severity:               severity,
+		AlertTitleTemplate:     req.AlertTitleTemplate,
+		AlertMessageTemplate:   req.AlertMessageTemplate,
+		TriggerWorkflowID:      req.TriggerWorkflowID,
+		TriggerWorkflowVersion: req.TriggerWorkflowVersion,
+		TriggerInputTemplate:   triggerInputTemplate,
+		CooldownSeconds:        cooldownSeconds,
+		CreatedBy:              userID,
 	}
 
 	rule, err := h.service.Create(ctx, input)
@@ -204,17 +205,18 @@ func (h *AlertRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := port.UpdateAlertRuleInput{
-		Name:                 req.Name,
-		Description:          req.Description,
-		Enabled:              req.Enabled,
-		ConditionType:        req.ConditionType,
-		ConditionConfig:      conditionConfig,
-		Severity:             severity,
-		AlertTitleTemplate:   req.AlertTitleTemplate,
-		AlertMessageTemplate: req.AlertMessageTemplate,
-		TriggerWorkflowID:    req.TriggerWorkflowID,
-		TriggerInputTemplate: triggerInputTemplate,
-		CooldownSeconds:      cooldownSeconds,
+		Name:                   req.Name,
+		Description:            req.Description,
+		Enabled:                req.Enabled,
+		ConditionType:          req.ConditionType,
+		ConditionConfig:        conditionConfig,
+		Severity:               severity,
+		AlertTitleTemplate:     req.AlertTitleTemplate,
+		AlertMessageTemplate:   req.AlertMessageTemplate,
+		TriggerWorkflowID:      req.TriggerWorkflowID,
+		TriggerWorkflowVersion: req.TriggerWorkflowVersion,
+		TriggerInputTemplate:   triggerInputTemplate,
+		CooldownSeconds:        cooldownSeconds,
 	}
 
 	rule, err := h.service.Update(ctx, id, input)
@@ -261,28 +263,30 @@ func (h *AlertRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 // Request types
 type CreateAlertRuleRequest struct {
-	Name                 string                 `json:"name"`
-	Description          *string                `json:"description,omitempty"`
-	ConditionType        string                 `json:"condition_type"`
-	ConditionConfig      map[string]interface{} `json:"condition_config"`
-	Severity             string                 `json:"severity,omitempty"`
-	AlertTitleTemplate   string                 `json:"alert_title_template"`
-	AlertMessageTemplate *string                `json:"alert_message_template,omitempty"`
-	TriggerWorkflowID    *uuid.UUID             `json:"trigger_workflow_id,omitempty"`
-	TriggerInputTemplate map[string]interface{} `json:"trigger_input_template,omitempty"`
-	CooldownSeconds      *int                   `json:"cooldown_seconds,omitempty"`
+	Name                   string                 `json:"name"`
+	Description            *string                `json:"description,omitempty"`
+	ConditionType          string                 `json:"condition_type"`
+	ConditionConfig        map[string]interface{} `json:"condition_config"`
+	Severity               string                 `json:"severity,omitempty"`
+	AlertTitleTemplate     string                 `json:"alert_title_template"`
+	AlertMessageTemplate   *string                `json:"alert_message_template,omitempty"`
+	TriggerWorkflowID      *uuid.UUID             `json:"trigger_workflow_id,omitempty"`
+	TriggerWorkflowVersion *int32                 `json:"trigger_workflow_version,omitempty"`
+	TriggerInputTemplate   map[string]interface{} `json:"trigger_input_template,omitempty"`
+	CooldownSeconds        *int                   `json:"cooldown_seconds,omitempty"`
 }
 
 type UpdateAlertRuleRequest struct {
-	Name                 *string                `json:"name,omitempty"`
-	Description          *string                `json:"description,omitempty"`
-	Enabled              *bool                  `json:"enabled,omitempty"`
-	ConditionType        *string                `json:"condition_type,omitempty"`
-	ConditionConfig      map[string]interface{} `json:"condition_config,omitempty"`
-	Severity             *string                `json:"severity,omitempty"`
-	AlertTitleTemplate   *string                `json:"alert_title_template,omitempty"`
-	AlertMessageTemplate *string                `json:"alert_message_template,omitempty"`
-	TriggerWorkflowID    *uuid.UUID             `json:"trigger_workflow_id,omitempty"`
-	TriggerInputTemplate map[string]interface{} `json:"trigger_input_template,omitempty"`
-	CooldownSeconds      *int                   `json:"cooldown_seconds,omitempty"`
+	Name                   *string                `json:"name,omitempty"`
+	Description            *string                `json:"description,omitempty"`
+	Enabled                *bool                  `json:"enabled,omitempty"`
+	ConditionType          *string                `json:"condition_type,omitempty"`
+	ConditionConfig        map[string]interface{} `json:"condition_config,omitempty"`
+	Severity               *string                `json:"severity,omitempty"`
+	AlertTitleTemplate     *string                `json:"alert_title_template,omitempty"`
+	AlertMessageTemplate   *string                `json:"alert_message_template,omitempty"`
+	TriggerWorkflowID      *uuid.UUID             `json:"trigger_workflow_id,omitempty"`
+	TriggerWorkflowVersion *int32                 `json:"trigger_workflow_version,omitempty"`
+	TriggerInputTemplate   map[string]interface{} `json:"trigger_input_template,omitempty"`
+	CooldownSeconds        *int                   `json:"cooldown_seconds,omitempty"`
 }
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 
@@ -29,7 +31,16 @@ func (h *ExecutionHandler) Routes() chi.Router {
 
 	r.Get("/", h.List)
 	r.Get("/{id}", h.Get)
+	r.Get("/{id}/children", h.ListChildren)
+	r.Get("/{id}/steps", h.ListSteps)
 	r.Post("/{id}/cancel", h.Cancel)
+	r.Post("/{id}/retry", h.Retry)
+	r.Post("/{id}/pause", h.Pause)
+	r.Post("/{id}/resume", h.Resume)
+	r.Get("/{id}/progress", h.GetProgress)
+	r.Get("/{id}/approvals", h.ListApprovals)
+	r.Post("/{id}/approvals/{approvalId}/approve", h.Approve)
+	r.Post("/{id}/approvals/{approvalId}/reject", h.Reject)
 
 	return r
 }
@@ -89,6 +100,35 @@ func (h *ExecutionHandler) Get(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, DataResponse{Data: execution})
 }
 
+// ListChildren returns the executions started by child_workflow steps
+func (h *ExecutionHandler) ListChildren(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	children, err := h.service.ListChildren(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		slog.Error("failed to list child executions", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list child executions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: children})
+}
+
 // Cancel cancels a running execution
 func (h *ExecutionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -120,3 +160,309 @@ func (h *ExecutionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ListSteps returns the step timeline of an execution
+func (h *ExecutionHandler) ListSteps(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	steps, err := h.service.ListSteps(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		slog.Error("failed to list execution steps", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list execution steps")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: steps})
+}
+
+// Retry starts a new execution that retries a failed or cancelled one
+func (h *ExecutionHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	// The body is optional, retries resume from the failed step by default
+	var req RetryExecutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = domain.RetryModeFromFailedStep
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+
+	execution, err := h.service.Retry(ctx, port.RetryExecutionInput{
+		ExecutionID: id,
+		Mode:        req.Mode,
+		UserID:      userID,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidRetryMode) {
+			respondError(w, http.StatusBadRequest, "invalid retry mode")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowCannotExecute) {
+			respondError(w, http.StatusBadRequest, "workflow cannot be executed")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionCannotRetry) {
+			respondError(w, http.StatusConflict, "execution cannot be retried")
+			return
+		}
+		if errors.Is(err, domain.ErrNoFailedStep) {
+			respondError(w, http.StatusConflict, "execution has no failed step to resume from")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionCompensated) {
+			respondError(w, http.StatusConflict, "execution was compensated and can only be retried from scratch")
+			return
+		}
+		slog.Error("failed to retry execution", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to retry execution")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, DataResponse{Data: execution})
+}
+
+// ListApprovals returns the approvals an execution is waiting on
+func (h *ExecutionHandler) ListApprovals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	approvals, err := h.service.ListApprovals(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		slog.Error("failed to list approvals", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list approvals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: approvals})
+}
+
+// Approve approves a pending approval
+func (h *ExecutionHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, domain.ApprovalActionApprove)
+}
+
+// Reject rejects a pending approval
+func (h *ExecutionHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decideApproval(w, r, domain.ApprovalActionReject)
+}
+
+func (h *ExecutionHandler) decideApproval(w http.ResponseWriter, r *http.Request, action domain.ApprovalAction) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	// The body is optional
+	var req ApprovalDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+	name := user.Email
+	if name == "" {
+		name = user.Name
+	}
+
+	decision, err := h.service.DecideApproval(ctx, port.DecideApprovalInput{
+		ExecutionID: id,
+		ApprovalID:  chi.URLParam(r, "approvalId"),
+		Action:      action,
+		UserID:      userID,
+		User:        name,
+		Comment:     req.Comment,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		if errors.Is(err, domain.ErrApprovalNotFound) {
+			respondError(w, http.StatusNotFound, "approval not found")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionNotRunning) {
+			respondError(w, http.StatusConflict, "execution is not running")
+			return
+		}
+		slog.Error("failed to decide approval", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to decide approval")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: decision})
+}
+
+// Pause asks a running execution to wait before starting its next step
+func (h *ExecutionHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, true)
+}
+
+// Resume lets a paused execution continue
+func (h *ExecutionHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.setPaused(w, r, false)
+}
+
+// setPaused signals the execution and answers 202: the run pauses once the
+// step it is running finishes
+func (h *ExecutionHandler) setPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+	name := user.Email
+	if name == "" {
+		name = user.Name
+	}
+	input := port.PauseExecutionInput{ExecutionID: id, UserID: userID, User: name}
+
+	action := "pause"
+	if paused {
+		err = h.service.Pause(ctx, input)
+	} else {
+		action = "resume"
+		err = h.service.Resume(ctx, input)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionNotRunning) {
+			respondError(w, http.StatusConflict, "execution is not running")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionAlreadyPaused) {
+			respondError(w, http.StatusConflict, "execution is already paused")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionNotPaused) {
+			respondError(w, http.StatusConflict, "execution is not paused")
+			return
+		}
+		slog.Error("failed to "+action+" execution", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to "+action+" execution")
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// GetProgress returns the current step, completed steps and outputs of a
+// running execution
+func (h *ExecutionHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	progress, err := h.service.GetProgress(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			respondError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		if errors.Is(err, domain.ErrExecutionNotRunning) {
+			respondError(w, http.StatusConflict, "execution is not running")
+			return
+		}
+		slog.Error("failed to get execution progress", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get execution progress")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: progress})
+}
+
+// Request types
+
+type ApprovalDecisionRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+type RetryExecutionRequest struct {
+	Mode domain.RetryMode `json:"mode,omitempty"` // from_scratch, from_failed_step (default) or skip_failed_step
+}
@@ -23,6 +23,13 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ValidationErrorResponse represents an error response listing each problem
+// found in the request
+type ValidationErrorResponse struct {
+	Error  string      `json:"error"`
+	Errors interface{} `json:"errors"`
+}
+
 // respondJSON writes a JSON response
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,230 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/orchestrix/orchestrix-api/internal/auth"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/pkg/apperror"
+)
+
+// SecretHandler handles secret HTTP requests. Values are write-only: no
+// response includes them.
+type SecretHandler struct {
+	service port.SecretService
+}
+
+// NewSecretHandler creates a new secret handler
+func NewSecretHandler(service port.SecretService) *SecretHandler {
+	return &SecretHandler{service: service}
+}
+
+// Routes registers secret routes
+func (h *SecretHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+
+	return r
+}
+
+// List returns the secrets of the tenant
+func (h *SecretHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	secrets, err := h.service.List(ctx, user.TenantID)
+	if err != nil {
+		slog.Error("failed to list secrets", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list secrets")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: secrets})
+}
+
+// Get returns a secret
+func (h *SecretHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	secret, err := h.service.Get(ctx, user.TenantID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			respondError(w, http.StatusNotFound, "secret not found")
+			return
+		}
+		slog.Error("failed to get secret", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get secret")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: secret})
+}
+
+// Create creates a secret
+func (h *SecretHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+
+	secret, err := h.service.Create(ctx, port.CreateSecretInput{
+		TenantID:    user.TenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Value:       req.Value,
+		CreatedBy:   &userID,
+	})
+	if err != nil {
+		if h.respondSecretError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrSecretAlreadyExists) {
+			respondError(w, http.StatusConflict, "secret already exists")
+			return
+		}
+		slog.Error("failed to create secret", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to create secret")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, DataResponse{Data: secret})
+}
+
+// Update replaces the description or value of a secret
+func (h *SecretHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req UpdateSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+
+	secret, err := h.service.Update(ctx, port.UpdateSecretInput{
+		TenantID:    user.TenantID,
+		ID:          id,
+		Description: req.Description,
+		Value:       req.Value,
+		UpdatedBy:   &userID,
+	})
+	if err != nil {
+		if h.respondSecretError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			respondError(w, http.StatusNotFound, "secret not found")
+			return
+		}
+		slog.Error("failed to update secret", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to update secret")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: secret})
+}
+
+// Delete deletes a secret
+func (h *SecretHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	userID, _ := uuid.Parse(user.ID)
+
+	if err := h.service.Delete(ctx, user.TenantID, id, &userID); err != nil {
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			respondError(w, http.StatusNotFound, "secret not found")
+			return
+		}
+		slog.Error("failed to delete secret", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to delete secret")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondSecretError writes the errors shared by create and update, and
+// reports whether it did
+func (h *SecretHandler) respondSecretError(w http.ResponseWriter, err error) bool {
+	if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidSecret) {
+		respondFieldErrors(w, appErr)
+		return true
+	}
+	if errors.Is(err, domain.ErrSecretsNotConfigured) {
+		respondError(w, http.StatusServiceUnavailable, "secrets are not configured: set SECRETS_MASTER_KEY")
+		return true
+	}
+	return false
+}
+
+// Request types
+type CreateSecretRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	Value       string  `json:"value"`
+}
+
+type UpdateSecretRequest struct {
+	Description *string `json:"description,omitempty"`
+	Value       *string `json:"value,omitempty"`
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/orchestrix/orchestrix-api/internal/auth"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+// TemplateHandler handles workflow template HTTP requests
+type TemplateHandler struct {
+	service port.TemplateService
+}
+
+// NewTemplateHandler creates a new template handler
+func NewTemplateHandler(service port.TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: service}
+}
+
+// Routes registers workflow template routes
+func (h *TemplateHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.List)
+	r.Get("/{name}", h.Get)
+
+	return r
+}
+
+// List returns the latest version of each template
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: h.service.List(ctx)})
+}
+
+// Get returns a template, at the latest version or the one in ?version=
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	version := 0
+	if s := r.URL.Query().Get("version"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 {
+			respondError(w, http.StatusBadRequest, "invalid version")
+			return
+		}
+		version = v
+	}
+
+	template, err := h.service.Get(ctx, chi.URLParam(r, "name"), version)
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateNotFound) {
+			respondError(w, http.StatusNotFound, "workflow template not found")
+			return
+		}
+		slog.Error("failed to get workflow template", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get workflow template")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: template})
+}
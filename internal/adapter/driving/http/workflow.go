@@ -3,8 +3,10 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
@@ -13,16 +15,20 @@ import (
 	"github.com/orchestrix/orchestrix-api/internal/auth"
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
 	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/pkg/apperror"
+	"github.com/orchestrix/orchestrix-api/pkg/validation"
 )
 
 // WorkflowHandler handles workflow HTTP requests
 type WorkflowHandler struct {
-	service port.WorkflowService
+	service   port.WorkflowService
+	bundles   port.BundleService
+	templates port.TemplateService
 }
 
 // NewWorkflowHandler creates a new workflow handler
-func NewWorkflowHandler(service port.WorkflowService) *WorkflowHandler {
-	return &WorkflowHandler{service: service}
+func NewWorkflowHandler(service port.WorkflowService, bundles port.BundleService, templates port.TemplateService) *WorkflowHandler {
+	return &WorkflowHandler{service: service, bundles: bundles, templates: templates}
 }
 
 // Routes registers workflow routes
@@ -31,11 +37,20 @@ func (h *WorkflowHandler) Routes() chi.Router {
 
 	r.Get("/", h.List)
 	r.Post("/", h.Create)
+	r.Post("/validate", h.Validate)
+	r.Post("/import", h.Import)
+	r.Post("/from-template", h.CreateFromTemplate)
 	r.Get("/{id}", h.Get)
 	r.Put("/{id}", h.Update)
 	r.Delete("/{id}", h.Delete)
 	r.Post("/{id}/execute", h.Execute)
+	r.Post("/{id}/dry-run", h.DryRun)
+	r.Get("/{id}/export", h.Export)
 	r.Get("/{id}/executions", h.ListExecutions)
+	r.Get("/{id}/versions", h.ListVersions)
+	r.Get("/{id}/versions/diff", h.DiffVersions)
+	r.Get("/{id}/versions/{version}", h.GetVersion)
+	r.Post("/{id}/versions/{version}/rollback", h.Rollback)
 
 	return r
 }
@@ -81,6 +96,12 @@ func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "yaml" {
+		respondError(w, http.StatusBadRequest, "format must be json or yaml")
+		return
+	}
+
 	workflow, err := h.service.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, domain.ErrWorkflowNotFound) {
@@ -92,6 +113,18 @@ func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The YAML document can be sent back as is to create or update a workflow
+	if format == "yaml" {
+		respondYAML(w, http.StatusOK, WorkflowDocument{
+			Name:            workflow.Name,
+			Description:     workflow.Description,
+			Definition:      workflow.Definition,
+			Schedule:        workflow.Schedule,
+			ScheduleOptions: workflow.ScheduleOptions,
+		}, rebaseComments(workflow.DefinitionComments, "$", "$.definition"))
+		return
+	}
+
 	respondJSON(w, http.StatusOK, DataResponse{Data: workflow})
 }
 
@@ -105,7 +138,8 @@ func (h *WorkflowHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CreateWorkflowRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	comments, err := decodeBody(r, &req)
+	if err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
@@ -115,25 +149,39 @@ func (h *WorkflowHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	definition, err := json.Marshal(req.Definition)
-	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid definition")
-		return
+	var definition []byte
+	if req.Definition != nil {
+		definition, err = json.Marshal(req.Definition)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "invalid definition")
+			return
+		}
 	}
 
 	userID, _ := uuid.Parse(user.ID)
 
 	input := port.CreateWorkflowInput{
-		TenantID:    user.TenantID,
-		Name:        req.Name,
-		Description: req.Description,
-		Definition:  definition,
-		Schedule:    req.Schedule,
-		CreatedBy:   &userID,
+		TenantID:           user.TenantID,
+		Name:               req.Name,
+		Description:        req.Description,
+		Definition:         definition,
+		DefinitionComments: rebaseComments(comments, "$.definition", "$"),
+		Schedule:           req.Schedule,
+		ScheduleOptions:    req.ScheduleOptions,
+		CreatedBy:          &userID,
 	}
 
 	workflow, err := h.service.Create(ctx, input)
 	if err != nil {
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidSchedule) {
+			respondFieldErrors(w, appErr)
+			return
+		}
 		slog.Error("failed to create workflow", "error", err)
 		respondError(w, http.StatusInternalServerError, "failed to create workflow")
 		return
@@ -142,6 +190,92 @@ func (h *WorkflowHandler) Create(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, DataResponse{Data: workflow})
 }
 
+// CreateFromTemplate creates a draft workflow from a catalog template
+func (h *WorkflowHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req CreateFromTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Template == "" {
+		respondError(w, http.StatusBadRequest, "template is required")
+		return
+	}
+	if req.Version < 0 {
+		respondError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	var userID *uuid.UUID
+	if parsed, err := uuid.Parse(user.ID); err == nil {
+		userID = &parsed
+	}
+
+	workflow, err := h.templates.Instantiate(ctx, port.InstantiateTemplateInput{
+		TenantID:    user.TenantID,
+		Template:    req.Template,
+		Version:     req.Version,
+		Name:        req.Name,
+		Description: req.Description,
+		Parameters:  req.Parameters,
+		CreatedBy:   userID,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTemplateNotFound) {
+			respondError(w, http.StatusNotFound, "workflow template not found")
+			return
+		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidTemplateParameters) {
+			respondFieldErrors(w, appErr)
+			return
+		}
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		slog.Error("failed to create workflow from template", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to create workflow from template")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, DataResponse{Data: workflow})
+}
+
+// Validate checks a workflow definition without saving it
+func (h *WorkflowHandler) Validate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req ValidateWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	errs := h.service.ValidateDefinition(ctx, req.Definition)
+	if errs == nil {
+		errs = []domain.DefinitionError{}
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: ValidationResult{
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	}})
+}
+
 // Update updates a workflow
 func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -158,7 +292,8 @@ func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateWorkflowRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	comments, err := decodeBody(r, &req)
+	if err != nil {
 		respondError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
@@ -175,11 +310,16 @@ func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := port.UpdateWorkflowInput{
-		Name:        req.Name,
-		Description: req.Description,
-		Definition:  definition,
-		Schedule:    req.Schedule,
-		Status:      status,
+		Name:               req.Name,
+		Description:        req.Description,
+		Definition:         definition,
+		DefinitionComments: rebaseComments(comments, "$.definition", "$"),
+		Schedule:           req.Schedule,
+		ScheduleOptions:    req.ScheduleOptions,
+		Status:             status,
+	}
+	if userID, err := uuid.Parse(user.ID); err == nil {
+		input.UpdatedBy = &userID
 	}
 
 	workflow, err := h.service.Update(ctx, id, input)
@@ -188,6 +328,15 @@ func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondError(w, http.StatusNotFound, "workflow not found")
 			return
 		}
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidSchedule) {
+			respondFieldErrors(w, appErr)
+			return
+		}
 		slog.Error("failed to update workflow", "error", err)
 		respondError(w, http.StatusInternalServerError, "failed to update workflow")
 		return
@@ -245,16 +394,29 @@ func (h *WorkflowHandler) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	execution, err := h.service.Execute(ctx, id, user.ID, req.Input)
+	if req.Version < 0 {
+		respondError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	execution, err := h.service.ExecuteVersion(ctx, id, req.Version, user.ID, req.Input)
 	if err != nil {
 		if errors.Is(err, domain.ErrWorkflowNotFound) {
 			respondError(w, http.StatusNotFound, "workflow not found")
 			return
 		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
 		if errors.Is(err, domain.ErrWorkflowCannotExecute) {
 			respondError(w, http.StatusBadRequest, "workflow cannot be executed")
 			return
 		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidInput) {
+			respondFieldErrors(w, appErr)
+			return
+		}
 		slog.Error("failed to execute workflow", "error", err)
 		respondError(w, http.StatusInternalServerError, "failed to execute workflow")
 		return
@@ -263,6 +425,68 @@ func (h *WorkflowHandler) Execute(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusAccepted, DataResponse{Data: execution})
 }
 
+// DryRun simulates a workflow without side effects and returns the step
+// trace with rendered configs
+func (h *WorkflowHandler) DryRun(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req DryRunWorkflowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Version < 0 {
+		respondError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	result, err := h.service.DryRun(ctx, id, port.DryRunInput{
+		Version:  req.Version,
+		Input:    req.Input,
+		Fixtures: req.Fixtures,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidInput) {
+			respondFieldErrors(w, appErr)
+			return
+		}
+		if errors.Is(err, domain.ErrDryRunTimedOut) {
+			respondError(w, http.StatusGatewayTimeout, "workflow dry run timed out")
+			return
+		}
+		slog.Error("failed to dry run workflow", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to dry run workflow")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: result})
+}
+
 // ListExecutions returns executions for a workflow
 func (h *WorkflowHandler) ListExecutions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -295,24 +519,393 @@ func (h *WorkflowHandler) ListExecutions(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// ListVersions returns the published versions of a workflow, newest first
+func (h *WorkflowHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	page, limit := parsePagination(r)
+
+	result, err := h.service.ListVersions(ctx, id, page, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		slog.Error("failed to list workflow versions", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to list workflow versions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, PaginatedResponse{
+		Data:  result.Versions,
+		Total: result.Total,
+		Page:  int32(page),
+		Limit: int32(limit),
+	})
+}
+
+// GetVersion returns a single version of a workflow
+func (h *WorkflowHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	version, err := parseVersion(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	result, err := h.service.GetVersion(ctx, id, version)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
+		slog.Error("failed to get workflow version", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to get workflow version")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: result})
+}
+
+// DiffVersions returns the changes between two versions of a workflow
+func (h *WorkflowHandler) DiffVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	from, err := parseVersion(r.URL.Query().Get("from"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid from version")
+		return
+	}
+	to, err := parseVersion(r.URL.Query().Get("to"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid to version")
+		return
+	}
+
+	changes, err := h.service.DiffVersions(ctx, id, from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
+		slog.Error("failed to diff workflow versions", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to diff workflow versions")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: VersionDiff{
+		From:    from,
+		To:      to,
+		Changes: changes,
+	}})
+}
+
+// Rollback restores the definition of an earlier version as a new version
+func (h *WorkflowHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	version, err := parseVersion(chi.URLParam(r, "version"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid version")
+		return
+	}
+
+	var userID *uuid.UUID
+	if parsed, err := uuid.Parse(user.ID); err == nil {
+		userID = &parsed
+	}
+
+	workflow, err := h.service.Rollback(ctx, id, version, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrWorkflowVersionNotFound) {
+			respondError(w, http.StatusNotFound, "workflow version not found")
+			return
+		}
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		slog.Error("failed to roll back workflow", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to roll back workflow")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: workflow})
+}
+
+// Export returns a workflow as a portable bundle, with the child workflows,
+// alert rules and metric definitions it depends on
+func (h *WorkflowHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "yaml" {
+		respondError(w, http.StatusBadRequest, "format must be json or yaml")
+		return
+	}
+
+	bundle, err := h.bundles.Export(ctx, user.TenantID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkflowNotFound) {
+			respondError(w, http.StatusNotFound, "workflow not found")
+			return
+		}
+		if errors.Is(err, domain.ErrBundleDependencyNotFound) {
+			respondError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		slog.Error("failed to export workflow", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to export workflow")
+		return
+	}
+
+	// The bundle isn't wrapped, so it can be sent back as is to import it
+	if format == "yaml" {
+		respondYAML(w, http.StatusOK, bundle, nil)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bundle)
+}
+
+// Import creates the workflows, alert rules and metric definitions of a
+// bundle. on_conflict decides what happens to objects whose name is taken;
+// keep_status=true gives workflows their bundled status instead of draft.
+func (h *WorkflowHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := auth.FromContext(ctx)
+	if user == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	strategy := domain.ConflictSkip
+	if value := r.URL.Query().Get("on_conflict"); value != "" {
+		strategy = domain.ConflictStrategy(value)
+	}
+	if !strategy.IsValid() {
+		respondError(w, http.StatusBadRequest, "on_conflict must be skip, overwrite or rename")
+		return
+	}
+
+	keepStatus := false
+	if value := r.URL.Query().Get("keep_status"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "keep_status must be true or false")
+			return
+		}
+		keepStatus = parsed
+	}
+
+	var bundle domain.WorkflowBundle
+	if _, err := decodeBody(r, &bundle); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	var userID *uuid.UUID
+	if parsed, err := uuid.Parse(user.ID); err == nil {
+		userID = &parsed
+	}
+
+	result, err := h.bundles.Import(ctx, port.ImportBundleInput{
+		TenantID:   user.TenantID,
+		Bundle:     &bundle,
+		Strategy:   strategy,
+		KeepStatus: keepStatus,
+		UserID:     userID,
+	})
+	if err != nil {
+		var bundleErr *domain.BundleValidationError
+		if errors.As(err, &bundleErr) {
+			respondJSON(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+				Error:  "invalid workflow bundle",
+				Errors: bundleErr.Errors,
+			})
+			return
+		}
+		var validationErr *domain.DefinitionValidationError
+		if errors.As(err, &validationErr) {
+			respondDefinitionErrors(w, validationErr.Errors)
+			return
+		}
+		if appErr, ok := apperror.GetAppError(err); ok && errors.Is(err, domain.ErrInvalidSchedule) {
+			respondFieldErrors(w, appErr)
+			return
+		}
+		slog.Error("failed to import workflow bundle", "error", err)
+		respondError(w, http.StatusInternalServerError, "failed to import workflow bundle")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, DataResponse{Data: result})
+}
+
 // Request/Response types
 type CreateWorkflowRequest struct {
-	Name        string                 `json:"name"`
-	Description *string                `json:"description"`
-	Definition  map[string]interface{} `json:"definition"`
-	Schedule    *string                `json:"schedule"`
+	Name            string                  `json:"name"`
+	Description     *string                 `json:"description"`
+	Definition      map[string]interface{}  `json:"definition"`
+	Schedule        *string                 `json:"schedule"`
+	ScheduleOptions *domain.ScheduleOptions `json:"schedule_options"`
 }
 
 type UpdateWorkflowRequest struct {
-	Name        *string                `json:"name"`
+	Name            *string                 `json:"name"`
+	Description     *string                 `json:"description"`
+	Definition      map[string]interface{}  `json:"definition"`
+	Schedule        *string                 `json:"schedule"` // "" removes the schedule
+	ScheduleOptions *domain.ScheduleOptions `json:"schedule_options"`
+	Status          *string                 `json:"status"`
+}
+
+type CreateFromTemplateRequest struct {
+	Template    string                 `json:"template"`
+	Version     int                    `json:"version,omitempty"` // 0 uses the latest version
+	Name        string                 `json:"name,omitempty"`    // defaults to the template name
 	Description *string                `json:"description"`
-	Definition  map[string]interface{} `json:"definition"`
-	Schedule    *string                `json:"schedule"`
-	Status      *string                `json:"status"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// WorkflowDocument is a workflow as returned by GET ?format=yaml, in the
+// shape create and update requests accept
+type WorkflowDocument struct {
+	Name            string                  `json:"name"`
+	Description     *string                 `json:"description,omitempty"`
+	Definition      json.RawMessage         `json:"definition,omitempty"`
+	Schedule        *string                 `json:"schedule,omitempty"`
+	ScheduleOptions *domain.ScheduleOptions `json:"schedule_options,omitempty"`
+}
+
+type ValidateWorkflowRequest struct {
+	Definition json.RawMessage `json:"definition"`
+}
+
+type ValidationResult struct {
+	Valid  bool                     `json:"valid"`
+	Errors []domain.DefinitionError `json:"errors"`
 }
 
 type ExecuteWorkflowRequest struct {
-	Input map[string]interface{} `json:"input"`
+	Input   map[string]interface{} `json:"input"`
+	Version int32                  `json:"version,omitempty"` // 0 runs the latest version
+}
+
+type DryRunWorkflowRequest struct {
+	Input    map[string]interface{} `json:"input"`
+	Version  int32                  `json:"version,omitempty"`  // 0 simulates the latest version
+	Fixtures map[string]interface{} `json:"fixtures,omitempty"` // simulated step outputs by step ID
+}
+
+type VersionDiff struct {
+	From    int32                     `json:"from"`
+	To      int32                     `json:"to"`
+	Changes []domain.DefinitionChange `json:"changes"`
+}
+
+// respondDefinitionErrors writes the problems found in a workflow definition
+func respondDefinitionErrors(w http.ResponseWriter, errs []domain.DefinitionError) {
+	respondJSON(w, http.StatusUnprocessableEntity, ValidationErrorResponse{
+		Error:  "invalid workflow definition",
+		Errors: errs,
+	})
+}
+
+// respondFieldErrors writes a validation error with one entry per field
+func respondFieldErrors(w http.ResponseWriter, appErr *apperror.AppError) {
+	fields, _ := appErr.Details["fields"].(map[string]string)
+	errs := make([]validation.FieldError, 0, len(fields))
+	for field, message := range fields {
+		errs = append(errs, validation.FieldError{Field: field, Message: message})
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+
+	respondJSON(w, appErr.HTTPStatus, ValidationErrorResponse{
+		Error:  appErr.Message,
+		Errors: errs,
+	})
+}
+
+// parseVersion parses a workflow version number
+func parseVersion(s string) (int32, error) {
+	v, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	if v < 1 {
+		return 0, errors.New("version must be positive")
+	}
+	return int32(v), nil
 }
 
 // parsePagination extracts pagination parameters from the request
@@ -0,0 +1,313 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+// yamlContentType is the content type of YAML responses
+const yamlContentType = "application/yaml"
+
+// maxYAMLDepth bounds the nesting of a YAML document, aliases included, so
+// a body can't expand into an arbitrarily large JSON value
+const maxYAMLDepth = 64
+
+// isYAML reports whether the request body is YAML
+func isYAML(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return true
+	}
+	return false
+}
+
+// decodeBody decodes a JSON or YAML request body into v. The comments of a
+// YAML body are returned by the path of the node they belong to.
+func decodeBody(r *http.Request, v interface{}) (domain.DefinitionComments, error) {
+	if !isYAML(r) {
+		return nil, json.NewDecoder(r.Body).Decode(v)
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	body, comments, err := yamlToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return comments, json.Unmarshal(body, v)
+}
+
+// respondYAML writes a YAML response with comments reattached by path
+func respondYAML(w http.ResponseWriter, status int, data interface{}, comments domain.DefinitionComments) {
+	body, err := json.Marshal(data)
+	if err == nil {
+		body, err = jsonToYAML(body, comments)
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to encode YAML")
+		return
+	}
+	w.Header().Set("Content-Type", yamlContentType)
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// yamlToJSON converts a YAML document to JSON, keeping the order of keys.
+// JSON can't hold comments, so they are returned separately, by the path of
+// the node they belong to, e.g. "$.steps[0].config.url".
+func yamlToJSON(data []byte) ([]byte, domain.DefinitionComments, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, errors.New("empty YAML document")
+	}
+
+	c := &yamlDecoder{comments: domain.DefinitionComments{}}
+	root := doc.Content[0]
+	c.comment("$", doc.HeadComment+root.HeadComment, root.LineComment, root.FootComment+doc.FootComment)
+	if err := c.write(root, "$", 0); err != nil {
+		return nil, nil, err
+	}
+	if len(c.comments) == 0 {
+		c.comments = nil
+	}
+	return c.buf.Bytes(), c.comments, nil
+}
+
+type yamlDecoder struct {
+	buf      bytes.Buffer
+	comments domain.DefinitionComments
+}
+
+func (c *yamlDecoder) comment(path, head, line, foot string) {
+	if head == "" && line == "" && foot == "" {
+		return
+	}
+	c.comments[path] = domain.DefinitionComment{Head: head, Line: line, Foot: foot}
+}
+
+func (c *yamlDecoder) write(node *yaml.Node, path string, depth int) error {
+	if depth > maxYAMLDepth {
+		return fmt.Errorf("%s: nested more than %d levels deep", path, maxYAMLDepth)
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		return c.write(node.Alias, path, depth+1)
+
+	case yaml.MappingNode:
+		c.buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode || key.Tag == "!!merge" {
+				return fmt.Errorf("%s: keys must be strings", path)
+			}
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			name, _ := json.Marshal(key.Value)
+			c.buf.Write(name)
+			c.buf.WriteByte(':')
+
+			child := childPath(path, key.Value)
+			line := key.LineComment
+			if line == "" {
+				line = value.LineComment
+			}
+			c.comment(child, key.HeadComment+value.HeadComment, line, key.FootComment+value.FootComment)
+			if err := c.write(value, child, depth+1); err != nil {
+				return err
+			}
+		}
+		c.buf.WriteByte('}')
+
+	case yaml.SequenceNode:
+		c.buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				c.buf.WriteByte(',')
+			}
+			child := fmt.Sprintf("%s[%d]", path, i)
+			c.comment(child, item.HeadComment, item.LineComment, item.FootComment)
+			if err := c.write(item, child, depth+1); err != nil {
+				return err
+			}
+		}
+		c.buf.WriteByte(']')
+
+	case yaml.ScalarNode:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%s: %s can't be represented in JSON", path, node.Value)
+		}
+		c.buf.Write(data)
+
+	default:
+		return fmt.Errorf("%s: unsupported YAML node", path)
+	}
+	return nil
+}
+
+// jsonToYAML converts a JSON document to YAML, keeping the order of keys and
+// attaching the comments recorded by yamlToJSON
+func jsonToYAML(data []byte, comments domain.DefinitionComments) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	e := &yamlEncoder{dec: dec, comments: comments}
+
+	root, err := e.node("$")
+	if err != nil {
+		return nil, err
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}
+	if comment, ok := comments["$"]; ok {
+		doc.HeadComment = comment.Head
+		root.LineComment = comment.Line
+		doc.FootComment = comment.Foot
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type yamlEncoder struct {
+	dec      *json.Decoder
+	comments domain.DefinitionComments
+}
+
+func (e *yamlEncoder) node(path string) (*yaml.Node, error) {
+	token, err := e.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			for e.dec.More() {
+				token, err := e.dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				name, _ := token.(string)
+				child := childPath(path, name)
+				value, err := e.node(child)
+				if err != nil {
+					return nil, err
+				}
+				key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}
+				if comment, ok := e.comments[child]; ok {
+					key.HeadComment = comment.Head
+					key.FootComment = comment.Foot
+					// Line comments follow scalar values and keys of collections
+					if value.Kind == yaml.ScalarNode {
+						value.LineComment = comment.Line
+					} else {
+						key.LineComment = comment.Line
+					}
+				}
+				node.Content = append(node.Content, key, value)
+			}
+			_, err := e.dec.Token()
+			return node, err
+		case '[':
+			node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for i := 0; e.dec.More(); i++ {
+				child := fmt.Sprintf("%s[%d]", path, i)
+				item, err := e.node(child)
+				if err != nil {
+					return nil, err
+				}
+				if comment, ok := e.comments[child]; ok {
+					item.HeadComment = comment.Head
+					item.LineComment = comment.Line
+					item.FootComment = comment.Foot
+				}
+				node.Content = append(node.Content, item)
+			}
+			_, err := e.dec.Token()
+			return node, err
+		}
+	case string:
+		node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}
+		if strings.Contains(t, "\n") {
+			node.Style = yaml.LiteralStyle
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("%s: unexpected JSON token %v", path, token)
+}
+
+var plainKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// childPath returns the path of a key of the object at path. Keys that
+// aren't plain words are quoted, e.g. $.headers["Content-Type"].
+func childPath(path, key string) string {
+	if plainKey.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// rebaseComments moves the comments of the node at from and its children to
+// the node at to, e.g. "$.definition.steps" becomes "$.steps" when moved from
+// "$.definition" to "$". Comments outside from are dropped.
+func rebaseComments(comments domain.DefinitionComments, from, to string) domain.DefinitionComments {
+	rebased := domain.DefinitionComments{}
+	for path, comment := range comments {
+		switch {
+		case path == from:
+			rebased[to] = comment
+		case strings.HasPrefix(path, from+".") || strings.HasPrefix(path, from+"["):
+			rebased[to+path[len(from):]] = comment
+		}
+	}
+	if len(rebased) == 0 {
+		return nil
+	}
+	return rebased
+}
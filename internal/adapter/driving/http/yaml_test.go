@@ -0,0 +1,131 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+const workflowYAML = `name: restart-api
+definition:
+  # Check before restarting
+  steps:
+    - id: check
+      type: http
+      config:
+        url: https://api.internal/health # the load balancer's probe
+        headers:
+          Content-Type: application/json
+        success_codes:
+          - 200
+          - 204
+    # Tell the team
+    - id: notify
+      type: notify
+      config:
+        message: |
+          API restarted
+          by ${input.user}
+  timeout: 5m
+`
+
+func TestYAMLToJSON(t *testing.T) {
+	t.Run("converts to JSON in key order", func(t *testing.T) {
+		data, _, err := yamlToJSON([]byte(workflowYAML))
+
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"restart-api","definition":{"steps":[`+
+			`{"id":"check","type":"http","config":{"url":"https://api.internal/health","headers":{"Content-Type":"application/json"},"success_codes":[200,204]}},`+
+			`{"id":"notify","type":"notify","config":{"message":"API restarted\nby ${input.user}\n"}}],"timeout":"5m"}}`, string(data))
+	})
+
+	t.Run("records comments by path", func(t *testing.T) {
+		_, comments, err := yamlToJSON([]byte(workflowYAML))
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.DefinitionComments{
+			"$.definition.steps":               {Head: "# Check before restarting"},
+			"$.definition.steps[0].config.url": {Line: "# the load balancer's probe"},
+			"$.definition.steps[1]":            {Head: "# Tell the team"},
+		}, comments)
+	})
+
+	t.Run("expands aliases", func(t *testing.T) {
+		data, _, err := yamlToJSON([]byte("base: &base {type: log}\nsteps: [*base, *base]\n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, `{"base":{"type":"log"},"steps":[{"type":"log"},{"type":"log"}]}`, string(data))
+	})
+
+	t.Run("rejects documents JSON can't hold", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"empty":          "",
+			"invalid":        "steps: [",
+			"non-string key": "{[a]: 1}",
+			"merge key":      "base: &base {a: 1}\nother:\n  <<: *base\n",
+			"infinity":       "limit: .inf\n",
+		} {
+			_, _, err := yamlToJSON([]byte(doc))
+			assert.Error(t, err, name)
+		}
+	})
+}
+
+func TestJSONToYAML(t *testing.T) {
+	t.Run("round trips with comments", func(t *testing.T) {
+		data, comments, err := yamlToJSON([]byte(workflowYAML))
+		require.NoError(t, err)
+
+		out, err := jsonToYAML(data, comments)
+
+		require.NoError(t, err)
+		assert.Equal(t, workflowYAML, string(out))
+	})
+
+	t.Run("keeps the types of scalars", func(t *testing.T) {
+		out, err := jsonToYAML([]byte(`{"a":"true","b":true,"c":"10","d":10,"e":1.5,"f":null}`), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "a: \"true\"\nb: true\nc: \"10\"\nd: 10\ne: 1.5\nf: null\n", string(out))
+	})
+}
+
+func TestRebaseComments(t *testing.T) {
+	comments := domain.DefinitionComments{
+		"$.name":                {Line: "# dropped"},
+		"$.definition":          {Foot: "# end"},
+		"$.definition.steps[0]": {Head: "# first"},
+		"$.definitions":         {Head: "# not a child"},
+	}
+
+	rebased := rebaseComments(comments, "$.definition", "$")
+
+	assert.Equal(t, domain.DefinitionComments{
+		"$":          {Foot: "# end"},
+		"$.steps[0]": {Head: "# first"},
+	}, rebased)
+	assert.Equal(t, domain.DefinitionComments{
+		"$.definition":          {Foot: "# end"},
+		"$.definition.steps[0]": {Head: "# first"},
+	}, rebaseComments(rebased, "$", "$.definition"))
+	assert.Nil(t, rebaseComments(comments, "$.schedule", "$"))
+}
+
+func TestIsYAML(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"application/yaml":         true,
+		"application/x-yaml":       true,
+		"text/yaml; charset=utf-8": true,
+		"application/json":         false,
+		"":                         false,
+	} {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(""))
+		r.Header.Set("Content-Type", contentType)
+		assert.Equal(t, want, isYAML(r), contentType)
+	}
+}
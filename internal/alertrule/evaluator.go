@@ -15,7 +15,10 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/service"
 	"github.com/orchestrix/orchestrix-api/internal/db"
+	"github.com/orchestrix/orchestrix-api/pkg/apperror"
 	"github.com/orchestrix/orchestrix-api/pkg/temporal"
 )
 
@@ -250,6 +253,21 @@ func (e *Evaluator) triggerWorkflow(ctx context.Context, tenantID uuid.UUID, rul
 		return ErrTenantMismatch
 	}
 
+	// Run the pinned version if the rule has one, otherwise the latest
+	definition := workflow.Definition
+	version := workflow.Version
+	if rule.TriggerWorkflowVersion != nil && *rule.TriggerWorkflowVersion != workflow.Version {
+		pinned, err := e.queries.GetWorkflowVersion(ctx, db.GetWorkflowVersionParams{
+			WorkflowID: workflowUUID,
+			Version:    *rule.TriggerWorkflowVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("get workflow version %d: %w", *rule.TriggerWorkflowVersion, err)
+		}
+		definition = pinned.Definition
+		version = pinned.Version
+	}
+
 	// Build workflow input from template
 	var workflowInput map[string]interface{}
 	if len(rule.TriggerInputTemplate) > 0 {
@@ -271,17 +289,27 @@ func (e *Evaluator) triggerWorkflow(ctx context.Context, tenantID uuid.UUID, rul
 		}
 	}
 
+	workflowInput, err = service.PrepareInput(&domain.Workflow{Definition: definition}, workflowInput)
+	if err != nil {
+		if appErr, ok := apperror.GetAppError(err); ok {
+			slog.Warn("invalid workflow input from alert rule", "rule_id", rule.ID, "workflow_id", workflowUUID, "fields", appErr.Details["fields"])
+		}
+		return fmt.Errorf("workflow input: %w", err)
+	}
+
 	inputJSON, _ := json.Marshal(workflowInput)
 
 	// Create execution record
 	temporalWorkflowID := "alert-" + alert.ID.String()
 	execution, err := e.queries.CreateExecution(ctx, db.CreateExecutionParams{
+		ID:                 uuid.New(),
 		TenantID:           tenantID,
 		WorkflowID:         workflowUUID,
 		TemporalWorkflowID: &temporalWorkflowID,
 		Status:             "pending",
 		Input:              inputJSON,
 		TriggeredBy:        stringPtr("alert_rule:" + rule.ID.String()),
+		WorkflowVersion:    &version,
 	})
 	if err != nil {
 		return err
@@ -296,14 +324,14 @@ func (e *Evaluator) triggerWorkflow(ctx context.Context, tenantID uuid.UUID, rul
 	}
 
 	// Check if workflow has a dynamic definition
-	var definition map[string]interface{}
-	if len(workflow.Definition) > 0 {
-		json.Unmarshal(workflow.Definition, &definition)
+	var parsed map[string]interface{}
+	if len(definition) > 0 {
+		json.Unmarshal(definition, &parsed)
 	}
 
 	var run temporal.WorkflowRun
 	var startErr error
-	if steps, ok := definition["steps"]; ok && steps != nil {
+	if steps, ok := parsed["steps"]; ok && steps != nil {
 		// Dynamic workflow
 		run, startErr = temporal.ExecuteWorkflow(
 			ctx,
@@ -312,8 +340,10 @@ func (e *Evaluator) triggerWorkflow(ctx context.Context, tenantID uuid.UUID, rul
 			map[string]interface{}{
 				"workflow_id":  workflowUUID.String(),
 				"execution_id": execution.ID.String(),
-				"definition":   workflow.Definition,
+				"name":         workflow.Name,
+				"definition":   definition,
 				"input":        workflowInput,
+				"tenant_id":    tenantID.String(),
 			},
 		)
 	} else {
@@ -339,12 +369,12 @@ func (e *Evaluator) triggerWorkflow(ctx context.Context, tenantID uuid.UUID, rul
 		return startErr
 	}
 
-	// Update execution with run ID
-	e.queries.UpdateExecutionRunID(ctx, db.UpdateExecutionRunIDParams{
-		ID:            execution.ID,
-		TemporalRunID: stringPtr(run.GetRunID()),
-		Status:        "running",
-		StartedAt:     pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	// Update execution with run ID, keeping the outcome of runs the worker
+	// already finished
+	e.queries.MarkExecutionRunning(ctx, db.MarkExecutionRunningParams{
+		ID:                 execution.ID,
+		TemporalWorkflowID: &temporalWorkflowID,
+		TemporalRunID:      stringPtr(run.GetRunID()),
 	})
 
 	slog.Info("workflow triggered for alert",
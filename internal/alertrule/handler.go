@@ -1,7 +1,10 @@
 package alertrule
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -68,6 +71,9 @@ type CreateRequest struct {
 	TriggerWorkflowID    *string                `json:"trigger_workflow_id,omitempty"`
 	TriggerInputTemplate map[string]interface{} `json:"trigger_input_template,omitempty"`
 	CooldownSeconds      *int                   `json:"cooldown_seconds,omitempty"`
+
+	// Pins the triggered workflow to a version, 0 follows the latest version
+	TriggerWorkflowVersion *int32 `json:"trigger_workflow_version,omitempty"`
 }
 
 // List returns all alert rules
@@ -182,23 +188,34 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		triggerWorkflowID = pgtype.UUID{Bytes: id, Valid: true}
 	}
 
+	var triggerWorkflowVersion *int32
+	if req.TriggerWorkflowVersion != nil {
+		var err error
+		triggerWorkflowVersion, err = h.checkWorkflowVersion(ctx, user.TenantID, triggerWorkflowID, *req.TriggerWorkflowVersion)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	userID, _ := uuid.Parse(user.ID)
 	createdBy := pgtype.UUID{Bytes: userID, Valid: userID != uuid.Nil}
 
 	rule, err := h.queries.CreateAlertRule(ctx, db.CreateAlertRuleParams{
-		TenantID:             user.TenantID,
-		Name:                 req.Name,
-		Description:          stringPtr(req.Description),
-		Enabled:              enabled,
-		ConditionType:        req.ConditionType,
-		ConditionConfig:      conditionConfig,
-		Severity:             severity,
-		AlertTitleTemplate:   req.AlertTitleTemplate,
-		AlertMessageTemplate: stringPtr(req.AlertMessageTemplate),
-		TriggerWorkflowID:    triggerWorkflowID,
-		TriggerInputTemplate: triggerInputTemplate,
-		CooldownSeconds:      cooldownSeconds,
-		CreatedBy:            createdBy,
+		TenantID:               user.TenantID,
+		Name:                   req.Name,
+		Description:            stringPtr(req.Description),
+		Enabled:                enabled,
+		ConditionType:          req.ConditionType,
+		ConditionConfig:        conditionConfig,
+		Severity:               severity,
+		AlertTitleTemplate:     req.AlertTitleTemplate,
+		AlertMessageTemplate:   stringPtr(req.AlertMessageTemplate),
+		TriggerWorkflowID:      triggerWorkflowID,
+		TriggerInputTemplate:   triggerInputTemplate,
+		CooldownSeconds:        cooldownSeconds,
+		CreatedBy:              createdBy,
+		TriggerWorkflowVersion: triggerWorkflowVersion,
 	})
 	if err != nil {
 		slog.Error("failed to create alert rule", "error", err)
@@ -322,6 +339,19 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// A pinned version belongs to the workflow it was set for
+	triggerWorkflowVersion := existing.TriggerWorkflowVersion
+	if triggerWorkflowID != existing.TriggerWorkflowID {
+		triggerWorkflowVersion = nil
+	}
+	if req.TriggerWorkflowVersion != nil {
+		triggerWorkflowVersion, err = h.checkWorkflowVersion(ctx, user.TenantID, triggerWorkflowID, *req.TriggerWorkflowVersion)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
 	triggerInputTemplate := existing.TriggerInputTemplate
 	if req.TriggerInputTemplate != nil {
 		triggerInputTemplate, _ = json.Marshal(req.TriggerInputTemplate)
@@ -333,19 +363,20 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	rule, err := h.queries.UpdateAlertRule(ctx, db.UpdateAlertRuleParams{
-		ID:                   id,
-		TenantID:             user.TenantID,
-		Name:                 name,
-		Description:          description,
-		Enabled:              enabled,
-		ConditionType:        conditionType,
-		ConditionConfig:      conditionConfig,
-		Severity:             severity,
-		AlertTitleTemplate:   alertTitleTemplate,
-		AlertMessageTemplate: alertMessageTemplate,
-		TriggerWorkflowID:    triggerWorkflowID,
-		TriggerInputTemplate: triggerInputTemplate,
-		CooldownSeconds:      cooldownSeconds,
+		ID:                     id,
+		TenantID:               user.TenantID,
+		Name:                   name,
+		Description:            description,
+		Enabled:                enabled,
+		ConditionType:          conditionType,
+		ConditionConfig:        conditionConfig,
+		Severity:               severity,
+		AlertTitleTemplate:     alertTitleTemplate,
+		AlertMessageTemplate:   alertMessageTemplate,
+		TriggerWorkflowID:      triggerWorkflowID,
+		TriggerInputTemplate:   triggerInputTemplate,
+		CooldownSeconds:        cooldownSeconds,
+		TriggerWorkflowVersion: triggerWorkflowVersion,
 	})
 	if err != nil {
 		slog.Error("failed to update alert rule", "error", err)
@@ -415,6 +446,29 @@ func (h *Handler) Test(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// checkWorkflowVersion checks that version exists for the trigger workflow.
+// Version 0 follows the latest version and returns nil.
+func (h *Handler) checkWorkflowVersion(ctx context.Context, tenantID uuid.UUID, workflowID pgtype.UUID, version int32) (*int32, error) {
+	if version == 0 {
+		return nil, nil
+	}
+	if version < 0 {
+		return nil, errors.New("invalid trigger_workflow_version")
+	}
+	if !workflowID.Valid {
+		return nil, errors.New("trigger_workflow_version requires trigger_workflow_id")
+	}
+
+	v, err := h.queries.GetWorkflowVersion(ctx, db.GetWorkflowVersionParams{
+		WorkflowID: workflowID.Bytes,
+		Version:    version,
+	})
+	if err != nil || v.TenantID != tenantID {
+		return nil, fmt.Errorf("version %d of the trigger workflow not found", version)
+	}
+	return &v.Version, nil
+}
+
 func stringPtr(s string) *string {
 	if s == "" {
 		return nil
@@ -9,23 +9,24 @@ import (
 
 // AlertRule represents an alert rule in the domain
 type AlertRule struct {
-	ID                   uuid.UUID
-	TenantID             uuid.UUID
-	Name                 string
-	Description          *string
-	Enabled              bool
-	ConditionType        string
-	ConditionConfig      json.RawMessage
-	Severity             AlertSeverity
-	AlertTitleTemplate   string
-	AlertMessageTemplate *string
-	TriggerWorkflowID    *uuid.UUID
-	TriggerInputTemplate json.RawMessage
-	CooldownSeconds      int32
-	LastTriggeredAt      *time.Time
-	CreatedBy            *uuid.UUID
-	CreatedAt            time.Time
-	UpdatedAt            time.Time
+	ID                     uuid.UUID
+	TenantID               uuid.UUID
+	Name                   string
+	Description            *string
+	Enabled                bool
+	ConditionType          string
+	ConditionConfig        json.RawMessage
+	Severity               AlertSeverity
+	AlertTitleTemplate     string
+	AlertMessageTemplate   *string
+	TriggerWorkflowID      *uuid.UUID
+	TriggerWorkflowVersion *int32 // nil follows the latest version
+	TriggerInputTemplate   json.RawMessage
+	CooldownSeconds        int32
+	LastTriggeredAt        *time.Time
+	CreatedBy              *uuid.UUID
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
 
 // ThresholdCondition represents a threshold-based condition
@@ -53,6 +54,17 @@ func (r *AlertRule) MarkTriggered() {
 	r.LastTriggeredAt = &now
 }
 
+// MetricName returns the metric the rule's condition watches, if any
+func (r *AlertRule) MetricName() string {
+	var cond struct {
+		MetricName string `json:"metric_name"`
+	}
+	if err := json.Unmarshal(r.ConditionConfig, &cond); err != nil {
+		return ""
+	}
+	return cond.MetricName
+}
+
 // ParseConditionConfig parses the condition config based on condition type
 func (r *AlertRule) ParseThresholdCondition() (*ThresholdCondition, error) {
 	if r.ConditionType != "threshold" {
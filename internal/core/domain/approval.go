@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalAction is the decision taken on an approval
+type ApprovalAction string
+
+const (
+	ApprovalActionApprove ApprovalAction = "approve"
+	ApprovalActionReject  ApprovalAction = "reject"
+)
+
+// IsValid checks if the approval action is valid
+func (a ApprovalAction) IsValid() bool {
+	return a == ApprovalActionApprove || a == ApprovalActionReject
+}
+
+// Approval represents a pending approval requested by an approval step of a
+// running execution
+type Approval struct {
+	ID            string         `json:"id"`
+	ExecutionID   uuid.UUID      `json:"execution_id"`
+	StepID        string         `json:"step_id"`
+	StepName      string         `json:"step_name"`
+	Message       string         `json:"message,omitempty"`
+	DefaultAction ApprovalAction `json:"default_action"`
+	RequestedAt   time.Time      `json:"requested_at"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+}
+
+// ApprovalDecision is a user's decision on a pending approval
+type ApprovalDecision struct {
+	ApprovalID string         `json:"approval_id"`
+	Action     ApprovalAction `json:"action"`
+	UserID     uuid.UUID      `json:"user_id"`
+	User       string         `json:"user,omitempty"` // email or name, for display
+	Comment    string         `json:"comment,omitempty"`
+	DecidedAt  time.Time      `json:"decided_at"`
+}
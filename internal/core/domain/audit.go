@@ -26,16 +26,26 @@ type AuditLog struct {
 
 // Common audit event types
 const (
-	AuditEventWorkflowCreated   = "workflow.created"
-	AuditEventWorkflowUpdated   = "workflow.updated"
-	AuditEventWorkflowDeleted   = "workflow.deleted"
-	AuditEventWorkflowExecuted  = "workflow.executed"
-	AuditEventAlertCreated      = "alert.created"
-	AuditEventAlertAcknowledged = "alert.acknowledged"
-	AuditEventAlertResolved     = "alert.resolved"
-	AuditEventAlertRuleCreated  = "alertrule.created"
-	AuditEventAlertRuleUpdated  = "alertrule.updated"
-	AuditEventAlertRuleDeleted  = "alertrule.deleted"
+	AuditEventWorkflowCreated    = "workflow.created"
+	AuditEventWorkflowUpdated    = "workflow.updated"
+	AuditEventWorkflowDeleted    = "workflow.deleted"
+	AuditEventWorkflowExecuted   = "workflow.executed"
+	AuditEventWorkflowRolledBack = "workflow.rolled_back"
+	AuditEventWorkflowImported   = "workflow.imported"
+	AuditEventExecutionApproved  = "execution.approved"
+	AuditEventExecutionRejected  = "execution.rejected"
+	AuditEventExecutionRetried   = "execution.retried"
+	AuditEventExecutionPaused    = "execution.paused"
+	AuditEventExecutionResumed   = "execution.resumed"
+	AuditEventAlertCreated       = "alert.created"
+	AuditEventAlertAcknowledged  = "alert.acknowledged"
+	AuditEventAlertResolved      = "alert.resolved"
+	AuditEventAlertRuleCreated   = "alertrule.created"
+	AuditEventAlertRuleUpdated   = "alertrule.updated"
+	AuditEventAlertRuleDeleted   = "alertrule.deleted"
+	AuditEventSecretCreated      = "secret.created"
+	AuditEventSecretUpdated      = "secret.updated"
+	AuditEventSecretDeleted      = "secret.deleted"
 )
 
 // Common resource types
@@ -44,6 +54,7 @@ const (
 	ResourceTypeExecution = "execution"
 	ResourceTypeAlert     = "alert"
 	ResourceTypeAlertRule = "alertrule"
+	ResourceTypeSecret    = "secret"
 )
 
 // Common actions
@@ -54,6 +65,11 @@ const (
 	ActionExecute     = "execute"
 	ActionAcknowledge = "acknowledge"
 	ActionResolve     = "resolve"
+	ActionApprove     = "approve"
+	ActionReject      = "reject"
+	ActionImport      = "import"
+	ActionPause       = "pause"
+	ActionResume      = "resume"
 )
 
 // NewAuditLog creates a new audit log entry
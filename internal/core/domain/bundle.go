@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BundleFormatVersion is the version of the bundle format written by
+// exports. Imports reject bundles of other versions.
+const BundleFormatVersion = 1
+
+// WorkflowBundle is a portable copy of a workflow and the objects it depends
+// on: the child workflows it starts, the alert rules that trigger it and the
+// metric definitions those rules and its wait_for_metric steps watch. IDs
+// are those of the exporting tenant and are remapped on import.
+type WorkflowBundle struct {
+	FormatVersion     int                      `json:"format_version"`
+	ExportedAt        time.Time                `json:"exported_at"`
+	WorkflowID        uuid.UUID                `json:"workflow_id"` // the exported workflow
+	Workflows         []BundleWorkflow         `json:"workflows"`
+	AlertRules        []BundleAlertRule        `json:"alert_rules"`
+	MetricDefinitions []BundleMetricDefinition `json:"metric_definitions"`
+}
+
+// BundleWorkflow is a workflow in a bundle
+type BundleWorkflow struct {
+	ID              uuid.UUID        `json:"id"`
+	Name            string           `json:"name"`
+	Description     *string          `json:"description,omitempty"`
+	Definition      json.RawMessage  `json:"definition,omitempty"`
+	Schedule        *string          `json:"schedule,omitempty"`
+	ScheduleOptions *ScheduleOptions `json:"schedule_options,omitempty"`
+	Status          WorkflowStatus   `json:"status"`
+}
+
+// BundleAlertRule is an alert rule in a bundle. Rules follow the latest
+// version of the workflow they trigger, since version numbers aren't kept
+// across tenants.
+type BundleAlertRule struct {
+	ID                   uuid.UUID       `json:"id"`
+	Name                 string          `json:"name"`
+	Description          *string         `json:"description,omitempty"`
+	Enabled              bool            `json:"enabled"`
+	ConditionType        string          `json:"condition_type"`
+	ConditionConfig      json.RawMessage `json:"condition_config"`
+	Severity             AlertSeverity   `json:"severity"`
+	AlertTitleTemplate   string          `json:"alert_title_template"`
+	AlertMessageTemplate *string         `json:"alert_message_template,omitempty"`
+	TriggerWorkflowID    *uuid.UUID      `json:"trigger_workflow_id,omitempty"`
+	TriggerInputTemplate json.RawMessage `json:"trigger_input_template,omitempty"`
+	CooldownSeconds      int32           `json:"cooldown_seconds"`
+}
+
+// BundleMetricDefinition is a metric definition in a bundle. Metric
+// definitions are identified by the name of their metric.
+type BundleMetricDefinition struct {
+	Name           string          `json:"name"`
+	DisplayName    *string         `json:"display_name,omitempty"`
+	Description    *string         `json:"description,omitempty"`
+	Unit           *string         `json:"unit,omitempty"`
+	Type           MetricType      `json:"type"`
+	Aggregation    AggregationType `json:"aggregation"`
+	AlertThreshold *AlertThreshold `json:"alert_threshold,omitempty"`
+	RetentionDays  int             `json:"retention_days"`
+}
+
+// ConflictStrategy is what an import does with an object whose name is
+// already taken in the importing tenant
+type ConflictStrategy string
+
+const (
+	// ConflictSkip keeps the existing object and points references to it
+	ConflictSkip ConflictStrategy = "skip"
+	// ConflictOverwrite replaces the existing object with the bundled one
+	ConflictOverwrite ConflictStrategy = "overwrite"
+	// ConflictRename imports the bundled object under a free name, e.g.
+	// "restart-api (2)". Metric definitions are skipped instead, since their
+	// name is the metric they describe.
+	ConflictRename ConflictStrategy = "rename"
+)
+
+// IsValid checks if the conflict strategy is valid
+func (s ConflictStrategy) IsValid() bool {
+	return s == ConflictSkip || s == ConflictOverwrite || s == ConflictRename
+}
+
+// BundleObjectKind is the kind of an object in a bundle
+type BundleObjectKind string
+
+const (
+	BundleObjectWorkflow         BundleObjectKind = "workflow"
+	BundleObjectAlertRule        BundleObjectKind = "alert_rule"
+	BundleObjectMetricDefinition BundleObjectKind = "metric_definition"
+)
+
+// BundleAction is what an import did with an object of the bundle
+type BundleAction string
+
+const (
+	BundleActionCreated     BundleAction = "created"
+	BundleActionSkipped     BundleAction = "skipped"
+	BundleActionOverwritten BundleAction = "overwritten"
+	BundleActionRenamed     BundleAction = "renamed"
+)
+
+// BundleImport is the outcome of importing a bundle
+type BundleImport struct {
+	WorkflowID uuid.UUID              `json:"workflow_id"` // the imported copy of the exported workflow
+	Objects    []BundleImportedObject `json:"objects"`
+}
+
+// BundleImportedObject is what an import did with an object of the bundle
+type BundleImportedObject struct {
+	Kind     BundleObjectKind `json:"kind"`
+	SourceID *uuid.UUID       `json:"source_id,omitempty"` // the ID in the bundle, metric definitions have none
+	ID       uuid.UUID        `json:"id"`                  // the ID in the importing tenant
+	Name     string           `json:"name"`                // the name in the importing tenant
+	Action   BundleAction     `json:"action"`
+}
+
+// BundleValidationError is returned when a bundle can't be imported. Paths
+// are JSON paths into the bundle. It wraps ErrInvalidBundle.
+type BundleValidationError struct {
+	Errors []DefinitionError
+}
+
+func (e *BundleValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return ErrInvalidBundle.Error()
+	}
+	msg := fmt.Sprintf("%s: %s: %s", ErrInvalidBundle, e.Errors[0].Path, e.Errors[0].Message)
+	if len(e.Errors) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Errors)-1)
+	}
+	return msg
+}
+
+func (e *BundleValidationError) Unwrap() error {
+	return ErrInvalidBundle
+}
+
+// DefinitionDependencies are the workflows and metrics a workflow
+// definition refers to
+type DefinitionDependencies struct {
+	WorkflowIDs   []string // child workflows started by ID
+	WorkflowNames []string // child workflows started by name
+	Metrics       []string // metrics watched by wait_for_metric steps
+}
+
+// metricConditionName matches the metric of a wait_for_metric condition,
+// e.g. "cpu_usage" in "cpu_usage{host=web-1} avg over 5m < 70"
+var metricConditionName = regexp.MustCompile(`^\s*([A-Za-z_][\w:.]*)`)
+
+// FindDefinitionDependencies returns the child workflows and metrics a
+// definition refers to. References built from expressions can't be
+// resolved before a run and are left out.
+func FindDefinitionDependencies(definition json.RawMessage) (*DefinitionDependencies, error) {
+	deps := &DefinitionDependencies{}
+	if len(definition) == 0 {
+		return deps, nil
+	}
+	def, err := decodeDefinition(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	add := func(list *[]string, kind, value string) {
+		if value == "" || strings.Contains(value, "${") || seen[kind+value] {
+			return
+		}
+		seen[kind+value] = true
+		*list = append(*list, value)
+	}
+	walkDefinitionSteps(def, func(step map[string]interface{}) {
+		config, _ := step["config"].(map[string]interface{})
+		switch step["type"] {
+		case "child_workflow":
+			id, _ := config["workflow_id"].(string)
+			name, _ := config["workflow_name"].(string)
+			add(&deps.WorkflowIDs, "id:", id)
+			add(&deps.WorkflowNames, "name:", name)
+		case "wait_for_metric":
+			metric, _ := config["metric"].(string)
+			if condition, ok := config["condition"].(string); ok && metric == "" {
+				if m := metricConditionName.FindStringSubmatch(condition); m != nil {
+					metric = m[1]
+				}
+			}
+			add(&deps.Metrics, "metric:", metric)
+		}
+	})
+	return deps, nil
+}
+
+// RemapChildWorkflows points the child_workflow steps of a definition to
+// other workflows: workflow IDs found in ids and workflow names found in
+// names are replaced by their values.
+func RemapChildWorkflows(definition json.RawMessage, ids, names map[string]string) (json.RawMessage, error) {
+	if len(definition) == 0 {
+		return definition, nil
+	}
+	def, err := decodeDefinition(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	walkDefinitionSteps(def, func(step map[string]interface{}) {
+		config, _ := step["config"].(map[string]interface{})
+		if step["type"] != "child_workflow" || config == nil {
+			return
+		}
+		if id, ok := config["workflow_id"].(string); ok && ids[id] != "" {
+			config["workflow_id"] = ids[id]
+		}
+		if name, ok := config["workflow_name"].(string); ok && names[name] != "" {
+			config["workflow_name"] = names[name]
+		}
+	})
+	return json.Marshal(def)
+}
+
+// nestedStepKeys are the keys of a definition or step that hold steps
+var nestedStepKeys = []string{"steps", "on_error", "on_success", "on_true", "on_false", "parallel", "compensate"}
+
+// walkDefinitionSteps calls fn for every step of a definition, nested steps
+// included
+func walkDefinitionSteps(node map[string]interface{}, fn func(step map[string]interface{})) {
+	for _, key := range nestedStepKeys {
+		steps, _ := node[key].([]interface{})
+		for _, s := range steps {
+			if step, ok := s.(map[string]interface{}); ok {
+				fn(step)
+				walkDefinitionSteps(step, fn)
+			}
+		}
+	}
+}
+
+// decodeDefinition decodes a definition, keeping numbers as written
+func decodeDefinition(definition json.RawMessage) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(definition))
+	dec.UseNumber()
+	var def map[string]interface{}
+	if err := dec.Decode(&def); err != nil {
+		return nil, ErrInvalidDefinition
+	}
+	return def, nil
+}
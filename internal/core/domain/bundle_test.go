@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDefinitionDependencies(t *testing.T) {
+	t.Run("finds nested child workflows and metrics", func(t *testing.T) {
+		definition := json.RawMessage(`{"steps": [
+			{"id": "wait", "type": "wait_for_metric", "config": {"metric": "cpu_usage"}},
+			{"id": "check", "type": "condition", "on_true": [
+				{"id": "restart", "type": "child_workflow", "config": {"workflow_name": "restart-api"}}
+			], "on_false": [
+				{"id": "page", "type": "child_workflow", "config": {"workflow_id": "6f1c2a44-8d7e-4c1b-9a55-3b0f7d2e9c10"}}
+			]},
+			{"id": "again", "type": "child_workflow", "config": {"workflow_name": "restart-api"}},
+			{"id": "settle", "type": "wait_for_metric", "config": {"condition": "error_rate{service=api} avg over 5m < 1"}}
+		]}`)
+
+		deps, err := FindDefinitionDependencies(definition)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"6f1c2a44-8d7e-4c1b-9a55-3b0f7d2e9c10"}, deps.WorkflowIDs)
+		assert.Equal(t, []string{"restart-api"}, deps.WorkflowNames)
+		assert.Equal(t, []string{"cpu_usage", "error_rate"}, deps.Metrics)
+	})
+
+	t.Run("leaves out references built from expressions", func(t *testing.T) {
+		definition := json.RawMessage(`{"steps": [
+			{"id": "run", "type": "child_workflow", "config": {"workflow_name": "${input.workflow}"}},
+			{"id": "wait", "type": "wait_for_metric", "config": {"metric": "${input.metric}"}}
+		]}`)
+
+		deps, err := FindDefinitionDependencies(definition)
+
+		require.NoError(t, err)
+		assert.Empty(t, deps.WorkflowNames)
+		assert.Empty(t, deps.Metrics)
+	})
+
+	t.Run("rejects invalid definitions", func(t *testing.T) {
+		_, err := FindDefinitionDependencies(json.RawMessage(`{"steps": [`))
+
+		assert.ErrorIs(t, err, ErrInvalidDefinition)
+	})
+}
+
+func TestRemapChildWorkflows(t *testing.T) {
+	definition := json.RawMessage(`{"steps": [
+		{"id": "run", "type": "child_workflow", "config": {"workflow_id": "old-id", "timeout": 1.50}},
+		{"id": "cleanup", "type": "log", "on_error": [
+			{"id": "undo", "type": "child_workflow", "config": {"workflow_name": "undo"}}
+		], "config": {"workflow_name": "undo"}}
+	]}`)
+
+	remapped, err := RemapChildWorkflows(definition, map[string]string{"old-id": "new-id"}, map[string]string{"undo": "undo (2)"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"steps": [
+		{"id": "run", "type": "child_workflow", "config": {"workflow_id": "new-id", "timeout": 1.50}},
+		{"id": "cleanup", "type": "log", "on_error": [
+			{"id": "undo", "type": "child_workflow", "config": {"workflow_name": "undo (2)"}}
+		], "config": {"workflow_name": "undo"}}
+	]}`, string(remapped))
+}
@@ -0,0 +1,24 @@
+package domain
+
+// DryRun is the trace of a simulated run of a workflow. Steps with side
+// effects, such as HTTP requests and notifications, are simulated, so the
+// run changes nothing outside itself.
+type DryRun struct {
+	Status     string                 `json:"status"`
+	Error      string                 `json:"error,omitempty"`
+	Steps      []DryRunStep           `json:"steps"`
+	Output     map[string]interface{} `json:"output,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+}
+
+// DryRunStep is a step of a dry run with the config it was run with
+type DryRunStep struct {
+	StepID   string                 `json:"step_id"`
+	StepName string                 `json:"step_name,omitempty"`
+	StepType string                 `json:"step_type"`
+	Success  bool                   `json:"success"`
+	Config   map[string]interface{} `json:"config,omitempty"` // ${...} references rendered
+	Output   interface{}            `json:"output,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Children []DryRunStep           `json:"children,omitempty"` // nested steps run by condition/parallel/foreach steps
+}
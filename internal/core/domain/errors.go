@@ -9,11 +9,25 @@ var (
 	ErrWorkflowCannotExecute = errors.New("workflow cannot be executed")
 	ErrInvalidDefinition    = errors.New("invalid workflow definition")
 	ErrNoSteps              = errors.New("workflow has no steps")
+	ErrInvalidInput         = errors.New("invalid workflow input")
+	ErrWorkflowVersionNotFound = errors.New("workflow version not found")
+	ErrInvalidSchedule         = errors.New("invalid workflow schedule")
+	ErrDryRunTimedOut          = errors.New("workflow dry run timed out")
 
 	// Execution errors
 	ErrExecutionNotFound    = errors.New("execution not found")
 	ErrExecutionNotRunning  = errors.New("execution is not running")
 	ErrExecutionCannotCancel = errors.New("execution cannot be cancelled")
+	ErrExecutionCannotRetry  = errors.New("execution cannot be retried")
+	ErrInvalidRetryMode      = errors.New("invalid retry mode")
+	ErrNoFailedStep          = errors.New("execution has no failed step to resume from")
+	ErrExecutionCompensated  = errors.New("execution was compensated and can only be retried from scratch")
+	ErrExecutionAlreadyPaused = errors.New("execution is already paused")
+	ErrExecutionNotPaused     = errors.New("execution is not paused")
+
+	// Approval errors
+	ErrApprovalNotFound      = errors.New("approval not found")
+	ErrInvalidApprovalAction = errors.New("invalid approval action")
 
 	// Alert errors
 	ErrAlertNotFound          = errors.New("alert not found")
@@ -41,6 +55,21 @@ var (
 	ErrInvalidAggregationType  = errors.New("invalid aggregation type")
 	ErrBatchTooLarge           = errors.New("batch size exceeds maximum limit")
 
+	// Bundle errors
+	ErrInvalidBundle            = errors.New("invalid workflow bundle")
+	ErrInvalidConflictStrategy  = errors.New("invalid conflict strategy")
+	ErrBundleDependencyNotFound = errors.New("bundle dependency not found")
+
+	// Template errors
+	ErrTemplateNotFound          = errors.New("workflow template not found")
+	ErrInvalidTemplateParameters = errors.New("invalid workflow template parameters")
+
+	// Secret errors
+	ErrSecretNotFound       = errors.New("secret not found")
+	ErrSecretAlreadyExists  = errors.New("secret already exists")
+	ErrInvalidSecret        = errors.New("invalid secret")
+	ErrSecretsNotConfigured = errors.New("secrets are not configured")
+
 	// General errors
 	ErrNotFound     = errors.New("not found")
 	ErrUnauthorized = errors.New("unauthorized")
@@ -23,6 +23,14 @@ type Execution struct {
 	CreatedBy          *uuid.UUID
 	CreatedAt          time.Time
 	TriggeredBy        *string
+	ParentExecutionID  *uuid.UUID
+	WorkflowVersion    *int32     // version of the workflow definition that ran
+	RetryOf            *uuid.UUID // execution this one retries
+}
+
+// TemporalWorkflowID returns the Temporal workflow ID used to run an execution
+func TemporalWorkflowID(executionID uuid.UUID) string {
+	return "execution-" + executionID.String()
 }
 
 // ExecutionStatus represents the status of an execution
@@ -34,13 +42,15 @@ const (
 	ExecutionStatusCompleted ExecutionStatus = "completed"
 	ExecutionStatusFailed    ExecutionStatus = "failed"
 	ExecutionStatusCancelled ExecutionStatus = "cancelled"
+	ExecutionStatusTimedOut  ExecutionStatus = "timed_out" // ran past the timeout of its definition
 )
 
 // IsTerminal checks if the execution is in a terminal state
 func (e *Execution) IsTerminal() bool {
 	return e.Status == ExecutionStatusCompleted ||
 		e.Status == ExecutionStatusFailed ||
-		e.Status == ExecutionStatusCancelled
+		e.Status == ExecutionStatusCancelled ||
+		e.Status == ExecutionStatusTimedOut
 }
 
 // CanCancel checks if the execution can be cancelled
@@ -48,6 +58,13 @@ func (e *Execution) CanCancel() bool {
 	return e.Status == ExecutionStatusPending || e.Status == ExecutionStatusRunning
 }
 
+// CanRetry checks if the execution can be retried
+func (e *Execution) CanRetry() bool {
+	return e.Status == ExecutionStatusFailed ||
+		e.Status == ExecutionStatusCancelled ||
+		e.Status == ExecutionStatusTimedOut
+}
+
 // MarkAsRunning marks the execution as running
 func (e *Execution) MarkAsRunning() {
 	e.Status = ExecutionStatusRunning
@@ -71,9 +88,67 @@ func (e *Execution) MarkAsFailed(errorMsg string) {
 	e.CompletedAt = &now
 }
 
+// MarkAsTimedOut marks the execution as timed out
+func (e *Execution) MarkAsTimedOut(errorMsg string) {
+	e.Status = ExecutionStatusTimedOut
+	e.Error = &errorMsg
+	now := time.Now()
+	e.CompletedAt = &now
+}
+
 // MarkAsCancelled marks the execution as cancelled
 func (e *Execution) MarkAsCancelled() {
 	e.Status = ExecutionStatusCancelled
 	now := time.Now()
 	e.CompletedAt = &now
 }
+
+// ExecutionStep is the outcome of one top-level step of an execution. Steps
+// nested in condition, parallel or foreach steps are part of their parent's
+// output.
+type ExecutionStep struct {
+	ID          uuid.UUID
+	TenantID    uuid.UUID
+	ExecutionID uuid.UUID
+	Position    int32 // index of the step in the definition
+	StepID      string
+	StepName    string
+	StepType    string
+	Status      ExecutionStepStatus
+	Attempts    int32
+	Output      json.RawMessage
+	Error       *string
+	StartedAt   time.Time
+	CompletedAt *time.Time
+}
+
+// ExecutionStepStatus represents the status of an execution step
+type ExecutionStepStatus string
+
+const (
+	ExecutionStepStatusRunning     ExecutionStepStatus = "running"
+	ExecutionStepStatusCompleted   ExecutionStepStatus = "completed"
+	ExecutionStepStatusFailed      ExecutionStepStatus = "failed"
+	ExecutionStepStatusSkipped     ExecutionStepStatus = "skipped"     // failed step a retry skipped
+	ExecutionStepStatusCompensated ExecutionStepStatus = "compensated" // completed step undone after the run failed
+)
+
+// ExecutionProgress is a live snapshot of a running execution: the step it
+// is running, the steps it has finished and the outputs so far
+type ExecutionProgress struct {
+	ExecutionID    uuid.UUID              `json:"execution_id"`
+	Paused         bool                   `json:"paused"`
+	PausedAt       *time.Time             `json:"paused_at,omitempty"`
+	PausedBy       string                 `json:"paused_by,omitempty"`
+	CurrentStep    *ExecutionStep         `json:"current_step,omitempty"`
+	CompletedSteps []*ExecutionStep       `json:"completed_steps"`
+	TotalSteps     int                    `json:"total_steps"` // top-level steps in the definition
+	Outputs        map[string]interface{} `json:"outputs"`
+}
+
+// ExecutionPause is a user's request to pause or resume a running execution
+type ExecutionPause struct {
+	UserID uuid.UUID `json:"user_id"`
+	User   string    `json:"user,omitempty"` // email or name, for display
+	At     time.Time `json:"at"`
+}
@@ -0,0 +1,310 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Input schema types
+const (
+	InputTypeString  = "string"
+	InputTypeNumber  = "number"
+	InputTypeInteger = "integer"
+	InputTypeBoolean = "boolean"
+	InputTypeObject  = "object"
+	InputTypeArray   = "array"
+)
+
+var inputTypes = map[string]bool{
+	InputTypeString:  true,
+	InputTypeNumber:  true,
+	InputTypeInteger: true,
+	InputTypeBoolean: true,
+	InputTypeObject:  true,
+	InputTypeArray:   true,
+}
+
+// InputSchema declares the input a workflow accepts. It is a subset of JSON
+// Schema: type, properties, required, items, enum, default and pattern. The
+// top-level schema always describes an object.
+type InputSchema struct {
+	Type        string                  `json:"type,omitempty"`
+	Description string                  `json:"description,omitempty"`
+	Properties  map[string]*InputSchema `json:"properties,omitempty"`
+	Required    []string                `json:"required,omitempty"`
+	Items       *InputSchema            `json:"items,omitempty"`
+	Enum        []interface{}           `json:"enum,omitempty"`
+	Default     interface{}             `json:"default,omitempty"`
+	Pattern     string                  `json:"pattern,omitempty"` // regular expression strings must match
+}
+
+// InputError describes an input value that doesn't match the schema. Field
+// is the path of the value, e.g. "hosts[1]" or "target.region".
+type InputError struct {
+	Field   string
+	Message string
+}
+
+// Apply validates input against the schema and returns a copy with defaults
+// filled in for missing properties
+func (s *InputSchema) Apply(input map[string]interface{}) (map[string]interface{}, []InputError) {
+	if input == nil {
+		input = map[string]interface{}{}
+	}
+
+	// Round trip through JSON so values look the same as they will to the
+	// workflow, e.g. all numbers are float64
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, []InputError{{Field: "input", Message: fmt.Sprintf("is not valid JSON: %v", err)}}
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, []InputError{{Field: "input", Message: fmt.Sprintf("is not valid JSON: %v", err)}}
+	}
+
+	root := *s
+	root.Type = InputTypeObject
+
+	var errs []InputError
+	value = root.apply("", value, &errs)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return value.(map[string]interface{}), nil
+}
+
+// Check returns the problems in the schema itself, with paths below path
+func (s *InputSchema) Check(path string) []DefinitionError {
+	var errs []DefinitionError
+	s.check(path, true, &errs)
+	return errs
+}
+
+func (s *InputSchema) check(path string, root bool, errs *[]DefinitionError) {
+	add := func(p, format string, args ...interface{}) {
+		*errs = append(*errs, DefinitionError{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+	before := len(*errs)
+
+	switch {
+	case root && s.Type != "" && s.Type != InputTypeObject:
+		add(path+".type", "inputs must be an object schema")
+	case !root && s.Type == "":
+		add(path+".type", "type is required")
+	case s.Type != "" && !inputTypes[s.Type]:
+		add(path+".type", "unknown type %q", s.Type)
+		return
+	}
+
+	if s.Pattern != "" {
+		if s.Type != InputTypeString {
+			add(path+".pattern", "pattern only applies to strings")
+		} else if _, err := regexp.Compile(s.Pattern); err != nil {
+			add(path+".pattern", "invalid pattern: %v", err)
+		}
+	}
+	if s.Items != nil && s.Type != InputTypeArray {
+		add(path+".items", "items only applies to arrays")
+	}
+	if len(s.Properties) > 0 && s.Type != InputTypeObject && !root {
+		add(path+".properties", "properties only applies to objects")
+	}
+
+	for _, name := range sortedSchemaKeys(s.Properties) {
+		prop := s.Properties[name]
+		if prop == nil {
+			add(path+".properties."+name, "schema is required")
+			continue
+		}
+		prop.check(path+".properties."+name, false, errs)
+	}
+	if s.Items != nil {
+		s.Items.check(path+".items", false, errs)
+	}
+
+	if len(*errs) > before {
+		return // values can't be checked against a broken schema
+	}
+	for i, value := range s.Enum {
+		var valueErrs []InputError
+		s.validate("", value, &valueErrs, false)
+		if len(valueErrs) > 0 {
+			add(fmt.Sprintf("%s.enum[%d]", path, i), "%s", valueErrs[0].Message)
+		}
+	}
+	if s.Default != nil {
+		var valueErrs []InputError
+		s.validate("", s.Default, &valueErrs, true)
+		if len(valueErrs) > 0 {
+			add(path+".default", "%s", valueErrs[0].Message)
+		}
+	}
+}
+
+// apply fills in defaults for missing object properties and validates value
+func (s *InputSchema) apply(field string, value interface{}, errs *[]InputError) interface{} {
+	if value == nil && s.Default != nil {
+		value = copyValue(s.Default)
+	}
+
+	if obj, ok := value.(map[string]interface{}); ok && s.Type == InputTypeObject {
+		for _, name := range s.Required {
+			if v, ok := obj[name]; (!ok || v == nil) && (s.Properties[name] == nil || s.Properties[name].Default == nil) {
+				*errs = append(*errs, InputError{Field: joinField(field, name), Message: "is required"})
+			}
+		}
+		for _, name := range sortedSchemaKeys(s.Properties) {
+			prop := s.Properties[name]
+			v, ok := obj[name]
+			if prop == nil || ((!ok || v == nil) && prop.Default == nil) {
+				continue
+			}
+			obj[name] = prop.apply(joinField(field, name), v, errs)
+		}
+		return obj
+	}
+
+	if list, ok := value.([]interface{}); ok && s.Type == InputTypeArray && s.Items != nil {
+		for i, item := range list {
+			list[i] = s.Items.apply(fmt.Sprintf("%s[%d]", field, i), item, errs)
+		}
+		return list
+	}
+
+	s.validate(field, value, errs, false)
+	return value
+}
+
+// validate checks a single value against the type, enum and pattern. Nested
+// values are checked when deep is set.
+func (s *InputSchema) validate(field string, value interface{}, errs *[]InputError, deep bool) {
+	add := func(format string, args ...interface{}) {
+		*errs = append(*errs, InputError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		add("must be %s", article(s.Type))
+		return
+	}
+
+	switch s.Type {
+	case InputTypeString:
+		str, ok := value.(string)
+		if !ok {
+			add("must be a string")
+			return
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(str) {
+				add("must match pattern %s", s.Pattern)
+				return
+			}
+		}
+	case InputTypeNumber:
+		if _, ok := value.(float64); !ok {
+			add("must be a number")
+			return
+		}
+	case InputTypeInteger:
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			add("must be an integer")
+			return
+		}
+	case InputTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			add("must be a boolean")
+			return
+		}
+	case InputTypeObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			add("must be an object")
+			return
+		}
+		if deep {
+			s.apply(field, copyValue(obj), errs)
+		}
+	case InputTypeArray:
+		list, ok := value.([]interface{})
+		if !ok {
+			add("must be an array")
+			return
+		}
+		if deep && s.Items != nil {
+			for i, item := range list {
+				s.Items.validate(fmt.Sprintf("%s[%d]", field, i), item, errs, true)
+			}
+		}
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		allowed := make([]string, len(s.Enum))
+		for i, v := range s.Enum {
+			data, _ := json.Marshal(v)
+			allowed[i] = string(data)
+		}
+		add("must be one of: %s", strings.Join(allowed, ", "))
+	}
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyValue deep copies a JSON value so defaults aren't shared between runs
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			out[k] = copyValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = copyValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+func article(t string) string {
+	switch t {
+	case InputTypeInteger, InputTypeObject, InputTypeArray:
+		return "an " + t
+	case "":
+		return "set"
+	default:
+		return "a " + t
+	}
+}
+
+func sortedSchemaKeys(m map[string]*InputSchema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSchema(t *testing.T, src string) *InputSchema {
+	t.Helper()
+	var schema InputSchema
+	require.NoError(t, json.Unmarshal([]byte(src), &schema))
+	return &schema
+}
+
+const testInputSchema = `{
+	"type": "object",
+	"properties": {
+		"host":     {"type": "string", "pattern": "^web-[0-9]+$"},
+		"env":      {"type": "string", "enum": ["staging", "production"], "default": "staging"},
+		"replicas": {"type": "integer", "default": 1},
+		"dry_run":  {"type": "boolean"},
+		"tags":     {"type": "array", "items": {"type": "string"}},
+		"target":   {"type": "object", "properties": {"region": {"type": "string"}}, "required": ["region"]}
+	},
+	"required": ["host"]
+}`
+
+func TestInputSchema_Apply(t *testing.T) {
+	schema := parseSchema(t, testInputSchema)
+
+	t.Run("fills in defaults", func(t *testing.T) {
+		input := map[string]interface{}{"host": "web-1", "tags": []string{"a"}}
+
+		result, errs := schema.Apply(input)
+
+		require.Empty(t, errs)
+		assert.Equal(t, map[string]interface{}{
+			"host":     "web-1",
+			"env":      "staging",
+			"replicas": float64(1),
+			"tags":     []interface{}{"a"},
+		}, result)
+		assert.NotContains(t, input, "env", "input must not be modified")
+	})
+
+	t.Run("keeps values that are set", func(t *testing.T) {
+		result, errs := schema.Apply(map[string]interface{}{"host": "web-2", "env": "production", "replicas": 3})
+
+		require.Empty(t, errs)
+		assert.Equal(t, "production", result["env"])
+		assert.Equal(t, float64(3), result["replicas"])
+	})
+
+	t.Run("keeps properties that are not declared", func(t *testing.T) {
+		result, errs := schema.Apply(map[string]interface{}{"host": "web-1", "extra": true})
+
+		require.Empty(t, errs)
+		assert.Equal(t, true, result["extra"])
+	})
+
+	t.Run("reports every invalid field", func(t *testing.T) {
+		_, errs := schema.Apply(map[string]interface{}{
+			"env":      "dev",
+			"replicas": 1.5,
+			"dry_run":  "yes",
+			"tags":     []interface{}{"a", 2},
+			"target":   map[string]interface{}{},
+		})
+
+		assert.Equal(t, []InputError{
+			{Field: "host", Message: "is required"},
+			{Field: "dry_run", Message: "must be a boolean"},
+			{Field: "env", Message: `must be one of: "staging", "production"`},
+			{Field: "replicas", Message: "must be an integer"},
+			{Field: "tags[1]", Message: "must be a string"},
+			{Field: "target.region", Message: "is required"},
+		}, errs)
+	})
+
+	t.Run("checks patterns", func(t *testing.T) {
+		_, errs := schema.Apply(map[string]interface{}{"host": "db-1"})
+
+		assert.Equal(t, []InputError{{Field: "host", Message: "must match pattern ^web-[0-9]+$"}}, errs)
+	})
+
+	t.Run("accepts nil input", func(t *testing.T) {
+		_, errs := schema.Apply(nil)
+
+		assert.Equal(t, []InputError{{Field: "host", Message: "is required"}}, errs)
+	})
+}
+
+func TestInputSchema_Check(t *testing.T) {
+	t.Run("accepts a valid schema", func(t *testing.T) {
+		assert.Empty(t, parseSchema(t, testInputSchema).Check("$.inputs"))
+	})
+
+	t.Run("reports schema problems", func(t *testing.T) {
+		schema := parseSchema(t, `{
+			"properties": {
+				"a": {"type": "text"},
+				"b": {"type": "string", "pattern": "("},
+				"c": {},
+				"d": {"type": "number", "pattern": "x"}
+			}
+		}`)
+
+		errs := schema.Check("$.inputs")
+
+		assert.Equal(t, []DefinitionError{
+			{Path: "$.inputs.properties.a.type", Message: `unknown type "text"`},
+			{Path: "$.inputs.properties.b.pattern", Message: "invalid pattern: error parsing regexp: missing closing ): `(`"},
+			{Path: "$.inputs.properties.c.type", Message: "type is required"},
+			{Path: "$.inputs.properties.d.pattern", Message: "pattern only applies to strings"},
+		}, errs)
+	})
+
+	t.Run("reports defaults and enums that do not match the type", func(t *testing.T) {
+		schema := parseSchema(t, `{
+			"properties": {
+				"count": {"type": "integer", "default": "one"},
+				"level": {"type": "string", "enum": ["low", 2]}
+			}
+		}`)
+
+		errs := schema.Check("$.inputs")
+
+		assert.Equal(t, []DefinitionError{
+			{Path: "$.inputs.properties.count.default", Message: "must be an integer"},
+			{Path: "$.inputs.properties.level.enum[1]", Message: "must be a string"},
+		}, errs)
+	})
+}
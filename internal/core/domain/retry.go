@@ -0,0 +1,72 @@
+package domain
+
+// RetryMode is how a failed execution is retried
+type RetryMode string
+
+const (
+	// RetryModeFromScratch runs every step again
+	RetryModeFromScratch RetryMode = "from_scratch"
+	// RetryModeFromFailedStep reuses the results of the steps before the
+	// failed step and runs the failed step again
+	RetryModeFromFailedStep RetryMode = "from_failed_step"
+	// RetryModeSkipFailedStep reuses the results of the steps before the
+	// failed step and carries on after it
+	RetryModeSkipFailedStep RetryMode = "skip_failed_step"
+)
+
+// IsValid checks if the retry mode is valid
+func (m RetryMode) IsValid() bool {
+	return m == RetryModeFromScratch || m == RetryModeFromFailedStep || m == RetryModeSkipFailedStep
+}
+
+// ExecutionResume tells a retried execution where to pick up. The steps
+// before StartAt don't run again; Steps holds their recorded results.
+type ExecutionResume struct {
+	StartAt int32
+	Steps   []*ExecutionStep
+}
+
+// PlanRetry works out where a retry picks up from the recorded steps of the
+// execution being retried. The failed step is the last step the execution
+// recorded, since a failure stops the run unless the step continues on
+// error. A last step still recorded as running is where a cancelled or
+// terminated run stopped, and counts as failed. Retrying from scratch
+// returns nil. Executions whose completed
+// steps were compensated can only be retried from scratch, since the work
+// those steps did has been undone.
+func PlanRetry(mode RetryMode, steps []*ExecutionStep) (*ExecutionResume, error) {
+	if !mode.IsValid() {
+		return nil, ErrInvalidRetryMode
+	}
+	if mode == RetryModeFromScratch {
+		return nil, nil
+	}
+
+	var failed *ExecutionStep
+	for _, step := range steps {
+		if step.Status == ExecutionStepStatusCompensated {
+			return nil, ErrExecutionCompensated
+		}
+		if failed == nil || step.Position > failed.Position {
+			failed = step
+		}
+	}
+	if failed == nil || (failed.Status != ExecutionStepStatusFailed && failed.Status != ExecutionStepStatusRunning) {
+		return nil, ErrNoFailedStep
+	}
+
+	resume := &ExecutionResume{StartAt: failed.Position}
+	for _, step := range steps {
+		if step.Position < failed.Position {
+			resume.Steps = append(resume.Steps, step)
+		}
+	}
+
+	if mode == RetryModeSkipFailedStep {
+		skipped := *failed
+		skipped.Status = ExecutionStepStatusSkipped
+		resume.Steps = append(resume.Steps, &skipped)
+		resume.StartAt++
+	}
+	return resume, nil
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRetry(t *testing.T) {
+	steps := []*ExecutionStep{
+		{Position: 0, StepID: "notify", Status: ExecutionStepStatusCompleted},
+		{Position: 1, StepID: "probe", Status: ExecutionStepStatusFailed}, // continue_on_error
+		{Position: 2, StepID: "scale_up", Status: ExecutionStepStatusCompleted},
+		{Position: 3, StepID: "deploy", Status: ExecutionStepStatusFailed},
+	}
+
+	t.Run("from scratch reuses nothing", func(t *testing.T) {
+		resume, err := PlanRetry(RetryModeFromScratch, steps)
+
+		require.NoError(t, err)
+		assert.Nil(t, resume)
+	})
+
+	t.Run("from failed step reruns the step that stopped the run", func(t *testing.T) {
+		resume, err := PlanRetry(RetryModeFromFailedStep, steps)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), resume.StartAt)
+		assert.Equal(t, steps[:3], resume.Steps)
+	})
+
+	t.Run("skip failed step carries on after it", func(t *testing.T) {
+		resume, err := PlanRetry(RetryModeSkipFailedStep, steps)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), resume.StartAt)
+		require.Len(t, resume.Steps, 4)
+		assert.Equal(t, "deploy", resume.Steps[3].StepID)
+		assert.Equal(t, ExecutionStepStatusSkipped, resume.Steps[3].Status)
+		assert.Equal(t, ExecutionStepStatusFailed, steps[3].Status)
+	})
+
+	t.Run("resumes from the step a stopped run was on", func(t *testing.T) {
+		stopped := []*ExecutionStep{
+			{Position: 0, StepID: "scale_up", Status: ExecutionStepStatusCompleted},
+			{Position: 1, StepID: "deploy", Status: ExecutionStepStatusRunning},
+		}
+
+		resume, err := PlanRetry(RetryModeFromFailedStep, stopped)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), resume.StartAt)
+		assert.Equal(t, stopped[:1], resume.Steps)
+	})
+
+	t.Run("requires a failed last step", func(t *testing.T) {
+		_, err := PlanRetry(RetryModeFromFailedStep, steps[:3])
+		assert.ErrorIs(t, err, ErrNoFailedStep)
+
+		_, err = PlanRetry(RetryModeSkipFailedStep, nil)
+		assert.ErrorIs(t, err, ErrNoFailedStep)
+	})
+
+	t.Run("cannot resume compensated executions", func(t *testing.T) {
+		compensated := []*ExecutionStep{
+			{Position: 0, StepID: "scale_up", Status: ExecutionStepStatusCompensated},
+			{Position: 1, StepID: "deploy", Status: ExecutionStepStatusFailed},
+		}
+
+		_, err := PlanRetry(RetryModeFromFailedStep, compensated)
+		assert.ErrorIs(t, err, ErrExecutionCompensated)
+
+		resume, err := PlanRetry(RetryModeFromScratch, compensated)
+		require.NoError(t, err)
+		assert.Nil(t, resume)
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := PlanRetry(RetryMode("resume"), steps)
+
+		assert.ErrorIs(t, err, ErrInvalidRetryMode)
+	})
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Schedule overlap policies decide what happens when a scheduled run is due
+// while the previous one is still running
+const (
+	ScheduleOverlapSkip           = "skip" // default
+	ScheduleOverlapBufferOne      = "buffer_one"
+	ScheduleOverlapBufferAll      = "buffer_all"
+	ScheduleOverlapCancelOther    = "cancel_other"
+	ScheduleOverlapTerminateOther = "terminate_other"
+	ScheduleOverlapAllowAll       = "allow_all"
+)
+
+// ScheduleOverlapPolicies lists the supported overlap policies
+var ScheduleOverlapPolicies = []string{
+	ScheduleOverlapSkip,
+	ScheduleOverlapBufferOne,
+	ScheduleOverlapBufferAll,
+	ScheduleOverlapCancelOther,
+	ScheduleOverlapTerminateOther,
+	ScheduleOverlapAllowAll,
+}
+
+// ScheduleOptions tunes how a workflow's cron schedule starts runs
+type ScheduleOptions struct {
+	Timezone string `json:"timezone,omitempty"` // IANA time zone name, UTC by default
+	Jitter   string `json:"jitter,omitempty"`   // random delay of up to this duration, e.g. "30s"
+	Overlap  string `json:"overlap,omitempty"`  // one of ScheduleOverlapPolicies
+}
+
+// ScheduleID returns the ID of the Temporal schedule that runs a workflow
+func ScheduleID(workflowID uuid.UUID) string {
+	return "workflow-" + workflowID.String()
+}
+
+// ScheduleTrigger returns the triggered_by value of executions started by a
+// schedule
+func ScheduleTrigger(scheduleID string) string {
+	return "schedule:" + scheduleID
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxSecretValueSize is the largest secret value accepted, in bytes
+const MaxSecretValueSize = 64 * 1024
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,127}$`)
+
+// Secret is a tenant secret that workflow steps reference as
+// ${secrets.NAME}. The value is envelope encrypted: EncryptedValue is
+// sealed with a per-secret data key, and EncryptedKey is that data key
+// sealed with the master key. Neither is ever returned by the API.
+type Secret struct {
+	ID             uuid.UUID  `json:"id"`
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	Name           string     `json:"name"`
+	Description    *string    `json:"description,omitempty"`
+	EncryptedValue []byte     `json:"-"`
+	EncryptedKey   []byte     `json:"-"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ValidSecretName reports whether name can be used in a ${secrets.NAME}
+// reference: letters, digits and underscores, not starting with a digit
+func ValidSecretName(name string) bool {
+	return secretNamePattern.MatchString(name)
+}
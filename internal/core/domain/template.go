@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// WorkflowTemplate is a parameterized workflow definition from the built-in
+// catalog. The definition refers to parameters with ${params.name}; they are
+// replaced when the template is instantiated, while other references such as
+// ${input.host} are left for the run. Templates are never changed once
+// published: changes ship as a new version.
+type WorkflowTemplate struct {
+	Name        string                 `json:"name"`
+	Version     int                    `json:"version"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description"`
+	Parameters  *InputSchema           `json:"parameters"`
+	Example     map[string]interface{} `json:"example,omitempty"` // parameters that instantiate the template
+	Definition  json.RawMessage        `json:"definition"`
+}
+
+// templateParameter matches a parameter reference, e.g. ${params.url}. A
+// leading "$" escapes the reference, as "$${" does in run-time templates.
+var templateParameter = regexp.MustCompile(`\$?\$\{\s*params\.([A-Za-z_][A-Za-z0-9_]*)\s*\}`)
+
+// Check returns the problems in the template itself: a broken parameter
+// schema, references to undeclared parameters and parameters that may be
+// left unset
+func (t *WorkflowTemplate) Check() []DefinitionError {
+	var errs []DefinitionError
+	add := func(path, format string, args ...interface{}) {
+		errs = append(errs, DefinitionError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if t.Name == "" {
+		add("$.name", "is required")
+	}
+	if t.Version < 1 {
+		add("$.version", "must be at least 1")
+	}
+	schema := t.Parameters
+	if schema == nil {
+		schema = &InputSchema{Type: InputTypeObject}
+	}
+	errs = append(errs, schema.Check("$.parameters")...)
+
+	def, err := decodeDefinition(t.Definition)
+	if err != nil {
+		add("$.definition", "is not a JSON object")
+		return errs
+	}
+	required := map[string]bool{}
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+	for _, name := range sortedSet(referencedParameters(def)) {
+		prop := schema.Properties[name]
+		switch {
+		case prop == nil:
+			add("$.definition", "references undeclared parameter %q", name)
+		case !required[name] && prop.Default == nil:
+			add("$.parameters.properties."+name, "must be required or have a default, since the definition references it")
+		}
+	}
+	return errs
+}
+
+// Instantiate validates params against the template's parameters and
+// returns the definition with parameter references replaced. A string made
+// of a single reference takes the parameter's value and type; otherwise the
+// value is formatted into the string.
+func (t *WorkflowTemplate) Instantiate(params map[string]interface{}) (json.RawMessage, []InputError) {
+	schema := t.Parameters
+	if schema == nil {
+		schema = &InputSchema{Type: InputTypeObject}
+	}
+
+	var errs []InputError
+	for _, name := range sortedKeys(params) {
+		if schema.Properties[name] == nil {
+			errs = append(errs, InputError{Field: name, Message: "is not a parameter of this template"})
+		}
+	}
+	values, inputErrs := schema.Apply(params)
+	errs = append(errs, inputErrs...)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	def, err := decodeDefinition(t.Definition)
+	if err != nil {
+		return nil, []InputError{{Field: "definition", Message: "is not a JSON object"}}
+	}
+	data, err := json.Marshal(substituteParameters(def, values))
+	if err != nil {
+		return nil, []InputError{{Field: "definition", Message: err.Error()}}
+	}
+	return data, nil
+}
+
+// substituteParameters replaces the parameter references in the strings of
+// a decoded definition
+func substituteParameters(value interface{}, params map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if m := templateParameter.FindStringSubmatch(v); m != nil && m[0] == v && !strings.HasPrefix(v, "$$") {
+			return params[m[1]]
+		}
+		return templateParameter.ReplaceAllStringFunc(v, func(ref string) string {
+			if strings.HasPrefix(ref, "$$") {
+				return ref
+			}
+			param := params[templateParameter.FindStringSubmatch(ref)[1]]
+			if s, ok := param.(string); ok {
+				return s
+			}
+			data, _ := json.Marshal(param)
+			return string(data)
+		})
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = substituteParameters(item, params)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = substituteParameters(item, params)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// referencedParameters returns the names of the parameters a decoded
+// definition refers to
+func referencedParameters(value interface{}) map[string]bool {
+	refs := map[string]bool{}
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, m := range templateParameter.FindAllStringSubmatch(v, -1) {
+				if !strings.HasPrefix(m[0], "$$") {
+					refs[m[1]] = true
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return refs
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTemplate() *WorkflowTemplate {
+	return &WorkflowTemplate{
+		Name:    "restart",
+		Version: 1,
+		Parameters: &InputSchema{
+			Type:     InputTypeObject,
+			Required: []string{"url"},
+			Properties: map[string]*InputSchema{
+				"url":     {Type: InputTypeString, Pattern: "^https?://"},
+				"retries": {Type: InputTypeInteger, Default: float64(3)},
+				"codes":   {Type: InputTypeArray, Items: &InputSchema{Type: InputTypeInteger}, Default: []interface{}{float64(200)}},
+			},
+		},
+		Definition: json.RawMessage(`{"steps":[{"id":"call","type":"http","config":{` +
+			`"url":"${params.url}","success_codes":"${params.codes}","timeout":1.50,` +
+			`"headers":{"X-Retries":"max ${params.retries}","X-Host":"${input.host}","X-Literal":"$${params.url}"}}}]}`),
+	}
+}
+
+func TestWorkflowTemplate_Instantiate(t *testing.T) {
+	t.Run("replaces parameter references", func(t *testing.T) {
+		definition, errs := testTemplate().Instantiate(map[string]interface{}{"url": "https://api.internal/restart"})
+
+		require.Empty(t, errs)
+		assert.JSONEq(t, `{"steps":[{"id":"call","type":"http","config":{`+
+			`"url":"https://api.internal/restart","success_codes":[200],"timeout":1.50,`+
+			`"headers":{"X-Retries":"max 3","X-Host":"${input.host}","X-Literal":"$${params.url}"}}}]}`, string(definition))
+	})
+
+	t.Run("rejects invalid and unknown parameters", func(t *testing.T) {
+		_, errs := testTemplate().Instantiate(map[string]interface{}{"retries": 1.5, "method": "GET"})
+
+		assert.ElementsMatch(t, []InputError{
+			{Field: "method", Message: "is not a parameter of this template"},
+			{Field: "url", Message: "is required"},
+			{Field: "retries", Message: "must be an integer"},
+		}, errs)
+	})
+}
+
+func TestWorkflowTemplate_Check(t *testing.T) {
+	t.Run("accepts a valid template", func(t *testing.T) {
+		assert.Empty(t, testTemplate().Check())
+	})
+
+	t.Run("finds undeclared and unset parameters", func(t *testing.T) {
+		template := testTemplate()
+		template.Version = 0
+		template.Parameters.Properties["codes"].Default = nil
+		template.Definition = json.RawMessage(`{"steps":[{"id":"call","type":"http","config":{"url":"${params.url}","method":"${params.method}","success_codes":"${params.codes}"}}]}`)
+
+		assert.Equal(t, []DefinitionError{
+			{Path: "$.version", Message: "must be at least 1"},
+			{Path: "$.parameters.properties.codes", Message: "must be required or have a default, since the definition references it"},
+			{Path: "$.definition", Message: `references undeclared parameter "method"`},
+		}, template.Check())
+	})
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowVersion is an immutable snapshot of a published workflow definition
+type WorkflowVersion struct {
+	ID           uuid.UUID
+	TenantID     uuid.UUID
+	WorkflowID   uuid.UUID
+	Version      int32
+	Definition   json.RawMessage
+	RestoredFrom *int32 // version this one was rolled back to, if any
+	CreatedBy    *uuid.UUID
+	CreatedAt    time.Time
+}
+
+// Definition change operations
+const (
+	DefinitionChangeAdded   = "added"
+	DefinitionChangeRemoved = "removed"
+	DefinitionChangeChanged = "changed"
+)
+
+// DefinitionChange is a single difference between two workflow definitions.
+// Path is a JSON path into the definition, e.g. "$.steps[2].config.url".
+type DefinitionChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// DiffDefinitions returns the changes that turn definition from into to.
+// Objects are compared key by key and arrays element by element, so
+// inserting a step shows up as changes to every step after it.
+func DiffDefinitions(from, to json.RawMessage) ([]DefinitionChange, error) {
+	var a, b interface{}
+	if len(from) > 0 {
+		if err := json.Unmarshal(from, &a); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDefinition, err)
+		}
+	}
+	if len(to) > 0 {
+		if err := json.Unmarshal(to, &b); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDefinition, err)
+		}
+	}
+
+	changes := []DefinitionChange{}
+	diffValues("$", a, b, &changes)
+	return changes, nil
+}
+
+func diffValues(path string, a, b interface{}, changes *[]DefinitionChange) {
+	switch {
+	case reflect.DeepEqual(a, b):
+		return
+	case a == nil:
+		*changes = append(*changes, DefinitionChange{Path: path, Op: DefinitionChangeAdded, To: b})
+		return
+	case b == nil:
+		*changes = append(*changes, DefinitionChange{Path: path, Op: DefinitionChangeRemoved, From: a})
+		return
+	}
+
+	objA, okA := a.(map[string]interface{})
+	objB, okB := b.(map[string]interface{})
+	if okA && okB {
+		keys := make(map[string]bool, len(objA)+len(objB))
+		for k := range objA {
+			keys[k] = true
+		}
+		for k := range objB {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffValues(path+"."+k, objA[k], objB[k], changes)
+		}
+		return
+	}
+
+	listA, okA := a.([]interface{})
+	listB, okB := b.([]interface{})
+	if okA && okB {
+		for i := 0; i < len(listA) || i < len(listB); i++ {
+			var itemA, itemB interface{}
+			if i < len(listA) {
+				itemA = listA[i]
+			}
+			if i < len(listB) {
+				itemB = listB[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), itemA, itemB, changes)
+		}
+		return
+	}
+
+	*changes = append(*changes, DefinitionChange{Path: path, Op: DefinitionChangeChanged, From: a, To: b})
+}
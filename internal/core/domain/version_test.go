@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffDefinitions(t *testing.T) {
+	t.Run("equal definitions have no changes", func(t *testing.T) {
+		changes, err := DiffDefinitions(
+			json.RawMessage(`{"steps": [{"id": "a", "type": "log"}]}`),
+			json.RawMessage(`{"steps":[{"type":"log","id":"a"}]}`))
+
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("reports added, removed and changed values", func(t *testing.T) {
+		changes, err := DiffDefinitions(
+			json.RawMessage(`{"timeout": "5m", "steps": [{"id": "a", "type": "http", "config": {"url": "http://a"}}]}`),
+			json.RawMessage(`{"steps": [{"id": "a", "type": "http", "config": {"url": "http://b"}}, {"id": "b", "type": "log"}]}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, []DefinitionChange{
+			{Path: "$.steps[0].config.url", Op: DefinitionChangeChanged, From: "http://a", To: "http://b"},
+			{Path: "$.steps[1]", Op: DefinitionChangeAdded, To: map[string]interface{}{"id": "b", "type": "log"}},
+			{Path: "$.timeout", Op: DefinitionChangeRemoved, From: "5m"},
+		}, changes)
+	})
+
+	t.Run("rejects invalid JSON", func(t *testing.T) {
+		_, err := DiffDefinitions(json.RawMessage(`{`), json.RawMessage(`{}`))
+
+		assert.ErrorIs(t, err, ErrInvalidDefinition)
+	})
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,17 +10,19 @@ import (
 
 // Workflow represents a workflow entity in the domain
 type Workflow struct {
-	ID          uuid.UUID
-	TenantID    uuid.UUID
-	Name        string
-	Description *string
-	Definition  json.RawMessage
-	Schedule    *string
-	Status      WorkflowStatus
-	Version     int32
-	CreatedBy   *uuid.UUID
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID                 uuid.UUID
+	TenantID           uuid.UUID
+	Name               string
+	Description        *string
+	Definition         json.RawMessage
+	DefinitionComments DefinitionComments // comments of a definition written in YAML
+	Schedule           *string            // cron expression, nil when the workflow isn't scheduled
+	ScheduleOptions    *ScheduleOptions
+	Status             WorkflowStatus
+	Version            int32
+	CreatedBy          *uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
 }
 
 // WorkflowStatus represents the status of a workflow
@@ -31,9 +34,22 @@ const (
 	WorkflowStatusInactive WorkflowStatus = "inactive"
 )
 
+// DefinitionComments are the comments of a definition written in YAML, by
+// JSON path into the definition, e.g. "$.steps[0].config.url"
+type DefinitionComments map[string]DefinitionComment
+
+// DefinitionComment holds the comments around a node of a YAML definition,
+// including their "#" markers
+type DefinitionComment struct {
+	Head string `json:"head,omitempty"` // on the lines above the node
+	Line string `json:"line,omitempty"` // at the end of the node's line
+	Foot string `json:"foot,omitempty"` // on the lines below the node
+}
+
 // WorkflowDefinition represents the structure of a workflow definition
 type WorkflowDefinition struct {
-	Steps []WorkflowStep `json:"steps"`
+	Inputs *InputSchema   `json:"inputs,omitempty"`
+	Steps  []WorkflowStep `json:"steps"`
 }
 
 // WorkflowStep represents a single step in a workflow
@@ -43,11 +59,44 @@ type WorkflowStep struct {
 	Config map[string]interface{} `json:"config"`
 }
 
+// DefinitionError describes a problem in a workflow definition. Path is a
+// JSON path into the definition, e.g. "$.steps[2].config.url".
+type DefinitionError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// DefinitionValidationError is returned when a workflow definition fails
+// validation. It wraps ErrInvalidDefinition.
+type DefinitionValidationError struct {
+	Errors []DefinitionError
+}
+
+func (e *DefinitionValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return ErrInvalidDefinition.Error()
+	}
+	msg := fmt.Sprintf("%s: %s: %s", ErrInvalidDefinition, e.Errors[0].Path, e.Errors[0].Message)
+	if len(e.Errors) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Errors)-1)
+	}
+	return msg
+}
+
+func (e *DefinitionValidationError) Unwrap() error {
+	return ErrInvalidDefinition
+}
+
 // CanExecute checks if the workflow can be executed
 func (w *Workflow) CanExecute() bool {
 	return w.Status == WorkflowStatusActive
 }
 
+// IsScheduled checks if the workflow runs on a cron schedule
+func (w *Workflow) IsScheduled() bool {
+	return w.Schedule != nil && *w.Schedule != ""
+}
+
 // Activate activates the workflow if it has a valid definition
 func (w *Workflow) Activate() error {
 	def, err := w.ParseDefinition()
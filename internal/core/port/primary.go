@@ -23,7 +23,30 @@ type WorkflowService interface {
 	Update(ctx context.Context, id uuid.UUID, input UpdateWorkflowInput) (*domain.Workflow, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	Execute(ctx context.Context, id uuid.UUID, userID string, input map[string]interface{}) (*domain.Execution, error)
+	ExecuteVersion(ctx context.Context, id uuid.UUID, version int32, userID string, input map[string]interface{}) (*domain.Execution, error)
 	ListExecutions(ctx context.Context, workflowID uuid.UUID, page, limit int) (*ExecutionListResult, error)
+	ValidateDefinition(ctx context.Context, definition []byte) []domain.DefinitionError
+	DryRun(ctx context.Context, id uuid.UUID, input DryRunInput) (*domain.DryRun, error)
+
+	// Versions
+	ListVersions(ctx context.Context, workflowID uuid.UUID, page, limit int) (*WorkflowVersionListResult, error)
+	GetVersion(ctx context.Context, workflowID uuid.UUID, version int32) (*domain.WorkflowVersion, error)
+	DiffVersions(ctx context.Context, workflowID uuid.UUID, from, to int32) ([]domain.DefinitionChange, error)
+	Rollback(ctx context.Context, workflowID uuid.UUID, version int32, userID *uuid.UUID) (*domain.Workflow, error)
+}
+
+// BundleService defines the primary port for moving workflows and the
+// objects they depend on between tenants
+type BundleService interface {
+	Export(ctx context.Context, tenantID, workflowID uuid.UUID) (*domain.WorkflowBundle, error)
+	Import(ctx context.Context, input ImportBundleInput) (*domain.BundleImport, error)
+}
+
+// TemplateService defines the primary port for the workflow template catalog
+type TemplateService interface {
+	List(ctx context.Context) []*domain.WorkflowTemplate
+	Get(ctx context.Context, name string, version int) (*domain.WorkflowTemplate, error)
+	Instantiate(ctx context.Context, input InstantiateTemplateInput) (*domain.Workflow, error)
 }
 
 // ExecutionService defines the primary port for execution operations
@@ -31,7 +54,15 @@ type ExecutionService interface {
 	List(ctx context.Context, tenantID uuid.UUID, page, limit int) (*ExecutionListResult, error)
 	ListByWorkflow(ctx context.Context, workflowID uuid.UUID, page, limit int) (*ExecutionListResult, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Execution, error)
+	ListChildren(ctx context.Context, id uuid.UUID) ([]*domain.Execution, error)
+	ListSteps(ctx context.Context, id uuid.UUID) ([]*domain.ExecutionStep, error)
 	Cancel(ctx context.Context, id uuid.UUID) error
+	Retry(ctx context.Context, input RetryExecutionInput) (*domain.Execution, error)
+	ListApprovals(ctx context.Context, id uuid.UUID) ([]*domain.Approval, error)
+	DecideApproval(ctx context.Context, input DecideApprovalInput) (*domain.ApprovalDecision, error)
+	Pause(ctx context.Context, input PauseExecutionInput) error
+	Resume(ctx context.Context, input PauseExecutionInput) error
+	GetProgress(ctx context.Context, id uuid.UUID) (*domain.ExecutionProgress, error)
 }
 
 // AlertService defines the primary port for alert operations
@@ -53,6 +84,16 @@ type AlertRuleService interface {
 	Evaluate(ctx context.Context, tenantID uuid.UUID, metricName string, value float64) error
 }
 
+// SecretService defines the primary port for tenant secret operations.
+// Secret values are write-only: no method returns them.
+type SecretService interface {
+	List(ctx context.Context, tenantID uuid.UUID) ([]*domain.Secret, error)
+	Get(ctx context.Context, tenantID, id uuid.UUID) (*domain.Secret, error)
+	Create(ctx context.Context, input CreateSecretInput) (*domain.Secret, error)
+	Update(ctx context.Context, input UpdateSecretInput) (*domain.Secret, error)
+	Delete(ctx context.Context, tenantID, id uuid.UUID, userID *uuid.UUID) error
+}
+
 // AuditService defines the primary port for audit log operations
 type AuditService interface {
 	List(ctx context.Context, tenantID uuid.UUID, page, limit int) (*AuditListResult, error)
@@ -88,20 +129,31 @@ type MetricService interface {
 // Workflow DTOs
 
 type CreateWorkflowInput struct {
-	TenantID    uuid.UUID
-	Name        string
-	Description *string
-	Definition  []byte
-	Schedule    *string
-	CreatedBy   *uuid.UUID
+	TenantID           uuid.UUID
+	Name               string
+	Description        *string
+	Definition         []byte
+	DefinitionComments domain.DefinitionComments
+	Schedule           *string
+	ScheduleOptions    *domain.ScheduleOptions
+	CreatedBy          *uuid.UUID
 }
 
 type UpdateWorkflowInput struct {
-	Name        *string
-	Description *string
-	Definition  []byte
-	Schedule    *string
-	Status      *domain.WorkflowStatus
+	Name               *string
+	Description        *string
+	Definition         []byte
+	DefinitionComments domain.DefinitionComments // replace the comments of the old definition
+	Schedule           *string                   // an empty string removes the schedule
+	ScheduleOptions    *domain.ScheduleOptions
+	Status             *domain.WorkflowStatus
+	UpdatedBy          *uuid.UUID
+}
+
+type DryRunInput struct {
+	Version  int32 // 0 simulates the latest version
+	Input    map[string]interface{}
+	Fixtures map[string]interface{} // simulated step outputs by step ID
 }
 
 type WorkflowListResult struct {
@@ -111,6 +163,35 @@ type WorkflowListResult struct {
 	Limit     int
 }
 
+type WorkflowVersionListResult struct {
+	Versions []*domain.WorkflowVersion
+	Total    int64
+	Page     int
+	Limit    int
+}
+
+// Bundle DTOs
+
+type ImportBundleInput struct {
+	TenantID   uuid.UUID
+	Bundle     *domain.WorkflowBundle
+	Strategy   domain.ConflictStrategy // for objects whose name is taken
+	KeepStatus bool                    // give workflows their bundled status instead of importing them as drafts
+	UserID     *uuid.UUID
+}
+
+// Template DTOs
+
+type InstantiateTemplateInput struct {
+	TenantID    uuid.UUID
+	Template    string
+	Version     int    // 0 uses the latest version
+	Name        string // defaults to the template name
+	Description *string
+	Parameters  map[string]interface{}
+	CreatedBy   *uuid.UUID
+}
+
 // Execution DTOs
 
 type ExecutionListResult struct {
@@ -120,6 +201,27 @@ type ExecutionListResult struct {
 	Limit      int
 }
 
+type DecideApprovalInput struct {
+	ExecutionID uuid.UUID
+	ApprovalID  string
+	Action      domain.ApprovalAction
+	UserID      uuid.UUID
+	User        string
+	Comment     string
+}
+
+type PauseExecutionInput struct {
+	ExecutionID uuid.UUID
+	UserID      uuid.UUID
+	User        string
+}
+
+type RetryExecutionInput struct {
+	ExecutionID uuid.UUID
+	Mode        domain.RetryMode
+	UserID      uuid.UUID
+}
+
 // Alert DTOs
 
 type CreateAlertInput struct {
@@ -143,32 +245,34 @@ type AlertListResult struct {
 // AlertRule DTOs
 
 type CreateAlertRuleInput struct {
-	TenantID             uuid.UUID
-	Name                 string
-	Description          *string
-	ConditionType        string
-	ConditionConfig      []byte
-	Severity             domain.AlertSeverity
-	AlertTitleTemplate   string
-	AlertMessageTemplate *string
-	TriggerWorkflowID    *uuid.UUID
-	TriggerInputTemplate []byte
-	CooldownSeconds      int32
-	CreatedBy            uuid.UUID
+	TenantID               uuid.UUID
+	Name                   string
+	Description            *string
+	ConditionType          string
+	ConditionConfig        []byte
+	Severity               domain.AlertSeverity
+	AlertTitleTemplate     string
+	AlertMessageTemplate   *string
+	TriggerWorkflowID      *uuid.UUID
+	TriggerWorkflowVersion *int32 // nil follows the latest version
+	TriggerInputTemplate   []byte
+	CooldownSeconds        int32
+	CreatedBy              uuid.UUID
 }
 
 type UpdateAlertRuleInput struct {
-	Name                 *string
-	Description          *string
-	Enabled              *bool
-	ConditionType        *string
-	ConditionConfig      []byte
-	Severity             *domain.AlertSeverity
-	AlertTitleTemplate   *string
-	AlertMessageTemplate *string
-	TriggerWorkflowID    *uuid.UUID
-	TriggerInputTemplate []byte
-	CooldownSeconds      *int32
+	Name                   *string
+	Description            *string
+	Enabled                *bool
+	ConditionType          *string
+	ConditionConfig        []byte
+	Severity               *domain.AlertSeverity
+	AlertTitleTemplate     *string
+	AlertMessageTemplate   *string
+	TriggerWorkflowID      *uuid.UUID
+	TriggerWorkflowVersion *int32 // 0 switches back to following the latest version
+	TriggerInputTemplate   []byte
+	CooldownSeconds        *int32
 }
 
 type AlertRuleListResult struct {
@@ -178,6 +282,24 @@ type AlertRuleListResult struct {
 	Limit int
 }
 
+// Secret DTOs
+
+type CreateSecretInput struct {
+	TenantID    uuid.UUID
+	Name        string
+	Description *string
+	Value       string
+	CreatedBy   *uuid.UUID
+}
+
+type UpdateSecretInput struct {
+	TenantID    uuid.UUID
+	ID          uuid.UUID
+	Description *string
+	Value       *string // nil keeps the current value
+	UpdatedBy   *uuid.UUID
+}
+
 // Audit DTOs
 
 type AuditListResult struct {
@@ -17,6 +17,7 @@ import (
 // WorkflowRepository defines the interface for workflow persistence
 type WorkflowRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Workflow, error)
+	FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Workflow, error)
 	FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Workflow, error)
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	Save(ctx context.Context, workflow *domain.Workflow) error
@@ -24,16 +25,37 @@ type WorkflowRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// WorkflowVersionRepository defines the interface for workflow version persistence
+type WorkflowVersionRepository interface {
+	Save(ctx context.Context, version *domain.WorkflowVersion) error
+	FindByVersion(ctx context.Context, workflowID uuid.UUID, version int32) (*domain.WorkflowVersion, error)
+	LatestVersion(ctx context.Context, workflowID uuid.UUID) (int32, error) // 0 when the workflow has no versions
+	FindByWorkflow(ctx context.Context, workflowID uuid.UUID, limit, offset int) ([]*domain.WorkflowVersion, error)
+	CountByWorkflow(ctx context.Context, workflowID uuid.UUID) (int64, error)
+}
+
 // ExecutionRepository defines the interface for execution persistence
 type ExecutionRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Execution, error)
 	FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Execution, error)
 	FindByWorkflow(ctx context.Context, workflowID uuid.UUID, limit, offset int) ([]*domain.Execution, error)
+	FindByParent(ctx context.Context, parentID uuid.UUID) ([]*domain.Execution, error)
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	Save(ctx context.Context, execution *domain.Execution) error
 	Update(ctx context.Context, execution *domain.Execution) error
 	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.ExecutionStatus, errMsg *string) error
 	UpdateTemporalIDs(ctx context.Context, id uuid.UUID, temporalWorkflowID, temporalRunID string) error
+	// MarkRunning records the Temporal IDs of a started execution and moves
+	// it from pending to running. Executions the worker already finished
+	// keep their status.
+	MarkRunning(ctx context.Context, id uuid.UUID, temporalWorkflowID, temporalRunID string) (*domain.Execution, error)
+}
+
+// ExecutionStepRepository defines the interface for execution step persistence
+type ExecutionStepRepository interface {
+	// Save inserts the step or replaces the step recorded at the same position
+	Save(ctx context.Context, step *domain.ExecutionStep) error
+	FindByExecution(ctx context.Context, executionID uuid.UUID) ([]*domain.ExecutionStep, error)
 }
 
 // AlertRepository defines the interface for alert persistence
@@ -49,6 +71,8 @@ type AlertRepository interface {
 type AlertRuleRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error)
 	FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.AlertRule, error)
+	FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.AlertRule, error)
+	FindByWorkflow(ctx context.Context, tenantID, workflowID uuid.UUID) ([]*domain.AlertRule, error)
 	FindEnabledByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.AlertRule, error)
 	CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	Save(ctx context.Context, rule *domain.AlertRule) error
@@ -96,11 +120,67 @@ type MetricDefinitionRepository interface {
 	Delete(ctx context.Context, tenantID uuid.UUID, name string) error
 }
 
+// SecretRepository defines the interface for secret persistence. Secrets
+// are stored encrypted.
+type SecretRepository interface {
+	FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Secret, error)
+	FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Secret, error)
+	FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.Secret, error)
+	Save(ctx context.Context, secret *domain.Secret) error
+	Update(ctx context.Context, secret *domain.Secret) error
+	Delete(ctx context.Context, tenantID, id uuid.UUID) error
+}
+
+// SecretCipher envelope-encrypts secret values: each value is sealed with a
+// fresh data key, which is returned sealed with the master key
+type SecretCipher interface {
+	Encrypt(plaintext []byte) (value, key []byte, err error)
+	Decrypt(value, key []byte) ([]byte, error)
+}
+
 // WorkflowExecutor defines the interface for executing workflows via Temporal
 type WorkflowExecutor interface {
-	Execute(ctx context.Context, workflow *domain.Workflow, input map[string]interface{}) (*ExecuteResult, error)
+	// Execute starts a run of the workflow. A retried execution passes where
+	// to resume; resume is nil for a full run.
+	Execute(ctx context.Context, workflow *domain.Workflow, executionID uuid.UUID, input map[string]interface{}, resume *domain.ExecutionResume) (*ExecuteResult, error)
 	Cancel(ctx context.Context, temporalWorkflowID string) error
 	GetStatus(ctx context.Context, temporalWorkflowID string) (string, error)
+	ListApprovals(ctx context.Context, temporalWorkflowID string) ([]*domain.Approval, error)
+	SubmitApproval(ctx context.Context, temporalWorkflowID string, decision domain.ApprovalDecision) error
+	// ListSteps queries a running workflow for the steps it has finished and
+	// the step it is running
+	ListSteps(ctx context.Context, temporalWorkflowID string) ([]*domain.ExecutionStep, error)
+	// Pause asks a running workflow to wait before starting its next step;
+	// Resume lets it continue
+	Pause(ctx context.Context, temporalWorkflowID string, pause domain.ExecutionPause) error
+	Resume(ctx context.Context, temporalWorkflowID string, resume domain.ExecutionPause) error
+	// GetProgress queries a running workflow for its current step, finished
+	// steps and outputs, and whether it is paused
+	GetProgress(ctx context.Context, temporalWorkflowID string) (*domain.ExecutionProgress, error)
+	// DryRun simulates a run of the workflow without side effects and waits
+	// for its trace. Fixtures override the output of simulated steps by
+	// step ID.
+	DryRun(ctx context.Context, workflow *domain.Workflow, input map[string]interface{}, fixtures map[string]interface{}) (*domain.DryRun, error)
+}
+
+// WorkflowScheduler runs workflows on their cron schedule
+type WorkflowScheduler interface {
+	// Sync creates, updates, pauses or removes the workflow's schedule to
+	// match its cron expression, schedule options and status
+	Sync(ctx context.Context, workflow *domain.Workflow) error
+	// Delete removes the workflow's schedule if it has one
+	Delete(ctx context.Context, workflowID uuid.UUID) error
+}
+
+// DefinitionValidator checks workflow definitions before they are stored or run
+type DefinitionValidator interface {
+	Validate(definition []byte) []domain.DefinitionError
+}
+
+// TemplateCatalog provides the built-in workflow templates
+type TemplateCatalog interface {
+	List() []*domain.WorkflowTemplate                                // the latest version of each template
+	Find(name string, version int) (*domain.WorkflowTemplate, error) // version 0 is the latest
 }
 
 // ExecuteResult represents the result of starting a workflow execution
@@ -11,11 +11,11 @@ import (
 
 // AlertRuleService implements port.AlertRuleService
 type AlertRuleService struct {
-	ruleRepo       port.AlertRuleRepository
-	alertService   port.AlertService
+	ruleRepo        port.AlertRuleRepository
+	alertService    port.AlertService
 	workflowService port.WorkflowService
-	auditService   port.AuditService
-	tenantSetter   port.TenantContextSetter
+	auditService    port.AuditService
+	tenantSetter    port.TenantContextSetter
 }
 
 // NewAlertRuleService creates a new alert rule service
@@ -27,11 +27,11 @@ func NewAlertRuleService(
 	tenantSetter port.TenantContextSetter,
 ) *AlertRuleService {
 	return &AlertRuleService{
-		ruleRepo:       ruleRepo,
-		alertService:   alertService,
+		ruleRepo:        ruleRepo,
+		alertService:    alertService,
 		workflowService: workflowService,
-		auditService:   auditService,
-		tenantSetter:   tenantSetter,
+		auditService:    auditService,
+		tenantSetter:    tenantSetter,
 	}
 }
 
@@ -73,22 +73,23 @@ func (s *AlertRuleService) Create(ctx context.Context, input port.CreateAlertRul
 	}
 
 	rule := &domain.AlertRule{
-		ID:                   uuid.New(),
-		TenantID:             input.TenantID,
-		Name:                 input.Name,
-		Description:          input.Description,
-		Enabled:              true,
-		ConditionType:        input.ConditionType,
-		ConditionConfig:      input.ConditionConfig,
-		Severity:             input.Severity,
-		AlertTitleTemplate:   input.AlertTitleTemplate,
-		AlertMessageTemplate: input.AlertMessageTemplate,
-		TriggerWorkflowID:    input.TriggerWorkflowID,
-		TriggerInputTemplate: input.TriggerInputTemplate,
-		CooldownSeconds:      input.CooldownSeconds,
-		CreatedBy:            &input.CreatedBy,
-		CreatedAt:            time.Now(),
-		UpdatedAt:            time.Now(),
+		ID:                     uuid.New(),
+		TenantID:               input.TenantID,
+		Name:                   input.Name,
+		Description:            input.Description,
+		Enabled:                true,
+		ConditionType:          input.ConditionType,
+		ConditionConfig:        input.ConditionConfig,
+		Severity:               input.Severity,
+		AlertTitleTemplate:     input.AlertTitleTemplate,
+		AlertMessageTemplate:   input.AlertMessageTemplate,
+		TriggerWorkflowID:      input.TriggerWorkflowID,
+		TriggerWorkflowVersion: input.TriggerWorkflowVersion,
+		TriggerInputTemplate:   input.TriggerInputTemplate,
+		CooldownSeconds:        input.CooldownSeconds,
+		CreatedBy:              &input.CreatedBy,
+		CreatedAt:              time.Now(),
+		UpdatedAt:              time.Now(),
 	}
 
 	if err := s.ruleRepo.Save(ctx, rule); err != nil {
@@ -137,6 +138,12 @@ func (s *AlertRuleService) Update(ctx context.Context, id uuid.UUID, input port.
 	if input.TriggerWorkflowID != nil {
 		rule.TriggerWorkflowID = input.TriggerWorkflowID
 	}
+	if input.TriggerWorkflowVersion != nil {
+		rule.TriggerWorkflowVersion = input.TriggerWorkflowVersion
+		if *input.TriggerWorkflowVersion == 0 {
+			rule.TriggerWorkflowVersion = nil
+		}
+	}
 	if input.TriggerInputTemplate != nil {
 		rule.TriggerInputTemplate = input.TriggerInputTemplate
 	}
@@ -207,7 +214,11 @@ func (s *AlertRuleService) Evaluate(ctx context.Context, tenantID uuid.UUID, met
 
 			// Trigger workflow if configured
 			if rule.TriggerWorkflowID != nil {
-				s.workflowService.Execute(ctx, *rule.TriggerWorkflowID, "", nil)
+				var version int32
+				if rule.TriggerWorkflowVersion != nil {
+					version = *rule.TriggerWorkflowVersion
+				}
+				s.workflowService.ExecuteVersion(ctx, *rule.TriggerWorkflowID, version, "", nil)
 			}
 		}
 	}
@@ -0,0 +1,579 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+)
+
+// BundleService implements port.BundleService
+type BundleService struct {
+	workflowRepo    port.WorkflowRepository
+	ruleRepo        port.AlertRuleRepository
+	workflowService port.WorkflowService
+	metricService   port.MetricService
+	auditService    port.AuditService
+	tenantSetter    port.TenantContextSetter
+}
+
+// NewBundleService creates a new bundle service. Workflows and metric
+// definitions are imported through their services, so they are validated,
+// versioned and scheduled as if created by hand.
+func NewBundleService(
+	workflowRepo port.WorkflowRepository,
+	ruleRepo port.AlertRuleRepository,
+	workflowService port.WorkflowService,
+	metricService port.MetricService,
+	auditService port.AuditService,
+	tenantSetter port.TenantContextSetter,
+) *BundleService {
+	return &BundleService{
+		workflowRepo:    workflowRepo,
+		ruleRepo:        ruleRepo,
+		workflowService: workflowService,
+		metricService:   metricService,
+		auditService:    auditService,
+		tenantSetter:    tenantSetter,
+	}
+}
+
+// Export bundles a workflow with the child workflows it starts, directly or
+// through its children, the alert rules that trigger it and the metric
+// definitions they watch. Child workflows referenced by literal IDs or names
+// must exist; metrics without a definition are left out.
+func (s *BundleService) Export(ctx context.Context, tenantID, workflowID uuid.UUID) (*domain.WorkflowBundle, error) {
+	if err := s.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	root, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	if root.TenantID != tenantID {
+		return nil, domain.ErrWorkflowNotFound
+	}
+
+	bundle := &domain.WorkflowBundle{
+		FormatVersion:     domain.BundleFormatVersion,
+		ExportedAt:        time.Now().UTC(),
+		WorkflowID:        root.ID,
+		Workflows:         []domain.BundleWorkflow{},
+		AlertRules:        []domain.BundleAlertRule{},
+		MetricDefinitions: []domain.BundleMetricDefinition{},
+	}
+
+	var metrics []string
+	seenMetrics := map[string]bool{}
+	addMetric := func(name string) {
+		if name != "" && !seenMetrics[name] {
+			seenMetrics[name] = true
+			metrics = append(metrics, name)
+		}
+	}
+
+	// Walk the child workflows breadth first; workflows may start each other
+	seen := map[uuid.UUID]bool{root.ID: true}
+	queue := []*domain.Workflow{root}
+	for len(queue) > 0 {
+		wf := queue[0]
+		queue = queue[1:]
+		bundle.Workflows = append(bundle.Workflows, toBundleWorkflow(wf))
+
+		deps, err := domain.FindDefinitionDependencies(wf.Definition)
+		if err != nil {
+			return nil, fmt.Errorf("workflow %s: %w", wf.Name, err)
+		}
+		children, err := s.findChildWorkflows(ctx, tenantID, wf, deps)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				queue = append(queue, child)
+			}
+		}
+		for _, metric := range deps.Metrics {
+			addMetric(metric)
+		}
+	}
+
+	rules, err := s.ruleRepo.FindByWorkflow(ctx, tenantID, root.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		bundle.AlertRules = append(bundle.AlertRules, toBundleAlertRule(rule))
+		addMetric(rule.MetricName())
+	}
+
+	for _, name := range metrics {
+		def, err := s.metricService.GetDefinition(ctx, tenantID, name)
+		if errors.Is(err, domain.ErrMetricDefinitionNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		bundle.MetricDefinitions = append(bundle.MetricDefinitions, toBundleMetricDefinition(def))
+	}
+
+	return bundle, nil
+}
+
+// findChildWorkflows looks up the workflows a workflow's definition starts
+func (s *BundleService) findChildWorkflows(ctx context.Context, tenantID uuid.UUID, wf *domain.Workflow, deps *domain.DefinitionDependencies) ([]*domain.Workflow, error) {
+	var children []*domain.Workflow
+	for _, ref := range deps.WorkflowIDs {
+		id, err := uuid.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workflow %s starts workflow %q, which isn't a workflow ID", domain.ErrBundleDependencyNotFound, wf.Name, ref)
+		}
+		child, err := s.workflowRepo.FindByID(ctx, id)
+		if err == nil && child.TenantID != tenantID {
+			err = domain.ErrWorkflowNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: workflow %s starts workflow %s: %v", domain.ErrBundleDependencyNotFound, wf.Name, ref, err)
+		}
+		children = append(children, child)
+	}
+	for _, name := range deps.WorkflowNames {
+		child, err := s.workflowRepo.FindByName(ctx, tenantID, name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workflow %s starts workflow %q: %v", domain.ErrBundleDependencyNotFound, wf.Name, name, err)
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+// Import creates the objects of a bundle in a tenant. Objects whose name is
+// already taken are skipped, overwritten or renamed as the strategy says,
+// and references between the objects are remapped to their imported
+// copies. New workflows are imported as drafts, so scheduled workflows
+// don't start running in the new tenant, and overwritten ones keep their
+// status, unless input.KeepStatus asks for the bundled status. The whole
+// bundle is checked before anything is written; if a write fails, the
+// objects imported so far are kept and importing the bundle again with the
+// skip or overwrite strategy completes it.
+func (s *BundleService) Import(ctx context.Context, input port.ImportBundleInput) (*domain.BundleImport, error) {
+	if !input.Strategy.IsValid() {
+		return nil, domain.ErrInvalidConflictStrategy
+	}
+	if err := s.tenantSetter.SetTenantContext(ctx, input.TenantID); err != nil {
+		return nil, err
+	}
+	bundle := input.Bundle
+	if err := s.checkBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+
+	result := &domain.BundleImport{Objects: []domain.BundleImportedObject{}}
+
+	// Metric definitions go first so rules and steps watch described metrics
+	for _, def := range bundle.MetricDefinitions {
+		imported, err := s.importMetricDefinition(ctx, input, def)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import metric definition %s: %w", def.Name, err)
+		}
+		result.Objects = append(result.Objects, *imported)
+	}
+
+	// Workflows are created as drafts before any definition is set, so the
+	// definitions can point to the new IDs of workflows they start, even
+	// when workflows start each other
+	workflowIDs := map[uuid.UUID]uuid.UUID{}
+	ids := map[string]string{}
+	names := map[string]string{}
+	workflows := make([]domain.BundleImportedObject, len(bundle.Workflows))
+	for i, wf := range bundle.Workflows {
+		imported, err := s.claimWorkflow(ctx, input, wf)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import workflow %s: %w", wf.Name, err)
+		}
+		workflows[i] = *imported
+		workflowIDs[wf.ID] = imported.ID
+		ids[wf.ID.String()] = imported.ID.String()
+		if imported.Name != wf.Name {
+			names[wf.Name] = imported.Name
+		}
+		if wf.ID == bundle.WorkflowID {
+			result.WorkflowID = imported.ID
+		}
+	}
+	for i, wf := range bundle.Workflows {
+		if workflows[i].Action == domain.BundleActionSkipped {
+			continue
+		}
+		if err := s.writeWorkflow(ctx, input, wf, workflows[i], ids, names); err != nil {
+			return nil, fmt.Errorf("failed to import workflow %s: %w", wf.Name, err)
+		}
+	}
+	result.Objects = append(result.Objects, workflows...)
+
+	for _, rule := range bundle.AlertRules {
+		imported, err := s.importAlertRule(ctx, input, rule, workflowIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import alert rule %s: %w", rule.Name, err)
+		}
+		result.Objects = append(result.Objects, *imported)
+	}
+
+	// Log audit
+	s.logAudit(ctx, input, domain.AuditEventWorkflowImported, domain.ResourceTypeWorkflow, domain.ActionImport, result.WorkflowID, nil, result)
+
+	return result, nil
+}
+
+// checkBundle finds the problems of a bundle that would stop its import
+// halfway, definitions included
+func (s *BundleService) checkBundle(ctx context.Context, bundle *domain.WorkflowBundle) error {
+	if bundle == nil {
+		return &domain.BundleValidationError{Errors: []domain.DefinitionError{{Path: "$", Message: "is required"}}}
+	}
+	if bundle.FormatVersion != domain.BundleFormatVersion {
+		return &domain.BundleValidationError{Errors: []domain.DefinitionError{{
+			Path:    "$.format_version",
+			Message: fmt.Sprintf("must be %d", domain.BundleFormatVersion),
+		}}}
+	}
+
+	var errs []domain.DefinitionError
+	fail := func(path, message string) {
+		errs = append(errs, domain.DefinitionError{Path: path, Message: message})
+	}
+
+	workflows := map[uuid.UUID]bool{}
+	for i, wf := range bundle.Workflows {
+		path := fmt.Sprintf("$.workflows[%d]", i)
+		if wf.Name == "" {
+			fail(path+".name", "is required")
+		}
+		if workflows[wf.ID] {
+			fail(path+".id", "is used by another workflow")
+		}
+		workflows[wf.ID] = true
+		switch wf.Status {
+		case domain.WorkflowStatusDraft, domain.WorkflowStatusActive, domain.WorkflowStatusInactive:
+		default:
+			fail(path+".status", `must be one of "draft", "active" or "inactive"`)
+		}
+		if len(wf.Definition) == 0 && wf.Status == domain.WorkflowStatusActive {
+			fail(path+".definition", "is required for active workflows")
+		}
+		if len(wf.Definition) > 0 {
+			for _, e := range s.workflowService.ValidateDefinition(ctx, wf.Definition) {
+				fail(path+".definition"+strings.TrimPrefix(e.Path, "$"), e.Message)
+			}
+		}
+	}
+	if !workflows[bundle.WorkflowID] {
+		fail("$.workflow_id", "must be the ID of a bundled workflow")
+	}
+
+	for i, rule := range bundle.AlertRules {
+		path := fmt.Sprintf("$.alert_rules[%d]", i)
+		if rule.Name == "" {
+			fail(path+".name", "is required")
+		}
+		if rule.TriggerWorkflowID != nil && !workflows[*rule.TriggerWorkflowID] {
+			fail(path+".trigger_workflow_id", "must be the ID of a bundled workflow")
+		}
+	}
+
+	for i, def := range bundle.MetricDefinitions {
+		path := fmt.Sprintf("$.metric_definitions[%d]", i)
+		if def.Name == "" {
+			fail(path+".name", "is required")
+		}
+		if !def.Type.IsValid() {
+			fail(path+".type", domain.ErrInvalidMetricType.Error())
+		}
+		if !def.Aggregation.IsValid() {
+			fail(path+".aggregation", domain.ErrInvalidAggregationType.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return &domain.BundleValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (s *BundleService) importMetricDefinition(ctx context.Context, input port.ImportBundleInput, def domain.BundleMetricDefinition) (*domain.BundleImportedObject, error) {
+	imported := &domain.BundleImportedObject{Kind: domain.BundleObjectMetricDefinition, Name: def.Name}
+
+	existing, err := s.metricService.GetDefinition(ctx, input.TenantID, def.Name)
+	switch {
+	case errors.Is(err, domain.ErrMetricDefinitionNotFound):
+		created, err := s.metricService.CreateDefinition(ctx, port.CreateMetricDefinitionInput{
+			TenantID:       input.TenantID,
+			Name:           def.Name,
+			DisplayName:    def.DisplayName,
+			Description:    def.Description,
+			Unit:           def.Unit,
+			Type:           def.Type,
+			Aggregation:    def.Aggregation,
+			AlertThreshold: def.AlertThreshold,
+			RetentionDays:  def.RetentionDays,
+		})
+		if err != nil {
+			return nil, err
+		}
+		imported.ID = created.ID
+		imported.Action = domain.BundleActionCreated
+	case err != nil:
+		return nil, err
+	case input.Strategy == domain.ConflictOverwrite:
+		updated, err := s.metricService.UpdateDefinition(ctx, input.TenantID, def.Name, port.UpdateMetricDefinitionInput{
+			DisplayName:    def.DisplayName,
+			Description:    def.Description,
+			Unit:           def.Unit,
+			Type:           &def.Type,
+			Aggregation:    &def.Aggregation,
+			AlertThreshold: def.AlertThreshold,
+			RetentionDays:  &def.RetentionDays,
+		})
+		if err != nil {
+			return nil, err
+		}
+		imported.ID = updated.ID
+		imported.Action = domain.BundleActionOverwritten
+	default:
+		// Renaming a metric definition would describe another metric
+		imported.ID = existing.ID
+		imported.Action = domain.BundleActionSkipped
+	}
+	return imported, nil
+}
+
+// claimWorkflow finds or creates the workflow a bundled workflow is
+// imported into. Created workflows are drafts without a definition until
+// writeWorkflow fills them in.
+func (s *BundleService) claimWorkflow(ctx context.Context, input port.ImportBundleInput, wf domain.BundleWorkflow) (*domain.BundleImportedObject, error) {
+	sourceID := wf.ID
+	imported := &domain.BundleImportedObject{Kind: domain.BundleObjectWorkflow, SourceID: &sourceID, Name: wf.Name}
+
+	existing, err := s.workflowRepo.FindByName(ctx, input.TenantID, wf.Name)
+	switch {
+	case errors.Is(err, domain.ErrWorkflowNotFound):
+		imported.Action = domain.BundleActionCreated
+	case err != nil:
+		return nil, err
+	case input.Strategy == domain.ConflictSkip:
+		imported.ID = existing.ID
+		imported.Action = domain.BundleActionSkipped
+		return imported, nil
+	case input.Strategy == domain.ConflictOverwrite:
+		imported.ID = existing.ID
+		imported.Action = domain.BundleActionOverwritten
+		return imported, nil
+	default:
+		imported.Name, err = freeName(wf.Name, func(name string) (bool, error) {
+			_, err := s.workflowRepo.FindByName(ctx, input.TenantID, name)
+			if errors.Is(err, domain.ErrWorkflowNotFound) {
+				return true, nil
+			}
+			return false, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		imported.Action = domain.BundleActionRenamed
+	}
+
+	created, err := s.workflowService.Create(ctx, port.CreateWorkflowInput{
+		TenantID:    input.TenantID,
+		Name:        imported.Name,
+		Description: wf.Description,
+		CreatedBy:   input.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	imported.ID = created.ID
+	return imported, nil
+}
+
+// writeWorkflow sets the definition, schedule and status of an imported
+// workflow, with its child workflows remapped to their imported copies
+func (s *BundleService) writeWorkflow(ctx context.Context, input port.ImportBundleInput, wf domain.BundleWorkflow, imported domain.BundleImportedObject, ids, names map[string]string) error {
+	definition, err := domain.RemapChildWorkflows(wf.Definition, ids, names)
+	if err != nil {
+		return err
+	}
+	schedule := ""
+	if wf.Schedule != nil {
+		schedule = *wf.Schedule
+	}
+	var status *domain.WorkflowStatus
+	switch {
+	case input.KeepStatus:
+		status = &wf.Status
+	case imported.Action != domain.BundleActionOverwritten:
+		draft := domain.WorkflowStatusDraft
+		status = &draft
+	}
+
+	_, err = s.workflowService.Update(ctx, imported.ID, port.UpdateWorkflowInput{
+		Description:     wf.Description,
+		Definition:      definition,
+		Schedule:        &schedule,
+		ScheduleOptions: wf.ScheduleOptions,
+		Status:          status,
+		UpdatedBy:       input.UserID,
+	})
+	return err
+}
+
+func (s *BundleService) importAlertRule(ctx context.Context, input port.ImportBundleInput, rule domain.BundleAlertRule, workflowIDs map[uuid.UUID]uuid.UUID) (*domain.BundleImportedObject, error) {
+	sourceID := rule.ID
+	imported := &domain.BundleImportedObject{Kind: domain.BundleObjectAlertRule, SourceID: &sourceID, Name: rule.Name}
+
+	var triggerWorkflowID *uuid.UUID
+	if rule.TriggerWorkflowID != nil {
+		id := workflowIDs[*rule.TriggerWorkflowID]
+		triggerWorkflowID = &id
+	}
+
+	existing, err := s.ruleRepo.FindByName(ctx, input.TenantID, rule.Name)
+	switch {
+	case errors.Is(err, domain.ErrAlertRuleNotFound):
+		imported.Action = domain.BundleActionCreated
+	case err != nil:
+		return nil, err
+	case input.Strategy == domain.ConflictSkip:
+		imported.ID = existing.ID
+		imported.Action = domain.BundleActionSkipped
+		return imported, nil
+	case input.Strategy == domain.ConflictOverwrite:
+		oldRule := *existing
+		applyBundleAlertRule(existing, rule, triggerWorkflowID)
+		existing.UpdatedAt = time.Now()
+		if err := s.ruleRepo.Update(ctx, existing); err != nil {
+			return nil, err
+		}
+		s.logAudit(ctx, input, domain.AuditEventAlertRuleUpdated, domain.ResourceTypeAlertRule, domain.ActionUpdate, existing.ID, &oldRule, existing)
+		imported.ID = existing.ID
+		imported.Action = domain.BundleActionOverwritten
+		return imported, nil
+	default:
+		imported.Name, err = freeName(rule.Name, func(name string) (bool, error) {
+			_, err := s.ruleRepo.FindByName(ctx, input.TenantID, name)
+			if errors.Is(err, domain.ErrAlertRuleNotFound) {
+				return true, nil
+			}
+			return false, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		imported.Action = domain.BundleActionRenamed
+	}
+
+	created := &domain.AlertRule{
+		ID:        uuid.New(),
+		TenantID:  input.TenantID,
+		CreatedBy: input.UserID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	applyBundleAlertRule(created, rule, triggerWorkflowID)
+	created.Name = imported.Name
+	if err := s.ruleRepo.Save(ctx, created); err != nil {
+		return nil, err
+	}
+	s.logAudit(ctx, input, domain.AuditEventAlertRuleCreated, domain.ResourceTypeAlertRule, domain.ActionCreate, created.ID, nil, created)
+	imported.ID = created.ID
+	return imported, nil
+}
+
+func (s *BundleService) logAudit(ctx context.Context, input port.ImportBundleInput, eventType, resourceType, action string, resourceID uuid.UUID, oldValue, newValue interface{}) {
+	if s.auditService == nil {
+		return
+	}
+
+	log := domain.NewAuditLog(input.TenantID, input.UserID, eventType, resourceType, &resourceID, action).
+		WithOldValue(oldValue).
+		WithNewValue(newValue)
+
+	s.auditService.Log(ctx, log)
+}
+
+// applyBundleAlertRule copies the fields of a bundled alert rule to a rule
+func applyBundleAlertRule(target *domain.AlertRule, rule domain.BundleAlertRule, triggerWorkflowID *uuid.UUID) {
+	target.Name = rule.Name
+	target.Description = rule.Description
+	target.Enabled = rule.Enabled
+	target.ConditionType = rule.ConditionType
+	target.ConditionConfig = rule.ConditionConfig
+	target.Severity = rule.Severity
+	target.AlertTitleTemplate = rule.AlertTitleTemplate
+	target.AlertMessageTemplate = rule.AlertMessageTemplate
+	target.TriggerWorkflowID = triggerWorkflowID
+	target.TriggerWorkflowVersion = nil
+	target.TriggerInputTemplate = rule.TriggerInputTemplate
+	target.CooldownSeconds = rule.CooldownSeconds
+}
+
+// freeName returns the first of "name (2)", "name (3)", ... that isFree
+// accepts
+func freeName(name string, isFree func(name string) (bool, error)) (string, error) {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		free, err := isFree(candidate)
+		if err != nil || free {
+			return candidate, err
+		}
+	}
+}
+
+func toBundleWorkflow(wf *domain.Workflow) domain.BundleWorkflow {
+	return domain.BundleWorkflow{
+		ID:              wf.ID,
+		Name:            wf.Name,
+		Description:     wf.Description,
+		Definition:      wf.Definition,
+		Schedule:        wf.Schedule,
+		ScheduleOptions: wf.ScheduleOptions,
+		Status:          wf.Status,
+	}
+}
+
+func toBundleAlertRule(rule *domain.AlertRule) domain.BundleAlertRule {
+	return domain.BundleAlertRule{
+		ID:                   rule.ID,
+		Name:                 rule.Name,
+		Description:          rule.Description,
+		Enabled:              rule.Enabled,
+		ConditionType:        rule.ConditionType,
+		ConditionConfig:      rule.ConditionConfig,
+		Severity:             rule.Severity,
+		AlertTitleTemplate:   rule.AlertTitleTemplate,
+		AlertMessageTemplate: rule.AlertMessageTemplate,
+		TriggerWorkflowID:    rule.TriggerWorkflowID,
+		TriggerInputTemplate: rule.TriggerInputTemplate,
+		CooldownSeconds:      rule.CooldownSeconds,
+	}
+}
+
+func toBundleMetricDefinition(def *domain.MetricDefinition) domain.BundleMetricDefinition {
+	return domain.BundleMetricDefinition{
+		Name:           def.Name,
+		DisplayName:    def.DisplayName,
+		Description:    def.Description,
+		Unit:           def.Unit,
+		Type:           def.Type,
+		Aggregation:    def.Aggregation,
+		AlertThreshold: def.AlertThreshold,
+		RetentionDays:  def.RetentionDays,
+	}
+}
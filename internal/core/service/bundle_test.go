@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bundleTestEnv struct {
+	svc          *BundleService
+	workflowRepo *mocks.MockWorkflowRepository
+	ruleRepo     *mocks.MockAlertRuleRepository
+	defRepo      *mocks.MockMetricDefinitionRepository
+	validator    *mocks.MockDefinitionValidator
+}
+
+func newBundleTestEnv() *bundleTestEnv {
+	env := &bundleTestEnv{
+		workflowRepo: mocks.NewMockWorkflowRepository(),
+		ruleRepo:     mocks.NewMockAlertRuleRepository(),
+		defRepo:      mocks.NewMockMetricDefinitionRepository(),
+		validator:    mocks.NewMockDefinitionValidator(),
+	}
+	tenantSetter := mocks.NewMockTenantContextSetter()
+	auditService := mocks.NewMockAuditService()
+	workflowService := NewWorkflowService(env.workflowRepo, mocks.NewMockWorkflowVersionRepository(), mocks.NewMockExecutionRepository(), mocks.NewMockWorkflowExecutor(), nil, env.validator, auditService, tenantSetter)
+	metricService := NewMetricService(mocks.NewMockMetricRepository(), env.defRepo, mocks.NewMockAlertRuleService(), tenantSetter)
+	env.svc = NewBundleService(env.workflowRepo, env.ruleRepo, workflowService, metricService, auditService, tenantSetter)
+	return env
+}
+
+// addSourceWorkflows adds a workflow starting a child by name, which starts
+// a grandchild by ID and waits for a metric, and a rule triggering the first
+func (env *bundleTestEnv) addSourceWorkflows(tenantID uuid.UUID) (root, child, grandchild *domain.Workflow) {
+	grandchild = &domain.Workflow{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Name:       "page-oncall",
+		Definition: json.RawMessage(`{"steps":[{"id":"page","type":"notify","config":{"message":"down"}}]}`),
+		Status:     domain.WorkflowStatusActive,
+	}
+	child = &domain.Workflow{
+		ID:       uuid.New(),
+		TenantID: tenantID,
+		Name:     "restart-api",
+		Definition: json.RawMessage(`{"steps":[` +
+			`{"id":"wait","type":"wait_for_metric","config":{"metric":"error_rate"}},` +
+			`{"id":"page","type":"child_workflow","config":{"workflow_id":"` + grandchild.ID.String() + `"}}]}`),
+		Status: domain.WorkflowStatusActive,
+	}
+	root = &domain.Workflow{
+		ID:         uuid.New(),
+		TenantID:   tenantID,
+		Name:       "remediate",
+		Definition: json.RawMessage(`{"steps":[{"id":"restart","type":"child_workflow","config":{"workflow_name":"restart-api"}}]}`),
+		Status:     domain.WorkflowStatusActive,
+	}
+	for _, wf := range []*domain.Workflow{root, child, grandchild} {
+		env.workflowRepo.AddWorkflow(wf)
+	}
+
+	env.ruleRepo.AddRule(&domain.AlertRule{
+		ID:                 uuid.New(),
+		TenantID:           tenantID,
+		Name:               "high-cpu",
+		Enabled:            true,
+		ConditionType:      "threshold",
+		ConditionConfig:    json.RawMessage(`{"metric_name":"cpu_usage","operator":"gt","threshold":90}`),
+		Severity:           domain.AlertSeverityHigh,
+		AlertTitleTemplate: "CPU is high",
+		TriggerWorkflowID:  &root.ID,
+	})
+	env.ruleRepo.AddRule(&domain.AlertRule{
+		ID:                uuid.New(),
+		TenantID:          tenantID,
+		Name:              "unrelated",
+		ConditionConfig:   json.RawMessage(`{"metric_name":"disk_usage"}`),
+		TriggerWorkflowID: &grandchild.ID,
+	})
+	for _, name := range []string{"cpu_usage", "error_rate", "disk_usage"} {
+		env.defRepo.AddDefinition(&domain.MetricDefinition{
+			ID:            uuid.New(),
+			TenantID:      tenantID,
+			Name:          name,
+			Type:          domain.MetricTypeGauge,
+			Aggregation:   domain.AggregationAvg,
+			RetentionDays: 30,
+		})
+	}
+	return root, child, grandchild
+}
+
+func TestBundleService_Export(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	t.Run("bundles child workflows, alert rules and metric definitions", func(t *testing.T) {
+		env := newBundleTestEnv()
+		root, child, grandchild := env.addSourceWorkflows(tenantID)
+
+		bundle, err := env.svc.Export(ctx, tenantID, root.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BundleFormatVersion, bundle.FormatVersion)
+		assert.Equal(t, root.ID, bundle.WorkflowID)
+		require.Len(t, bundle.Workflows, 3)
+		assert.Equal(t, root.ID, bundle.Workflows[0].ID)
+		assert.Equal(t, child.ID, bundle.Workflows[1].ID)
+		assert.Equal(t, grandchild.ID, bundle.Workflows[2].ID)
+		require.Len(t, bundle.AlertRules, 1)
+		assert.Equal(t, "high-cpu", bundle.AlertRules[0].Name)
+		require.Len(t, bundle.MetricDefinitions, 2)
+		assert.Equal(t, "error_rate", bundle.MetricDefinitions[0].Name)
+		assert.Equal(t, "cpu_usage", bundle.MetricDefinitions[1].Name)
+	})
+
+	t.Run("fails when a child workflow is missing", func(t *testing.T) {
+		env := newBundleTestEnv()
+		root := &domain.Workflow{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			Name:       "remediate",
+			Definition: json.RawMessage(`{"steps":[{"id":"restart","type":"child_workflow","config":{"workflow_name":"missing"}}]}`),
+		}
+		env.workflowRepo.AddWorkflow(root)
+
+		_, err := env.svc.Export(ctx, tenantID, root.ID)
+
+		assert.ErrorIs(t, err, domain.ErrBundleDependencyNotFound)
+	})
+
+	t.Run("hides workflows of other tenants", func(t *testing.T) {
+		env := newBundleTestEnv()
+		root, _, _ := env.addSourceWorkflows(uuid.New())
+
+		_, err := env.svc.Export(ctx, tenantID, root.ID)
+
+		assert.ErrorIs(t, err, domain.ErrWorkflowNotFound)
+	})
+}
+
+func TestBundleService_Import(t *testing.T) {
+	ctx := context.Background()
+	sourceTenantID := uuid.New()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	exportBundle := func(t *testing.T, env *bundleTestEnv) *domain.WorkflowBundle {
+		root, _, _ := env.addSourceWorkflows(sourceTenantID)
+		bundle, err := env.svc.Export(ctx, sourceTenantID, root.ID)
+		require.NoError(t, err)
+		return bundle
+	}
+	actions := func(result *domain.BundleImport) map[string]domain.BundleAction {
+		actions := map[string]domain.BundleAction{}
+		for _, object := range result.Objects {
+			actions[string(object.Kind)+":"+object.Name] = object.Action
+		}
+		return actions
+	}
+
+	t.Run("creates the objects with remapped references", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+
+		result, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictSkip, UserID: &userID})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]domain.BundleAction{
+			"metric_definition:error_rate": domain.BundleActionCreated,
+			"metric_definition:cpu_usage":  domain.BundleActionCreated,
+			"workflow:remediate":           domain.BundleActionCreated,
+			"workflow:restart-api":         domain.BundleActionCreated,
+			"workflow:page-oncall":         domain.BundleActionCreated,
+			"alert_rule:high-cpu":          domain.BundleActionCreated,
+		}, actions(result))
+
+		root, err := env.workflowRepo.FindByID(ctx, result.WorkflowID)
+		require.NoError(t, err)
+		assert.Equal(t, tenantID, root.TenantID)
+		assert.Equal(t, domain.WorkflowStatusDraft, root.Status)
+
+		child, err := env.workflowRepo.FindByName(ctx, tenantID, "restart-api")
+		require.NoError(t, err)
+		grandchild, err := env.workflowRepo.FindByName(ctx, tenantID, "page-oncall")
+		require.NoError(t, err)
+		assert.Contains(t, string(child.Definition), grandchild.ID.String())
+
+		rule, err := env.ruleRepo.FindByName(ctx, tenantID, "high-cpu")
+		require.NoError(t, err)
+		assert.Equal(t, &result.WorkflowID, rule.TriggerWorkflowID)
+		assert.Equal(t, &userID, rule.CreatedBy)
+
+		_, err = env.defRepo.FindByName(ctx, tenantID, "cpu_usage")
+		assert.NoError(t, err)
+	})
+
+	t.Run("skips objects whose name is taken", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+		existing := &domain.Workflow{ID: uuid.New(), TenantID: tenantID, Name: "restart-api", Status: domain.WorkflowStatusDraft}
+		env.workflowRepo.AddWorkflow(existing)
+
+		result, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictSkip})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BundleActionSkipped, actions(result)["workflow:restart-api"])
+		assert.Empty(t, existing.Definition)
+		assert.Equal(t, domain.WorkflowStatusDraft, existing.Status)
+	})
+
+	t.Run("overwrites objects whose name is taken", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+		existing := &domain.Workflow{ID: uuid.New(), TenantID: tenantID, Name: "restart-api", Status: domain.WorkflowStatusInactive}
+		env.workflowRepo.AddWorkflow(existing)
+
+		result, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictOverwrite})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BundleActionOverwritten, actions(result)["workflow:restart-api"])
+		updated, err := env.workflowRepo.FindByID(ctx, existing.ID)
+		require.NoError(t, err)
+		assert.Contains(t, string(updated.Definition), "wait_for_metric")
+		assert.Equal(t, domain.WorkflowStatusInactive, updated.Status)
+	})
+
+	t.Run("keeps the bundled status when asked", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+
+		result, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictSkip, KeepStatus: true})
+
+		require.NoError(t, err)
+		root, err := env.workflowRepo.FindByID(ctx, result.WorkflowID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WorkflowStatusActive, root.Status)
+	})
+
+	t.Run("renames objects whose name is taken", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+		existing := &domain.Workflow{ID: uuid.New(), TenantID: tenantID, Name: "restart-api", Status: domain.WorkflowStatusDraft}
+		env.workflowRepo.AddWorkflow(existing)
+
+		result, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictRename})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.BundleActionRenamed, actions(result)["workflow:restart-api (2)"])
+		root, err := env.workflowRepo.FindByID(ctx, result.WorkflowID)
+		require.NoError(t, err)
+		assert.Contains(t, string(root.Definition), `"workflow_name":"restart-api (2)"`)
+		assert.Empty(t, existing.Definition)
+	})
+
+	t.Run("rejects invalid bundles before writing", func(t *testing.T) {
+		env := newBundleTestEnv()
+		bundle := exportBundle(t, env)
+		bundle.AlertRules[0].TriggerWorkflowID = &tenantID
+		bundle.MetricDefinitions[0].Type = "bogus"
+		env.validator.Errors = []domain.DefinitionError{{Path: "$.steps[0].id", Message: "is required"}}
+
+		_, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: bundle, Strategy: domain.ConflictSkip})
+
+		var bundleErr *domain.BundleValidationError
+		require.True(t, errors.As(err, &bundleErr))
+		assert.ErrorIs(t, err, domain.ErrInvalidBundle)
+		assert.Contains(t, bundleErr.Errors, domain.DefinitionError{Path: "$.workflows[0].definition.steps[0].id", Message: "is required"})
+		assert.Contains(t, bundleErr.Errors, domain.DefinitionError{Path: "$.alert_rules[0].trigger_workflow_id", Message: "must be the ID of a bundled workflow"})
+		assert.Contains(t, bundleErr.Errors, domain.DefinitionError{Path: "$.metric_definitions[0].type", Message: domain.ErrInvalidMetricType.Error()})
+		_, err = env.workflowRepo.FindByName(ctx, tenantID, "remediate")
+		assert.ErrorIs(t, err, domain.ErrWorkflowNotFound)
+	})
+
+	t.Run("rejects unknown conflict strategies", func(t *testing.T) {
+		env := newBundleTestEnv()
+
+		_, err := env.svc.Import(ctx, port.ImportBundleInput{TenantID: tenantID, Bundle: &domain.WorkflowBundle{}, Strategy: "merge"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidConflictStrategy)
+	})
+}
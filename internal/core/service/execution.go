@@ -2,6 +2,9 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
@@ -11,19 +14,31 @@ import (
 // ExecutionService implements port.ExecutionService
 type ExecutionService struct {
 	executionRepo port.ExecutionRepository
+	stepRepo      port.ExecutionStepRepository
+	workflowRepo  port.WorkflowRepository
+	versionRepo   port.WorkflowVersionRepository
 	executor      port.WorkflowExecutor
+	auditService  port.AuditService
 	tenantSetter  port.TenantContextSetter
 }
 
 // NewExecutionService creates a new execution service
 func NewExecutionService(
 	executionRepo port.ExecutionRepository,
+	stepRepo port.ExecutionStepRepository,
+	workflowRepo port.WorkflowRepository,
+	versionRepo port.WorkflowVersionRepository,
 	executor port.WorkflowExecutor,
+	auditService port.AuditService,
 	tenantSetter port.TenantContextSetter,
 ) *ExecutionService {
 	return &ExecutionService{
 		executionRepo: executionRepo,
+		stepRepo:      stepRepo,
+		workflowRepo:  workflowRepo,
+		versionRepo:   versionRepo,
 		executor:      executor,
+		auditService:  auditService,
 		tenantSetter:  tenantSetter,
 	}
 }
@@ -76,6 +91,36 @@ func (s *ExecutionService) GetByID(ctx context.Context, id uuid.UUID) (*domain.E
 	return s.executionRepo.FindByID(ctx, id)
 }
 
+// ListChildren returns the executions started by child_workflow steps of an execution
+func (s *ExecutionService) ListChildren(ctx context.Context, id uuid.UUID) ([]*domain.Execution, error) {
+	if _, err := s.executionRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.executionRepo.FindByParent(ctx, id)
+}
+
+// ListSteps returns the step timeline of an execution. Running executions
+// are queried live so the step in progress shows up too; if the query fails,
+// e.g. because no worker is polling, the recorded steps are returned instead.
+func (s *ExecutionService) ListSteps(ctx context.Context, id uuid.UUID) ([]*domain.ExecutionStep, error) {
+	execution, err := s.executionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if execution.Status == domain.ExecutionStatusRunning && execution.TemporalWorkflowID != nil {
+		if steps, err := s.executor.ListSteps(ctx, *execution.TemporalWorkflowID); err == nil {
+			for _, step := range steps {
+				step.TenantID = execution.TenantID
+				step.ExecutionID = execution.ID
+			}
+			return steps, nil
+		}
+	}
+
+	return s.stepRepo.FindByExecution(ctx, id)
+}
+
 // Cancel cancels a running execution
 func (s *ExecutionService) Cancel(ctx context.Context, id uuid.UUID) error {
 	execution, err := s.executionRepo.FindByID(ctx, id)
@@ -97,3 +142,281 @@ func (s *ExecutionService) Cancel(ctx context.Context, id uuid.UUID) error {
 	execution.MarkAsCancelled()
 	return s.executionRepo.Update(ctx, execution)
 }
+
+// Retry starts a new execution of a failed or cancelled execution, linked to
+// it. The retry runs the same workflow version with the same input. Unless it
+// starts from scratch, the steps before the failed step aren't run again:
+// their recorded results are copied to the new execution and seed the steps
+// that follow.
+func (s *ExecutionService) Retry(ctx context.Context, input port.RetryExecutionInput) (*domain.Execution, error) {
+	if !input.Mode.IsValid() {
+		return nil, domain.ErrInvalidRetryMode
+	}
+
+	original, err := s.executionRepo.FindByID(ctx, input.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	if !original.CanRetry() {
+		return nil, domain.ErrExecutionCannotRetry
+	}
+
+	workflow, err := s.workflowRepo.FindByID(ctx, original.WorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	if !workflow.CanExecute() {
+		return nil, domain.ErrWorkflowCannotExecute
+	}
+
+	// Step positions only line up with the definition the original ran
+	if original.WorkflowVersion != nil && *original.WorkflowVersion != workflow.Version {
+		v, err := s.versionRepo.FindByVersion(ctx, workflow.ID, *original.WorkflowVersion)
+		if err != nil {
+			return nil, err
+		}
+		pinned := *workflow
+		pinned.Definition = v.Definition
+		pinned.Version = v.Version
+		workflow = &pinned
+	}
+
+	var resume *domain.ExecutionResume
+	if input.Mode != domain.RetryModeFromScratch {
+		steps, err := s.stepRepo.FindByExecution(ctx, original.ID)
+		if err != nil {
+			return nil, err
+		}
+		if resume, err = domain.PlanRetry(input.Mode, steps); err != nil {
+			return nil, err
+		}
+	}
+
+	var workflowInput map[string]interface{}
+	if len(original.Input) > 0 {
+		if err := json.Unmarshal(original.Input, &workflowInput); err != nil {
+			return nil, fmt.Errorf("failed to decode execution input: %w", err)
+		}
+	}
+
+	execution := &domain.Execution{
+		ID:              uuid.New(),
+		TenantID:        original.TenantID,
+		WorkflowID:      original.WorkflowID,
+		Status:          domain.ExecutionStatusPending,
+		Input:           original.Input,
+		CreatedBy:       &input.UserID,
+		CreatedAt:       time.Now(),
+		TriggeredBy:     stringPtr("user:" + input.UserID.String()),
+		WorkflowVersion: &workflow.Version,
+		RetryOf:         &original.ID,
+	}
+
+	if err := s.executionRepo.Save(ctx, execution); err != nil {
+		return nil, fmt.Errorf("failed to save execution: %w", err)
+	}
+
+	// The reused steps are part of the new execution's timeline, so it can be
+	// retried in turn
+	if resume != nil {
+		for _, step := range resume.Steps {
+			reused := *step
+			reused.ID = uuid.Nil
+			reused.ExecutionID = execution.ID
+			if err := s.stepRepo.Save(ctx, &reused); err != nil {
+				return nil, fmt.Errorf("failed to save execution step: %w", err)
+			}
+		}
+	}
+
+	result, err := s.executor.Execute(ctx, workflow, execution.ID, workflowInput, resume)
+	if err != nil {
+		execution.MarkAsFailed(err.Error())
+		s.executionRepo.Update(ctx, execution)
+		return nil, fmt.Errorf("failed to start workflow: %w", err)
+	}
+
+	execution, err = s.executionRepo.MarkRunning(ctx, execution.ID, result.TemporalWorkflowID, result.TemporalRunID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update execution: %w", err)
+	}
+
+	s.logAudit(ctx, execution.TenantID, &input.UserID, domain.AuditEventExecutionRetried, execution.ID, original, execution)
+
+	return execution, nil
+}
+
+// ListApprovals returns the approvals an execution is currently waiting on
+func (s *ExecutionService) ListApprovals(ctx context.Context, id uuid.UUID) ([]*domain.Approval, error) {
+	execution, err := s.executionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if execution.Status != domain.ExecutionStatusRunning || execution.TemporalWorkflowID == nil {
+		return []*domain.Approval{}, nil
+	}
+
+	approvals, err := s.executor.ListApprovals(ctx, *execution.TemporalWorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	for _, approval := range approvals {
+		approval.ExecutionID = execution.ID
+	}
+	return approvals, nil
+}
+
+// DecideApproval approves or rejects a pending approval and records who decided
+func (s *ExecutionService) DecideApproval(ctx context.Context, input port.DecideApprovalInput) (*domain.ApprovalDecision, error) {
+	if !input.Action.IsValid() {
+		return nil, domain.ErrInvalidApprovalAction
+	}
+
+	execution, err := s.executionRepo.FindByID(ctx, input.ExecutionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if execution.Status != domain.ExecutionStatusRunning || execution.TemporalWorkflowID == nil {
+		return nil, domain.ErrExecutionNotRunning
+	}
+
+	pending, err := s.executor.ListApprovals(ctx, *execution.TemporalWorkflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	var approval *domain.Approval
+	for _, p := range pending {
+		if p.ID == input.ApprovalID {
+			approval = p
+			approval.ExecutionID = execution.ID
+			break
+		}
+	}
+	if approval == nil {
+		return nil, domain.ErrApprovalNotFound
+	}
+
+	decision := &domain.ApprovalDecision{
+		ApprovalID: approval.ID,
+		Action:     input.Action,
+		UserID:     input.UserID,
+		User:       input.User,
+		Comment:    input.Comment,
+		DecidedAt:  time.Now(),
+	}
+
+	if err := s.executor.SubmitApproval(ctx, *execution.TemporalWorkflowID, *decision); err != nil {
+		return nil, err
+	}
+
+	// Log audit
+	eventType := domain.AuditEventExecutionApproved
+	if decision.Action == domain.ApprovalActionReject {
+		eventType = domain.AuditEventExecutionRejected
+	}
+	s.logAudit(ctx, execution.TenantID, &input.UserID, eventType, execution.ID, approval, decision)
+
+	return decision, nil
+}
+
+// Pause asks a running execution to wait before starting its next step. The
+// step it is running finishes first.
+func (s *ExecutionService) Pause(ctx context.Context, input port.PauseExecutionInput) error {
+	execution, progress, err := s.liveProgress(ctx, input.ExecutionID)
+	if err != nil {
+		return err
+	}
+	if progress.Paused {
+		return domain.ErrExecutionAlreadyPaused
+	}
+
+	pause := domain.ExecutionPause{UserID: input.UserID, User: input.User, At: time.Now()}
+	if err := s.executor.Pause(ctx, *execution.TemporalWorkflowID, pause); err != nil {
+		return err
+	}
+
+	s.logAudit(ctx, execution.TenantID, &input.UserID, domain.AuditEventExecutionPaused, execution.ID, nil, pause)
+	return nil
+}
+
+// Resume lets a paused execution start its next step
+func (s *ExecutionService) Resume(ctx context.Context, input port.PauseExecutionInput) error {
+	execution, progress, err := s.liveProgress(ctx, input.ExecutionID)
+	if err != nil {
+		return err
+	}
+	if !progress.Paused {
+		return domain.ErrExecutionNotPaused
+	}
+
+	resume := domain.ExecutionPause{UserID: input.UserID, User: input.User, At: time.Now()}
+	if err := s.executor.Resume(ctx, *execution.TemporalWorkflowID, resume); err != nil {
+		return err
+	}
+
+	s.logAudit(ctx, execution.TenantID, &input.UserID, domain.AuditEventExecutionResumed, execution.ID, nil, resume)
+	return nil
+}
+
+// GetProgress returns the step a running execution is on, the steps it has
+// finished and their outputs
+func (s *ExecutionService) GetProgress(ctx context.Context, id uuid.UUID) (*domain.ExecutionProgress, error) {
+	_, progress, err := s.liveProgress(ctx, id)
+	return progress, err
+}
+
+// liveProgress queries the progress of a running execution
+func (s *ExecutionService) liveProgress(ctx context.Context, id uuid.UUID) (*domain.Execution, *domain.ExecutionProgress, error) {
+	execution, err := s.executionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if execution.Status != domain.ExecutionStatusRunning || execution.TemporalWorkflowID == nil {
+		return nil, nil, domain.ErrExecutionNotRunning
+	}
+
+	progress, err := s.executor.GetProgress(ctx, *execution.TemporalWorkflowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	progress.ExecutionID = execution.ID
+	steps := progress.CompletedSteps
+	if progress.CurrentStep != nil {
+		steps = append(steps, progress.CurrentStep)
+	}
+	for _, step := range steps {
+		step.TenantID = execution.TenantID
+		step.ExecutionID = execution.ID
+	}
+	return execution, progress, nil
+}
+
+func (s *ExecutionService) logAudit(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, eventType string, resourceID uuid.UUID, oldValue, newValue interface{}) {
+	if s.auditService == nil {
+		return
+	}
+
+	action := domain.ActionUpdate
+	switch eventType {
+	case domain.AuditEventExecutionApproved:
+		action = domain.ActionApprove
+	case domain.AuditEventExecutionRejected:
+		action = domain.ActionReject
+	case domain.AuditEventExecutionRetried:
+		action = domain.ActionExecute
+	case domain.AuditEventExecutionPaused:
+		action = domain.ActionPause
+	case domain.AuditEventExecutionResumed:
+		action = domain.ActionResume
+	}
+
+	log := domain.NewAuditLog(tenantID, userID, eventType, domain.ResourceTypeExecution, &resourceID, action).
+		WithOldValue(oldValue).
+		WithNewValue(newValue)
+
+	s.auditService.Log(ctx, log)
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionService_DecideApproval(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	newRunningExecution := func(repo *mocks.MockExecutionRepository) *domain.Execution {
+		temporalID := "execution-123"
+		execution := &domain.Execution{
+			ID:                 uuid.New(),
+			TenantID:           tenantID,
+			Status:             domain.ExecutionStatusRunning,
+			TemporalWorkflowID: &temporalID,
+		}
+		_ = repo.Save(ctx, execution)
+		return execution
+	}
+
+	t.Run("signals the decision and records it in the audit log", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		auditService := mocks.NewMockAuditService()
+		execution := newRunningExecution(executionRepo)
+		executor.Approvals = []*domain.Approval{{ID: "approve_failover", StepID: "approve_failover"}}
+
+		svc := NewExecutionService(executionRepo, mocks.NewMockExecutionStepRepository(), mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, auditService, mocks.NewMockTenantContextSetter())
+
+		decision, err := svc.DecideApproval(ctx, port.DecideApprovalInput{
+			ExecutionID: execution.ID,
+			ApprovalID:  "approve_failover",
+			Action:      domain.ApprovalActionReject,
+			UserID:      userID,
+			User:        "oncall@example.com",
+			Comment:     "replica is lagging",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ApprovalActionReject, decision.Action)
+		require.Len(t, executor.Decisions, 1)
+		assert.Equal(t, "replica is lagging", executor.Decisions[0].Comment)
+		require.Len(t, auditService.Logs, 1)
+		assert.Equal(t, domain.AuditEventExecutionRejected, auditService.Logs[0].EventType)
+		assert.Equal(t, &userID, auditService.Logs[0].UserID)
+		assert.Equal(t, &execution.ID, auditService.Logs[0].ResourceID)
+	})
+
+	t.Run("returns error for unknown approval", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		auditService := mocks.NewMockAuditService()
+		execution := newRunningExecution(executionRepo)
+
+		svc := NewExecutionService(executionRepo, mocks.NewMockExecutionStepRepository(), mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, auditService, mocks.NewMockTenantContextSetter())
+
+		_, err := svc.DecideApproval(ctx, port.DecideApprovalInput{
+			ExecutionID: execution.ID,
+			ApprovalID:  "missing",
+			Action:      domain.ApprovalActionApprove,
+			UserID:      userID,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrApprovalNotFound)
+		assert.Empty(t, executor.Decisions)
+		assert.False(t, auditService.LogCalled)
+	})
+
+	t.Run("returns error when execution is not running", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		execution := newRunningExecution(executionRepo)
+		execution.MarkAsCompleted(nil)
+
+		svc := NewExecutionService(executionRepo, mocks.NewMockExecutionStepRepository(), mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+		_, err := svc.DecideApproval(ctx, port.DecideApprovalInput{
+			ExecutionID: execution.ID,
+			ApprovalID:  "approve_failover",
+			Action:      domain.ApprovalActionApprove,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrExecutionNotRunning)
+	})
+}
+
+func TestExecutionService_ListSteps(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	newExecution := func(repo *mocks.MockExecutionRepository, status domain.ExecutionStatus) *domain.Execution {
+		temporalID := "execution-123"
+		execution := &domain.Execution{
+			ID:                 uuid.New(),
+			TenantID:           tenantID,
+			Status:             status,
+			TemporalWorkflowID: &temporalID,
+		}
+		_ = repo.Save(ctx, execution)
+		return execution
+	}
+
+	recordStep := func(repo *mocks.MockExecutionStepRepository, executionID uuid.UUID, position int32, stepID string) {
+		_ = repo.Save(ctx, &domain.ExecutionStep{
+			TenantID:    tenantID,
+			ExecutionID: executionID,
+			Position:    position,
+			StepID:      stepID,
+			Status:      domain.ExecutionStepStatusCompleted,
+			Attempts:    1,
+		})
+	}
+
+	t.Run("returns recorded steps of finished executions", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		stepRepo := mocks.NewMockExecutionStepRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		execution := newExecution(executionRepo, domain.ExecutionStatusCompleted)
+		recordStep(stepRepo, execution.ID, 1, "notify")
+		recordStep(stepRepo, execution.ID, 0, "check")
+		recordStep(stepRepo, uuid.New(), 0, "other")
+		executor.Steps = []*domain.ExecutionStep{{StepID: "live"}}
+
+		svc := NewExecutionService(executionRepo, stepRepo, mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+		steps, err := svc.ListSteps(ctx, execution.ID)
+
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.Equal(t, "check", steps[0].StepID)
+		assert.Equal(t, "notify", steps[1].StepID)
+	})
+
+	t.Run("queries running executions for live steps", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		stepRepo := mocks.NewMockExecutionStepRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		execution := newExecution(executionRepo, domain.ExecutionStatusRunning)
+		recordStep(stepRepo, execution.ID, 0, "check")
+		executor.Steps = []*domain.ExecutionStep{
+			{Position: 0, StepID: "check", Status: domain.ExecutionStepStatusCompleted, Attempts: 1},
+			{Position: 1, StepID: "deploy", Status: domain.ExecutionStepStatusRunning},
+		}
+
+		svc := NewExecutionService(executionRepo, stepRepo, mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+		steps, err := svc.ListSteps(ctx, execution.ID)
+
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.Equal(t, domain.ExecutionStepStatusRunning, steps[1].Status)
+		assert.Equal(t, execution.ID, steps[1].ExecutionID)
+		assert.Equal(t, tenantID, steps[1].TenantID)
+	})
+
+	t.Run("falls back to recorded steps when the query fails", func(t *testing.T) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		stepRepo := mocks.NewMockExecutionStepRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		execution := newExecution(executionRepo, domain.ExecutionStatusRunning)
+		recordStep(stepRepo, execution.ID, 0, "check")
+		executor.ListStepsErr = errors.New("no poller")
+
+		svc := NewExecutionService(executionRepo, stepRepo, mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+		steps, err := svc.ListSteps(ctx, execution.ID)
+
+		require.NoError(t, err)
+		require.Len(t, steps, 1)
+		assert.Equal(t, "check", steps[0].StepID)
+	})
+}
+
+func TestExecutionService_Retry(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	type fixture struct {
+		executionRepo *mocks.MockExecutionRepository
+		stepRepo      *mocks.MockExecutionStepRepository
+		workflowRepo  *mocks.MockWorkflowRepository
+		versionRepo   *mocks.MockWorkflowVersionRepository
+		executor      *mocks.MockWorkflowExecutor
+		auditService  *mocks.MockAuditService
+		svc           *ExecutionService
+		workflow      *domain.Workflow
+		original      *domain.Execution
+	}
+
+	setup := func(status domain.ExecutionStatus) *fixture {
+		f := &fixture{
+			executionRepo: mocks.NewMockExecutionRepository(),
+			stepRepo:      mocks.NewMockExecutionStepRepository(),
+			workflowRepo:  mocks.NewMockWorkflowRepository(),
+			versionRepo:   mocks.NewMockWorkflowVersionRepository(),
+			executor:      mocks.NewMockWorkflowExecutor(),
+			auditService:  mocks.NewMockAuditService(),
+		}
+		f.svc = NewExecutionService(f.executionRepo, f.stepRepo, f.workflowRepo, f.versionRepo, f.executor, f.auditService, mocks.NewMockTenantContextSetter())
+
+		f.workflow = &domain.Workflow{
+			ID:         uuid.New(),
+			TenantID:   tenantID,
+			Status:     domain.WorkflowStatusActive,
+			Version:    1,
+			Definition: []byte(`{"steps": [{"id": "notify", "type": "log"}, {"id": "scale_up", "type": "http"}, {"id": "deploy", "type": "http"}]}`),
+		}
+		f.workflowRepo.AddWorkflow(f.workflow)
+
+		errMsg := "step deploy failed: 503"
+		version := f.workflow.Version
+		f.original = &domain.Execution{
+			ID:              uuid.New(),
+			TenantID:        tenantID,
+			WorkflowID:      f.workflow.ID,
+			Status:          status,
+			Input:           []byte(`{"replicas": 3}`),
+			Error:           &errMsg,
+			WorkflowVersion: &version,
+		}
+		_ = f.executionRepo.Save(ctx, f.original)
+
+		for i, id := range []string{"notify", "scale_up", "deploy"} {
+			step := &domain.ExecutionStep{
+				TenantID:    tenantID,
+				ExecutionID: f.original.ID,
+				Position:    int32(i),
+				StepID:      id,
+				Status:      domain.ExecutionStepStatusCompleted,
+				Attempts:    1,
+				Output:      []byte(`{"ok": true}`),
+			}
+			if id == "deploy" {
+				step.Status = domain.ExecutionStepStatusFailed
+				step.Attempts = 3
+				step.Output = nil
+				step.Error = &errMsg
+			}
+			_ = f.stepRepo.Save(ctx, step)
+		}
+		return f
+	}
+
+	t.Run("resumes from the failed step", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusFailed)
+
+		execution, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryModeFromFailedStep,
+			UserID:      userID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ExecutionStatusRunning, execution.Status)
+		assert.Equal(t, &f.original.ID, execution.RetryOf)
+		assert.Equal(t, f.original.Input, execution.Input)
+		require.NotNil(t, f.executor.ExecuteResume)
+		assert.Equal(t, int32(2), f.executor.ExecuteResume.StartAt)
+		assert.Len(t, f.executor.ExecuteResume.Steps, 2)
+
+		// The reused steps start the new execution's timeline
+		steps, err := f.stepRepo.FindByExecution(ctx, execution.ID)
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.Equal(t, "notify", steps[0].StepID)
+		assert.Equal(t, "scale_up", steps[1].StepID)
+
+		require.Len(t, f.auditService.Logs, 1)
+		assert.Equal(t, domain.AuditEventExecutionRetried, f.auditService.Logs[0].EventType)
+	})
+
+	t.Run("skips the failed step", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusFailed)
+
+		execution, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryModeSkipFailedStep,
+			UserID:      userID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), f.executor.ExecuteResume.StartAt)
+		steps, err := f.stepRepo.FindByExecution(ctx, execution.ID)
+		require.NoError(t, err)
+		require.Len(t, steps, 3)
+		assert.Equal(t, domain.ExecutionStepStatusSkipped, steps[2].Status)
+	})
+
+	t.Run("starts from scratch", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusCancelled)
+
+		execution, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryModeFromScratch,
+			UserID:      userID,
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, f.executor.ExecuteResume)
+		steps, err := f.stepRepo.FindByExecution(ctx, execution.ID)
+		require.NoError(t, err)
+		assert.Empty(t, steps)
+	})
+
+	t.Run("runs the version the original ran", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusFailed)
+		f.versionRepo.AddVersion(&domain.WorkflowVersion{WorkflowID: f.workflow.ID, Version: 1, Definition: f.workflow.Definition})
+		f.workflow.Version = 2
+		f.workflow.Definition = []byte(`{"steps": [{"id": "deploy", "type": "http"}]}`)
+
+		_, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryModeFromFailedStep,
+			UserID:      userID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), f.executor.Workflow.Version)
+		assert.Contains(t, string(f.executor.Workflow.Definition), "scale_up")
+	})
+
+	t.Run("rejects executions that did not fail", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusRunning)
+
+		_, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryModeFromScratch,
+			UserID:      userID,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrExecutionCannotRetry)
+		assert.False(t, f.executor.ExecuteCalled)
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		f := setup(domain.ExecutionStatusFailed)
+
+		_, err := f.svc.Retry(ctx, port.RetryExecutionInput{
+			ExecutionID: f.original.ID,
+			Mode:        domain.RetryMode("again"),
+			UserID:      userID,
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRetryMode)
+	})
+}
+
+func TestExecutionService_PauseAndResume(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	newService := func(status domain.ExecutionStatus) (*ExecutionService, *domain.Execution, *mocks.MockWorkflowExecutor, *mocks.MockAuditService) {
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		auditService := mocks.NewMockAuditService()
+		temporalID := "execution-123"
+		execution := &domain.Execution{
+			ID:                 uuid.New(),
+			TenantID:           tenantID,
+			Status:             status,
+			TemporalWorkflowID: &temporalID,
+		}
+		_ = executionRepo.Save(ctx, execution)
+		svc := NewExecutionService(executionRepo, mocks.NewMockExecutionStepRepository(), mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, auditService, mocks.NewMockTenantContextSetter())
+		return svc, execution, executor, auditService
+	}
+
+	t.Run("pauses a running execution and records it in the audit log", func(t *testing.T) {
+		svc, execution, executor, auditService := newService(domain.ExecutionStatusRunning)
+
+		err := svc.Pause(ctx, port.PauseExecutionInput{ExecutionID: execution.ID, UserID: userID, User: "oncall@example.com"})
+
+		require.NoError(t, err)
+		require.Len(t, executor.Pauses, 1)
+		assert.Equal(t, "oncall@example.com", executor.Pauses[0].User)
+		require.Len(t, auditService.Logs, 1)
+		assert.Equal(t, domain.AuditEventExecutionPaused, auditService.Logs[0].EventType)
+		assert.Equal(t, domain.ActionPause, auditService.Logs[0].Action)
+	})
+
+	t.Run("rejects pausing a paused execution", func(t *testing.T) {
+		svc, execution, executor, auditService := newService(domain.ExecutionStatusRunning)
+		executor.Progress = &domain.ExecutionProgress{Paused: true}
+
+		err := svc.Pause(ctx, port.PauseExecutionInput{ExecutionID: execution.ID, UserID: userID})
+
+		assert.ErrorIs(t, err, domain.ErrExecutionAlreadyPaused)
+		assert.Empty(t, executor.Pauses)
+		assert.False(t, auditService.LogCalled)
+	})
+
+	t.Run("resumes a paused execution", func(t *testing.T) {
+		svc, execution, executor, auditService := newService(domain.ExecutionStatusRunning)
+		executor.Progress = &domain.ExecutionProgress{Paused: true}
+
+		err := svc.Resume(ctx, port.PauseExecutionInput{ExecutionID: execution.ID, UserID: userID})
+
+		require.NoError(t, err)
+		assert.Len(t, executor.Resumes, 1)
+		require.Len(t, auditService.Logs, 1)
+		assert.Equal(t, domain.AuditEventExecutionResumed, auditService.Logs[0].EventType)
+	})
+
+	t.Run("rejects resuming an execution that isn't paused", func(t *testing.T) {
+		svc, execution, executor, _ := newService(domain.ExecutionStatusRunning)
+
+		err := svc.Resume(ctx, port.PauseExecutionInput{ExecutionID: execution.ID, UserID: userID})
+
+		assert.ErrorIs(t, err, domain.ErrExecutionNotPaused)
+		assert.Empty(t, executor.Resumes)
+	})
+
+	t.Run("rejects executions that aren't running", func(t *testing.T) {
+		svc, execution, executor, _ := newService(domain.ExecutionStatusCompleted)
+
+		err := svc.Pause(ctx, port.PauseExecutionInput{ExecutionID: execution.ID, UserID: userID})
+
+		assert.ErrorIs(t, err, domain.ErrExecutionNotRunning)
+		assert.Empty(t, executor.Pauses)
+	})
+}
+
+func TestExecutionService_GetProgress(t *testing.T) {
+	ctx := context.Background()
+	executionRepo := mocks.NewMockExecutionRepository()
+	executor := mocks.NewMockWorkflowExecutor()
+	temporalID := "execution-123"
+	execution := &domain.Execution{
+		ID:                 uuid.New(),
+		TenantID:           uuid.New(),
+		Status:             domain.ExecutionStatusRunning,
+		TemporalWorkflowID: &temporalID,
+	}
+	_ = executionRepo.Save(ctx, execution)
+	executor.Progress = &domain.ExecutionProgress{
+		CurrentStep:    &domain.ExecutionStep{StepID: "restart", Status: domain.ExecutionStepStatusRunning},
+		CompletedSteps: []*domain.ExecutionStep{{StepID: "drain", Status: domain.ExecutionStepStatusCompleted}},
+		TotalSteps:     3,
+	}
+	svc := NewExecutionService(executionRepo, mocks.NewMockExecutionStepRepository(), mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(), executor, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+	progress, err := svc.GetProgress(ctx, execution.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, execution.ID, progress.ExecutionID)
+	assert.Equal(t, execution.ID, progress.CurrentStep.ExecutionID)
+	assert.Equal(t, execution.TenantID, progress.CompletedSteps[0].TenantID)
+	assert.Equal(t, 3, progress.TotalSteps)
+}
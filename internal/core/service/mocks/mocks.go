@@ -2,7 +2,10 @@ package mocks
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
@@ -45,6 +48,20 @@ func (m *MockWorkflowRepository) FindByID(ctx context.Context, id uuid.UUID) (*d
 	return nil, domain.ErrNotFound
 }
 
+func (m *MockWorkflowRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Workflow, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, w := range m.workflows {
+		if w.TenantID == tenantID && w.Name == name {
+			return w, nil
+		}
+	}
+	return nil, domain.ErrWorkflowNotFound
+}
+
 func (m *MockWorkflowRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Workflow, error) {
 	if m.FindErr != nil {
 		return nil, m.FindErr
@@ -123,6 +140,169 @@ func (m *MockWorkflowRepository) AddWorkflow(w *domain.Workflow) {
 	m.workflows[w.ID] = w
 }
 
+// ============================================================================
+// MOCK WORKFLOW VERSION REPOSITORY
+// ============================================================================
+
+type MockWorkflowVersionRepository struct {
+	mu       sync.RWMutex
+	versions []*domain.WorkflowVersion
+
+	SaveCalled bool
+	SaveErr    error
+	FindErr    error
+}
+
+func NewMockWorkflowVersionRepository() *MockWorkflowVersionRepository {
+	return &MockWorkflowVersionRepository{}
+}
+
+func (m *MockWorkflowVersionRepository) Save(ctx context.Context, version *domain.WorkflowVersion) error {
+	m.SaveCalled = true
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Like the unique (workflow_id, version) constraint
+	for _, v := range m.versions {
+		if v.WorkflowID == version.WorkflowID && v.Version == version.Version {
+			return errors.New("duplicate workflow version")
+		}
+	}
+	m.versions = append(m.versions, version)
+	return nil
+}
+
+func (m *MockWorkflowVersionRepository) FindByVersion(ctx context.Context, workflowID uuid.UUID, version int32) (*domain.WorkflowVersion, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.versions {
+		if v.WorkflowID == workflowID && v.Version == version {
+			return v, nil
+		}
+	}
+	return nil, domain.ErrWorkflowVersionNotFound
+}
+
+func (m *MockWorkflowVersionRepository) LatestVersion(ctx context.Context, workflowID uuid.UUID) (int32, error) {
+	if m.FindErr != nil {
+		return 0, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var latest int32
+	for _, v := range m.versions {
+		if v.WorkflowID == workflowID && v.Version > latest {
+			latest = v.Version
+		}
+	}
+	return latest, nil
+}
+
+func (m *MockWorkflowVersionRepository) FindByWorkflow(ctx context.Context, workflowID uuid.UUID, limit, offset int) ([]*domain.WorkflowVersion, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.WorkflowVersion
+	for i := len(m.versions) - 1; i >= 0; i-- {
+		if m.versions[i].WorkflowID == workflowID {
+			result = append(result, m.versions[i])
+		}
+	}
+	if offset >= len(result) {
+		return []*domain.WorkflowVersion{}, nil
+	}
+	end := offset + limit
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[offset:end], nil
+}
+
+func (m *MockWorkflowVersionRepository) CountByWorkflow(ctx context.Context, workflowID uuid.UUID) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var count int64
+	for _, v := range m.versions {
+		if v.WorkflowID == workflowID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// AddVersion adds a version to the mock repository (for test setup)
+func (m *MockWorkflowVersionRepository) AddVersion(v *domain.WorkflowVersion) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.versions = append(m.versions, v)
+}
+
+// Versions returns the saved versions in the order they were saved
+func (m *MockWorkflowVersionRepository) Versions() []*domain.WorkflowVersion {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*domain.WorkflowVersion(nil), m.versions...)
+}
+
+// ============================================================================
+// MOCK EXECUTION STEP REPOSITORY
+// ============================================================================
+
+type MockExecutionStepRepository struct {
+	mu    sync.RWMutex
+	steps []*domain.ExecutionStep
+
+	SaveErr error
+	FindErr error
+}
+
+func NewMockExecutionStepRepository() *MockExecutionStepRepository {
+	return &MockExecutionStepRepository{}
+}
+
+func (m *MockExecutionStepRepository) Save(ctx context.Context, step *domain.ExecutionStep) error {
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, s := range m.steps {
+		if s.ExecutionID == step.ExecutionID && s.Position == step.Position {
+			step.ID = s.ID
+			m.steps[i] = step
+			return nil
+		}
+	}
+	if step.ID == uuid.Nil {
+		step.ID = uuid.New()
+	}
+	m.steps = append(m.steps, step)
+	return nil
+}
+
+func (m *MockExecutionStepRepository) FindByExecution(ctx context.Context, executionID uuid.UUID) ([]*domain.ExecutionStep, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := []*domain.ExecutionStep{}
+	for _, s := range m.steps {
+		if s.ExecutionID == executionID {
+			result = append(result, s)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Position < result[j].Position })
+	return result, nil
+}
+
 // ============================================================================
 // MOCK EXECUTION REPOSITORY
 // ============================================================================
@@ -153,7 +333,7 @@ func (m *MockExecutionRepository) FindByID(ctx context.Context, id uuid.UUID) (*
 	if e, ok := m.executions[id]; ok {
 		return e, nil
 	}
-	return nil, domain.ErrNotFound
+	return nil, domain.ErrExecutionNotFound
 }
 
 func (m *MockExecutionRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.Execution, error) {
@@ -186,6 +366,21 @@ func (m *MockExecutionRepository) FindByWorkflow(ctx context.Context, workflowID
 	return result, nil
 }
 
+func (m *MockExecutionRepository) FindByParent(ctx context.Context, parentID uuid.UUID) ([]*domain.Execution, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*domain.Execution
+	for _, e := range m.executions {
+		if e.ParentExecutionID != nil && *e.ParentExecutionID == parentID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
 func (m *MockExecutionRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
 	if m.FindErr != nil {
 		return 0, m.FindErr
@@ -208,6 +403,10 @@ func (m *MockExecutionRepository) Save(ctx context.Context, execution *domain.Ex
 	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	// Like the executions primary key, an ID can only be saved once
+	if _, ok := m.executions[execution.ID]; ok {
+		return errors.New("duplicate execution")
+	}
 	m.executions[execution.ID] = execution
 	return nil
 }
@@ -251,6 +450,29 @@ func (m *MockExecutionRepository) UpdateTemporalIDs(ctx context.Context, id uuid
 	return nil
 }
 
+func (m *MockExecutionRepository) MarkRunning(ctx context.Context, id uuid.UUID, temporalWorkflowID, temporalRunID string) (*domain.Execution, error) {
+	m.UpdateCalled = true
+	if m.UpdateErr != nil {
+		return nil, m.UpdateErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.executions[id]
+	if !ok {
+		return nil, domain.ErrExecutionNotFound
+	}
+	e.TemporalWorkflowID = &temporalWorkflowID
+	e.TemporalRunID = &temporalRunID
+	if e.Status == domain.ExecutionStatusPending {
+		e.Status = domain.ExecutionStatusRunning
+	}
+	if e.StartedAt == nil {
+		now := time.Now()
+		e.StartedAt = &now
+	}
+	return e, nil
+}
+
 // ============================================================================
 // MOCK WORKFLOW EXECUTOR
 // ============================================================================
@@ -261,6 +483,28 @@ type MockWorkflowExecutor struct {
 	ExecuteErr    error
 	CancelErr     error
 	ExecuteResult *port.ExecuteResult
+	Workflow      *domain.Workflow        // workflow passed to the last Execute call
+	ExecuteResume *domain.ExecutionResume // resume passed to the last Execute call
+	OnExecute     func(executionID uuid.UUID)
+
+	Approvals       []*domain.Approval
+	Decisions       []domain.ApprovalDecision
+	ListApprovalErr error
+	SubmitErr       error
+
+	Steps        []*domain.ExecutionStep // live steps returned by ListSteps
+	ListStepsErr error
+
+	Progress       *domain.ExecutionProgress // live progress returned by GetProgress
+	GetProgressErr error
+	Pauses         []domain.ExecutionPause
+	Resumes        []domain.ExecutionPause
+	PauseErr       error
+
+	DryRunResult *domain.DryRun
+	DryRunErr    error
+	DryRunInput  map[string]interface{} // input passed to the last DryRun call
+	Fixtures     map[string]interface{} // fixtures passed to the last DryRun call
 }
 
 func NewMockWorkflowExecutor() *MockWorkflowExecutor {
@@ -272,11 +516,16 @@ func NewMockWorkflowExecutor() *MockWorkflowExecutor {
 	}
 }
 
-func (m *MockWorkflowExecutor) Execute(ctx context.Context, workflow *domain.Workflow, input map[string]interface{}) (*port.ExecuteResult, error) {
+func (m *MockWorkflowExecutor) Execute(ctx context.Context, workflow *domain.Workflow, executionID uuid.UUID, input map[string]interface{}, resume *domain.ExecutionResume) (*port.ExecuteResult, error) {
 	m.ExecuteCalled = true
+	m.Workflow = workflow
+	m.ExecuteResume = resume
 	if m.ExecuteErr != nil {
 		return nil, m.ExecuteErr
 	}
+	if m.OnExecute != nil {
+		m.OnExecute(executionID)
+	}
 	return m.ExecuteResult, nil
 }
 
@@ -289,6 +538,113 @@ func (m *MockWorkflowExecutor) GetStatus(ctx context.Context, temporalWorkflowID
 	return "running", nil
 }
 
+func (m *MockWorkflowExecutor) ListApprovals(ctx context.Context, temporalWorkflowID string) ([]*domain.Approval, error) {
+	if m.ListApprovalErr != nil {
+		return nil, m.ListApprovalErr
+	}
+	return m.Approvals, nil
+}
+
+func (m *MockWorkflowExecutor) SubmitApproval(ctx context.Context, temporalWorkflowID string, decision domain.ApprovalDecision) error {
+	if m.SubmitErr != nil {
+		return m.SubmitErr
+	}
+	m.Decisions = append(m.Decisions, decision)
+	return nil
+}
+
+func (m *MockWorkflowExecutor) ListSteps(ctx context.Context, temporalWorkflowID string) ([]*domain.ExecutionStep, error) {
+	if m.ListStepsErr != nil {
+		return nil, m.ListStepsErr
+	}
+	return m.Steps, nil
+}
+
+func (m *MockWorkflowExecutor) Pause(ctx context.Context, temporalWorkflowID string, pause domain.ExecutionPause) error {
+	if m.PauseErr != nil {
+		return m.PauseErr
+	}
+	m.Pauses = append(m.Pauses, pause)
+	return nil
+}
+
+func (m *MockWorkflowExecutor) Resume(ctx context.Context, temporalWorkflowID string, resume domain.ExecutionPause) error {
+	if m.PauseErr != nil {
+		return m.PauseErr
+	}
+	m.Resumes = append(m.Resumes, resume)
+	return nil
+}
+
+func (m *MockWorkflowExecutor) GetProgress(ctx context.Context, temporalWorkflowID string) (*domain.ExecutionProgress, error) {
+	if m.GetProgressErr != nil {
+		return nil, m.GetProgressErr
+	}
+	if m.Progress != nil {
+		return m.Progress, nil
+	}
+	return &domain.ExecutionProgress{CompletedSteps: []*domain.ExecutionStep{}, Outputs: map[string]interface{}{}}, nil
+}
+
+func (m *MockWorkflowExecutor) DryRun(ctx context.Context, workflow *domain.Workflow, input map[string]interface{}, fixtures map[string]interface{}) (*domain.DryRun, error) {
+	m.Workflow = workflow
+	m.DryRunInput = input
+	m.Fixtures = fixtures
+	if m.DryRunErr != nil {
+		return nil, m.DryRunErr
+	}
+	if m.DryRunResult != nil {
+		return m.DryRunResult, nil
+	}
+	return &domain.DryRun{Status: "completed", Steps: []domain.DryRunStep{}}, nil
+}
+
+// ============================================================================
+// MOCK WORKFLOW SCHEDULER
+// ============================================================================
+
+type MockWorkflowScheduler struct {
+	mu        sync.RWMutex
+	Schedules map[uuid.UUID]domain.Workflow // workflow state at the last Sync
+	Paused    map[uuid.UUID]bool
+	SyncErr   error
+	DeleteErr error
+}
+
+func NewMockWorkflowScheduler() *MockWorkflowScheduler {
+	return &MockWorkflowScheduler{
+		Schedules: make(map[uuid.UUID]domain.Workflow),
+		Paused:    make(map[uuid.UUID]bool),
+	}
+}
+
+func (m *MockWorkflowScheduler) Sync(ctx context.Context, workflow *domain.Workflow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SyncErr != nil {
+		return m.SyncErr
+	}
+	if !workflow.IsScheduled() {
+		delete(m.Schedules, workflow.ID)
+		delete(m.Paused, workflow.ID)
+		return nil
+	}
+	m.Schedules[workflow.ID] = *workflow
+	m.Paused[workflow.ID] = !workflow.CanExecute()
+	return nil
+}
+
+func (m *MockWorkflowScheduler) Delete(ctx context.Context, workflowID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.DeleteErr != nil {
+		return m.DeleteErr
+	}
+	delete(m.Schedules, workflowID)
+	delete(m.Paused, workflowID)
+	return nil
+}
+
 // ============================================================================
 // MOCK AUDIT SERVICE
 // ============================================================================
@@ -352,6 +708,60 @@ func (m *MockTenantContextSetter) SetTenantContext(ctx context.Context, tenantID
 	return m.SetErr
 }
 
+// ============================================================================
+// MOCK DEFINITION VALIDATOR
+// ============================================================================
+
+type MockDefinitionValidator struct {
+	ValidateCalled bool
+	Errors         []domain.DefinitionError
+	Definition     []byte
+}
+
+func NewMockDefinitionValidator() *MockDefinitionValidator {
+	return &MockDefinitionValidator{}
+}
+
+func (m *MockDefinitionValidator) Validate(definition []byte) []domain.DefinitionError {
+	m.ValidateCalled = true
+	m.Definition = definition
+	return m.Errors
+}
+
+// ============================================================================
+// MOCK TEMPLATE CATALOG
+// ============================================================================
+
+type MockTemplateCatalog struct {
+	templates []*domain.WorkflowTemplate
+}
+
+func NewMockTemplateCatalog() *MockTemplateCatalog {
+	return &MockTemplateCatalog{}
+}
+
+func (m *MockTemplateCatalog) List() []*domain.WorkflowTemplate {
+	return m.templates
+}
+
+func (m *MockTemplateCatalog) Find(name string, version int) (*domain.WorkflowTemplate, error) {
+	var found *domain.WorkflowTemplate
+	for _, t := range m.templates {
+		if t.Name == name && (t.Version == version || (version == 0 && (found == nil || t.Version > found.Version))) {
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, domain.ErrTemplateNotFound
+	}
+	return found, nil
+}
+
+// AddTemplate adds a template to the mock catalog (for test setup)
+func (m *MockTemplateCatalog) AddTemplate(t *domain.WorkflowTemplate) {
+	m.templates = append(m.templates, t)
+}
+
 // ============================================================================
 // MOCK ALERT REPOSITORY
 // ============================================================================
@@ -443,6 +853,265 @@ func (m *MockAlertRepository) AddAlert(a *domain.Alert) {
 	m.alerts[a.ID] = a
 }
 
+// ============================================================================
+// MOCK ALERT RULE REPOSITORY
+// ============================================================================
+
+type MockAlertRuleRepository struct {
+	mu    sync.RWMutex
+	rules map[uuid.UUID]*domain.AlertRule
+
+	SaveCalled   bool
+	UpdateCalled bool
+	SaveErr      error
+	UpdateErr    error
+	FindErr      error
+}
+
+func NewMockAlertRuleRepository() *MockAlertRuleRepository {
+	return &MockAlertRuleRepository{
+		rules: make(map[uuid.UUID]*domain.AlertRule),
+	}
+}
+
+func (m *MockAlertRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.AlertRule, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if r, ok := m.rules[id]; ok {
+		return r, nil
+	}
+	return nil, domain.ErrAlertRuleNotFound
+}
+
+func (m *MockAlertRuleRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID, limit, offset int) ([]*domain.AlertRule, error) {
+	return m.filter(func(r *domain.AlertRule) bool { return r.TenantID == tenantID })
+}
+
+func (m *MockAlertRuleRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.AlertRule, error) {
+	rules, err := m.filter(func(r *domain.AlertRule) bool { return r.TenantID == tenantID && r.Name == name })
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, domain.ErrAlertRuleNotFound
+	}
+	return rules[0], nil
+}
+
+func (m *MockAlertRuleRepository) FindByWorkflow(ctx context.Context, tenantID, workflowID uuid.UUID) ([]*domain.AlertRule, error) {
+	return m.filter(func(r *domain.AlertRule) bool {
+		return r.TenantID == tenantID && r.TriggerWorkflowID != nil && *r.TriggerWorkflowID == workflowID
+	})
+}
+
+func (m *MockAlertRuleRepository) FindEnabledByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.AlertRule, error) {
+	return m.filter(func(r *domain.AlertRule) bool { return r.TenantID == tenantID && r.Enabled })
+}
+
+func (m *MockAlertRuleRepository) CountByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error) {
+	rules, err := m.FindByTenant(ctx, tenantID, 0, 0)
+	return int64(len(rules)), err
+}
+
+func (m *MockAlertRuleRepository) Save(ctx context.Context, rule *domain.AlertRule) error {
+	m.SaveCalled = true
+	if m.SaveErr != nil {
+		return m.SaveErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *MockAlertRuleRepository) Update(ctx context.Context, rule *domain.AlertRule) error {
+	m.UpdateCalled = true
+	if m.UpdateErr != nil {
+		return m.UpdateErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.rules, id)
+	return nil
+}
+
+func (m *MockAlertRuleRepository) UpdateLastTriggered(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.rules[id]; ok {
+		now := time.Now()
+		r.LastTriggeredAt = &now
+	}
+	return nil
+}
+
+// AddRule adds an alert rule to the mock repository (for test setup)
+func (m *MockAlertRuleRepository) AddRule(r *domain.AlertRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rules[r.ID] = r
+}
+
+// filter returns the rules matching fn, sorted by name
+func (m *MockAlertRuleRepository) filter(fn func(*domain.AlertRule) bool) ([]*domain.AlertRule, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := []*domain.AlertRule{}
+	for _, r := range m.rules {
+		if fn(r) {
+			result = append(result, r)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// ============================================================================
+// MOCK SECRET REPOSITORY
+// ============================================================================
+
+type MockSecretRepository struct {
+	mu      sync.RWMutex
+	secrets map[uuid.UUID]*domain.Secret
+
+	SaveCalled   bool
+	UpdateCalled bool
+	DeleteCalled bool
+	FindErr      error
+}
+
+func NewMockSecretRepository() *MockSecretRepository {
+	return &MockSecretRepository{
+		secrets: make(map[uuid.UUID]*domain.Secret),
+	}
+}
+
+func (m *MockSecretRepository) FindByID(ctx context.Context, tenantID, id uuid.UUID) (*domain.Secret, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if s, ok := m.secrets[id]; ok && s.TenantID == tenantID {
+		return s, nil
+	}
+	return nil, domain.ErrSecretNotFound
+}
+
+func (m *MockSecretRepository) FindByName(ctx context.Context, tenantID uuid.UUID, name string) (*domain.Secret, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, s := range m.secrets {
+		if s.TenantID == tenantID && s.Name == name {
+			return s, nil
+		}
+	}
+	return nil, domain.ErrSecretNotFound
+}
+
+func (m *MockSecretRepository) FindByTenant(ctx context.Context, tenantID uuid.UUID) ([]*domain.Secret, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var secrets []*domain.Secret
+	for _, s := range m.secrets {
+		if s.TenantID == tenantID {
+			secrets = append(secrets, s)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+	return secrets, nil
+}
+
+func (m *MockSecretRepository) Save(ctx context.Context, secret *domain.Secret) error {
+	m.SaveCalled = true
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if secret.ID == uuid.Nil {
+		secret.ID = uuid.New()
+	}
+	m.secrets[secret.ID] = secret
+	return nil
+}
+
+func (m *MockSecretRepository) Update(ctx context.Context, secret *domain.Secret) error {
+	m.UpdateCalled = true
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.secrets[secret.ID]; !ok {
+		return domain.ErrSecretNotFound
+	}
+	m.secrets[secret.ID] = secret
+	return nil
+}
+
+func (m *MockSecretRepository) Delete(ctx context.Context, tenantID, id uuid.UUID) error {
+	m.DeleteCalled = true
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.secrets, id)
+	return nil
+}
+
+// AddSecret adds a secret to the mock (for test setup)
+func (m *MockSecretRepository) AddSecret(secret *domain.Secret) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[secret.ID] = secret
+}
+
+// ============================================================================
+// MOCK SECRET CIPHER
+// ============================================================================
+
+// MockSecretCipher "encrypts" by reversing the bytes, so tests can check
+// that stored values aren't the plaintext
+type MockSecretCipher struct {
+	EncryptErr error
+	DecryptErr error
+}
+
+func NewMockSecretCipher() *MockSecretCipher {
+	return &MockSecretCipher{}
+}
+
+func (m *MockSecretCipher) Encrypt(plaintext []byte) ([]byte, []byte, error) {
+	if m.EncryptErr != nil {
+		return nil, nil, m.EncryptErr
+	}
+	return reverseBytes(plaintext), []byte("data-key"), nil
+}
+
+func (m *MockSecretCipher) Decrypt(value, key []byte) ([]byte, error) {
+	if m.DecryptErr != nil {
+		return nil, m.DecryptErr
+	}
+	return reverseBytes(value), nil
+}
+
+func reverseBytes(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
 // ============================================================================
 // MOCK AUDIT REPOSITORY
 // ============================================================================
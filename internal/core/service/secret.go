@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/pkg/validation"
+)
+
+// SecretService implements port.SecretService
+type SecretService struct {
+	secretRepo   port.SecretRepository
+	cipher       port.SecretCipher
+	auditService port.AuditService
+	tenantSetter port.TenantContextSetter
+}
+
+// NewSecretService creates a new secret service. Without a cipher (no
+// master key configured) secrets can be listed and deleted, but setting a
+// value returns domain.ErrSecretsNotConfigured.
+func NewSecretService(
+	secretRepo port.SecretRepository,
+	cipher port.SecretCipher,
+	auditService port.AuditService,
+	tenantSetter port.TenantContextSetter,
+) *SecretService {
+	return &SecretService{
+		secretRepo:   secretRepo,
+		cipher:       cipher,
+		auditService: auditService,
+		tenantSetter: tenantSetter,
+	}
+}
+
+// List returns the secrets of a tenant, without their values
+func (s *SecretService) List(ctx context.Context, tenantID uuid.UUID) ([]*domain.Secret, error) {
+	if err := s.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return s.secretRepo.FindByTenant(ctx, tenantID)
+}
+
+// Get returns a secret, without its value
+func (s *SecretService) Get(ctx context.Context, tenantID, id uuid.UUID) (*domain.Secret, error) {
+	if err := s.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return s.secretRepo.FindByID(ctx, tenantID, id)
+}
+
+// Create encrypts and stores a new secret. Invalid names and values return
+// a validation error that wraps domain.ErrInvalidSecret.
+func (s *SecretService) Create(ctx context.Context, input port.CreateSecretInput) (*domain.Secret, error) {
+	v := validation.New()
+	if v.Required("name", input.Name); !v.HasErrors() {
+		v.Custom("name", domain.ValidSecretName(input.Name), "name must start with a letter or underscore and contain only letters, digits and underscores (at most 128)")
+	}
+	validateSecretValue(v, input.Value)
+	if appErr := v.Error(); appErr != nil {
+		appErr.Err = domain.ErrInvalidSecret
+		return nil, appErr
+	}
+	if s.cipher == nil {
+		return nil, domain.ErrSecretsNotConfigured
+	}
+
+	if err := s.tenantSetter.SetTenantContext(ctx, input.TenantID); err != nil {
+		return nil, err
+	}
+
+	_, err := s.secretRepo.FindByName(ctx, input.TenantID, input.Name)
+	if err == nil {
+		return nil, domain.ErrSecretAlreadyExists
+	}
+	if !errors.Is(err, domain.ErrSecretNotFound) {
+		return nil, err
+	}
+
+	secret := &domain.Secret{
+		TenantID:    input.TenantID,
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedBy:   input.CreatedBy,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := s.seal(secret, input.Value); err != nil {
+		return nil, err
+	}
+
+	if err := s.secretRepo.Save(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, input.TenantID, input.CreatedBy, domain.AuditEventSecretCreated, secret.ID, nil, secret)
+
+	return secret, nil
+}
+
+// Update replaces the description and, when set, the value of a secret.
+// Secrets can't be renamed, since workflows reference them by name.
+func (s *SecretService) Update(ctx context.Context, input port.UpdateSecretInput) (*domain.Secret, error) {
+	if input.Value != nil {
+		v := validation.New()
+		validateSecretValue(v, *input.Value)
+		if appErr := v.Error(); appErr != nil {
+			appErr.Err = domain.ErrInvalidSecret
+			return nil, appErr
+		}
+		if s.cipher == nil {
+			return nil, domain.ErrSecretsNotConfigured
+		}
+	}
+
+	if err := s.tenantSetter.SetTenantContext(ctx, input.TenantID); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.secretRepo.FindByID(ctx, input.TenantID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	oldSecret := *secret
+
+	if input.Description != nil {
+		secret.Description = input.Description
+	}
+	if input.Value != nil {
+		if err := s.seal(secret, *input.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.secretRepo.Update(ctx, secret); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, input.TenantID, input.UpdatedBy, domain.AuditEventSecretUpdated, secret.ID, &oldSecret, secret)
+
+	return secret, nil
+}
+
+// Delete deletes a secret. Workflows still referencing it fail at the step
+// that uses it.
+func (s *SecretService) Delete(ctx context.Context, tenantID, id uuid.UUID, userID *uuid.UUID) error {
+	if err := s.tenantSetter.SetTenantContext(ctx, tenantID); err != nil {
+		return err
+	}
+
+	secret, err := s.secretRepo.FindByID(ctx, tenantID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.secretRepo.Delete(ctx, tenantID, id); err != nil {
+		return err
+	}
+
+	s.logAudit(ctx, tenantID, userID, domain.AuditEventSecretDeleted, id, secret, nil)
+
+	return nil
+}
+
+// seal sets the encrypted value and data key of a secret
+func (s *SecretService) seal(secret *domain.Secret, value string) error {
+	encryptedValue, encryptedKey, err := s.cipher.Encrypt([]byte(value))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+	secret.EncryptedValue = encryptedValue
+	secret.EncryptedKey = encryptedKey
+	return nil
+}
+
+func validateSecretValue(v *validation.Validator, value string) {
+	if value == "" {
+		v.AddError("value", "value is required")
+		return
+	}
+	v.Custom("value", len(value) <= domain.MaxSecretValueSize, fmt.Sprintf("value must be at most %d bytes", domain.MaxSecretValueSize))
+}
+
+// logAudit records a secret change. Audit values never hold the secret
+// value, encrypted or not.
+func (s *SecretService) logAudit(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, eventType string, resourceID uuid.UUID, oldValue, newValue interface{}) {
+	if s.auditService == nil {
+		return
+	}
+
+	action := domain.ActionCreate
+	switch eventType {
+	case domain.AuditEventSecretUpdated:
+		action = domain.ActionUpdate
+	case domain.AuditEventSecretDeleted:
+		action = domain.ActionDelete
+	}
+
+	log := domain.NewAuditLog(tenantID, userID, eventType, domain.ResourceTypeSecret, &resourceID, action).
+		WithOldValue(oldValue).
+		WithNewValue(newValue)
+
+	s.auditService.Log(ctx, log)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretService_Create(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	t.Run("stores the value encrypted", func(t *testing.T) {
+		secretRepo := mocks.NewMockSecretRepository()
+		auditService := mocks.NewMockAuditService()
+		svc := NewSecretService(secretRepo, mocks.NewMockSecretCipher(), auditService, mocks.NewMockTenantContextSetter())
+
+		secret, err := svc.Create(ctx, port.CreateSecretInput{
+			TenantID:  tenantID,
+			Name:      "API_TOKEN",
+			Value:     "s3cr3t",
+			CreatedBy: &userID,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "API_TOKEN", secret.Name)
+		assert.Equal(t, []byte("t3rc3s"), secret.EncryptedValue)
+		assert.Equal(t, []byte("data-key"), secret.EncryptedKey)
+		assert.True(t, secretRepo.SaveCalled)
+
+		require.Len(t, auditService.Logs, 1)
+		assert.Equal(t, domain.AuditEventSecretCreated, auditService.Logs[0].EventType)
+		assert.NotContains(t, string(auditService.Logs[0].NewValue), "t3rc3s")
+		assert.NotContains(t, string(auditService.Logs[0].NewValue), "encrypted")
+	})
+
+	t.Run("rejects invalid names and values", func(t *testing.T) {
+		svc := NewSecretService(mocks.NewMockSecretRepository(), mocks.NewMockSecretCipher(), nil, mocks.NewMockTenantContextSetter())
+
+		_, err := svc.Create(ctx, port.CreateSecretInput{TenantID: tenantID, Name: "api-token"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidSecret)
+		assert.ErrorContains(t, err, "validation failed")
+
+		_, err = svc.Create(ctx, port.CreateSecretInput{
+			TenantID: tenantID,
+			Name:     "BIG",
+			Value:    strings.Repeat("x", domain.MaxSecretValueSize+1),
+		})
+		assert.ErrorIs(t, err, domain.ErrInvalidSecret)
+	})
+
+	t.Run("rejects a duplicate name", func(t *testing.T) {
+		secretRepo := mocks.NewMockSecretRepository()
+		secretRepo.AddSecret(&domain.Secret{ID: uuid.New(), TenantID: tenantID, Name: "API_TOKEN"})
+		svc := NewSecretService(secretRepo, mocks.NewMockSecretCipher(), nil, mocks.NewMockTenantContextSetter())
+
+		_, err := svc.Create(ctx, port.CreateSecretInput{TenantID: tenantID, Name: "API_TOKEN", Value: "s3cr3t"})
+
+		assert.ErrorIs(t, err, domain.ErrSecretAlreadyExists)
+	})
+
+	t.Run("fails without a master key", func(t *testing.T) {
+		secretRepo := mocks.NewMockSecretRepository()
+		svc := NewSecretService(secretRepo, nil, nil, mocks.NewMockTenantContextSetter())
+
+		_, err := svc.Create(ctx, port.CreateSecretInput{TenantID: tenantID, Name: "API_TOKEN", Value: "s3cr3t"})
+
+		assert.ErrorIs(t, err, domain.ErrSecretsNotConfigured)
+		assert.False(t, secretRepo.SaveCalled)
+	})
+}
+
+func TestSecretService_Update(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	setup := func() (*SecretService, *mocks.MockSecretRepository, *domain.Secret) {
+		secretRepo := mocks.NewMockSecretRepository()
+		secret := &domain.Secret{
+			ID:             uuid.New(),
+			TenantID:       tenantID,
+			Name:           "API_TOKEN",
+			EncryptedValue: []byte("dlo"),
+			EncryptedKey:   []byte("data-key"),
+		}
+		secretRepo.AddSecret(secret)
+		return NewSecretService(secretRepo, mocks.NewMockSecretCipher(), nil, mocks.NewMockTenantContextSetter()), secretRepo, secret
+	}
+
+	t.Run("replaces the value", func(t *testing.T) {
+		svc, secretRepo, secret := setup()
+		value := "new"
+
+		updated, err := svc.Update(ctx, port.UpdateSecretInput{TenantID: tenantID, ID: secret.ID, Value: &value})
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte("wen"), updated.EncryptedValue)
+		assert.True(t, secretRepo.UpdateCalled)
+	})
+
+	t.Run("keeps the value when only the description changes", func(t *testing.T) {
+		svc, _, secret := setup()
+		description := "Token for the deploy API"
+
+		updated, err := svc.Update(ctx, port.UpdateSecretInput{TenantID: tenantID, ID: secret.ID, Description: &description})
+
+		require.NoError(t, err)
+		assert.Equal(t, &description, updated.Description)
+		assert.Equal(t, []byte("dlo"), updated.EncryptedValue)
+	})
+
+	t.Run("doesn't find secrets of other tenants", func(t *testing.T) {
+		svc, _, secret := setup()
+
+		_, err := svc.Update(ctx, port.UpdateSecretInput{TenantID: uuid.New(), ID: secret.ID})
+
+		assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+	})
+}
+
+func TestSecretService_Delete(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	secretRepo := mocks.NewMockSecretRepository()
+	auditService := mocks.NewMockAuditService()
+	secret := &domain.Secret{ID: uuid.New(), TenantID: tenantID, Name: "API_TOKEN"}
+	secretRepo.AddSecret(secret)
+	svc := NewSecretService(secretRepo, nil, auditService, mocks.NewMockTenantContextSetter())
+
+	require.NoError(t, svc.Delete(ctx, tenantID, secret.ID, nil))
+
+	_, err := svc.Get(ctx, tenantID, secret.ID)
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+	require.Len(t, auditService.Logs, 1)
+	assert.Equal(t, domain.ActionDelete, auditService.Logs[0].Action)
+
+	assert.ErrorIs(t, svc.Delete(ctx, tenantID, secret.ID, nil), domain.ErrSecretNotFound)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/pkg/validation"
+)
+
+// TemplateService implements port.TemplateService
+type TemplateService struct {
+	catalog         port.TemplateCatalog
+	workflowService port.WorkflowService
+}
+
+// NewTemplateService creates a new template service. Workflows are created
+// through the workflow service, so instantiated definitions are validated
+// and audited like any other.
+func NewTemplateService(catalog port.TemplateCatalog, workflowService port.WorkflowService) *TemplateService {
+	return &TemplateService{
+		catalog:         catalog,
+		workflowService: workflowService,
+	}
+}
+
+// List returns the latest version of each template
+func (s *TemplateService) List(ctx context.Context) []*domain.WorkflowTemplate {
+	return s.catalog.List()
+}
+
+// Get returns a version of a template, 0 being the latest
+func (s *TemplateService) Get(ctx context.Context, name string, version int) (*domain.WorkflowTemplate, error) {
+	return s.catalog.Find(name, version)
+}
+
+// Instantiate creates a draft workflow from a template. Invalid parameters
+// return a validation error with one entry per parameter that wraps
+// domain.ErrInvalidTemplateParameters.
+func (s *TemplateService) Instantiate(ctx context.Context, input port.InstantiateTemplateInput) (*domain.Workflow, error) {
+	template, err := s.catalog.Find(input.Template, input.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, paramErrs := template.Instantiate(input.Parameters)
+	if len(paramErrs) > 0 {
+		v := validation.New()
+		for _, e := range paramErrs {
+			field := "parameters." + e.Field
+			v.AddError(field, field+" "+e.Message)
+		}
+		appErr := v.Error()
+		appErr.Err = domain.ErrInvalidTemplateParameters
+		return nil, appErr
+	}
+
+	name := input.Name
+	if name == "" {
+		name = template.Name
+	}
+	description := input.Description
+	if description == nil {
+		description = &template.Description
+	}
+
+	return s.workflowService.Create(ctx, port.CreateWorkflowInput{
+		TenantID:    input.TenantID,
+		Name:        name,
+		Description: description,
+		Definition:  definition,
+		CreatedBy:   input.CreatedBy,
+	})
+}
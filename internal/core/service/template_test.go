@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+	"github.com/orchestrix/orchestrix-api/pkg/apperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateService_Instantiate(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	newService := func() (*TemplateService, *mocks.MockWorkflowRepository) {
+		catalog := mocks.NewMockTemplateCatalog()
+		for version, message := range map[int]string{1: "v1 ${params.text}", 2: "v2 ${params.text}"} {
+			catalog.AddTemplate(&domain.WorkflowTemplate{
+				Name:        "announce",
+				Version:     version,
+				Description: "Sends a notification",
+				Parameters: &domain.InputSchema{
+					Type:       domain.InputTypeObject,
+					Required:   []string{"text"},
+					Properties: map[string]*domain.InputSchema{"text": {Type: domain.InputTypeString}},
+				},
+				Definition: json.RawMessage(`{"steps":[{"id":"notify","type":"notify","config":{"message":"` + message + `"}}]}`),
+			})
+		}
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		workflowService := NewWorkflowService(workflowRepo, mocks.NewMockWorkflowVersionRepository(), mocks.NewMockExecutionRepository(), mocks.NewMockWorkflowExecutor(), nil, mocks.NewMockDefinitionValidator(), mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+		return NewTemplateService(catalog, workflowService), workflowRepo
+	}
+
+	t.Run("creates a draft from the latest version", func(t *testing.T) {
+		svc, workflowRepo := newService()
+
+		workflow, err := svc.Instantiate(ctx, port.InstantiateTemplateInput{
+			TenantID:   tenantID,
+			Template:   "announce",
+			Parameters: map[string]interface{}{"text": "deploy done"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "announce", workflow.Name)
+		assert.Equal(t, "Sends a notification", *workflow.Description)
+		assert.Equal(t, domain.WorkflowStatusDraft, workflow.Status)
+		assert.JSONEq(t, `{"steps":[{"id":"notify","type":"notify","config":{"message":"v2 deploy done"}}]}`, string(workflow.Definition))
+		assert.True(t, workflowRepo.SaveCalled)
+	})
+
+	t.Run("uses the requested version and name", func(t *testing.T) {
+		svc, _ := newService()
+
+		workflow, err := svc.Instantiate(ctx, port.InstantiateTemplateInput{
+			TenantID:   tenantID,
+			Template:   "announce",
+			Version:    1,
+			Name:       "announce deploys",
+			Parameters: map[string]interface{}{"text": "deploy done"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "announce deploys", workflow.Name)
+		assert.Contains(t, string(workflow.Definition), "v1 deploy done")
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		svc, workflowRepo := newService()
+
+		_, err := svc.Instantiate(ctx, port.InstantiateTemplateInput{
+			TenantID:   tenantID,
+			Template:   "announce",
+			Parameters: map[string]interface{}{"text": 42},
+		})
+
+		require.True(t, errors.Is(err, domain.ErrInvalidTemplateParameters))
+		appErr, ok := apperror.GetAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, map[string]string{"parameters.text": "parameters.text must be a string"}, appErr.Details["fields"])
+		assert.False(t, workflowRepo.SaveCalled)
+	})
+
+	t.Run("returns not found for unknown templates", func(t *testing.T) {
+		svc, _ := newService()
+
+		_, err := svc.Instantiate(ctx, port.InstantiateTemplateInput{TenantID: tenantID, Template: "announce", Version: 3})
+
+		assert.ErrorIs(t, err, domain.ErrTemplateNotFound)
+	})
+}
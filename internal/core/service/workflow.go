@@ -9,13 +9,17 @@ import (
 	"github.com/google/uuid"
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
 	"github.com/orchestrix/orchestrix-api/internal/core/port"
+	"github.com/orchestrix/orchestrix-api/pkg/validation"
 )
 
 // WorkflowService implements port.WorkflowService
 type WorkflowService struct {
 	workflowRepo  port.WorkflowRepository
+	versionRepo   port.WorkflowVersionRepository
 	executionRepo port.ExecutionRepository
 	executor      port.WorkflowExecutor
+	scheduler     port.WorkflowScheduler
+	validator     port.DefinitionValidator
 	auditService  port.AuditService
 	tenantSetter  port.TenantContextSetter
 }
@@ -23,15 +27,21 @@ type WorkflowService struct {
 // NewWorkflowService creates a new workflow service
 func NewWorkflowService(
 	workflowRepo port.WorkflowRepository,
+	versionRepo port.WorkflowVersionRepository,
 	executionRepo port.ExecutionRepository,
 	executor port.WorkflowExecutor,
+	scheduler port.WorkflowScheduler,
+	validator port.DefinitionValidator,
 	auditService port.AuditService,
 	tenantSetter port.TenantContextSetter,
 ) *WorkflowService {
 	return &WorkflowService{
 		workflowRepo:  workflowRepo,
+		versionRepo:   versionRepo,
 		executionRepo: executionRepo,
 		executor:      executor,
+		scheduler:     scheduler,
+		validator:     validator,
 		auditService:  auditService,
 		tenantSetter:  tenantSetter,
 	}
@@ -74,24 +84,50 @@ func (s *WorkflowService) Create(ctx context.Context, input port.CreateWorkflowI
 		return nil, err
 	}
 
+	// Drafts can be created without a definition
+	if len(input.Definition) > 0 {
+		if err := s.validate(input.Definition); err != nil {
+			return nil, err
+		}
+	}
+
 	workflow := &domain.Workflow{
-		ID:          uuid.New(),
-		TenantID:    input.TenantID,
-		Name:        input.Name,
-		Description: input.Description,
-		Definition:  input.Definition,
-		Schedule:    input.Schedule,
-		Status:      domain.WorkflowStatusDraft,
-		Version:     1,
-		CreatedBy:   input.CreatedBy,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:                 uuid.New(),
+		TenantID:           input.TenantID,
+		Name:               input.Name,
+		Description:        input.Description,
+		Definition:         input.Definition,
+		DefinitionComments: input.DefinitionComments,
+		Schedule:           normalizeSchedule(input.Schedule),
+		ScheduleOptions:    input.ScheduleOptions,
+		Status:             domain.WorkflowStatusDraft,
+		Version:            1,
+		CreatedBy:          input.CreatedBy,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
+	}
+
+	if err := validateSchedule(workflow); err != nil {
+		return nil, err
 	}
 
 	if err := s.workflowRepo.Save(ctx, workflow); err != nil {
 		return nil, err
 	}
 
+	if len(workflow.Definition) > 0 {
+		if err := s.saveVersion(ctx, workflow, input.CreatedBy, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Drafts get a paused schedule that starts running once activated
+	if workflow.IsScheduled() {
+		if err := s.syncSchedule(ctx, workflow); err != nil {
+			return nil, err
+		}
+	}
+
 	// Log audit
 	s.logAudit(ctx, input.TenantID, input.CreatedBy, domain.AuditEventWorkflowCreated, workflow.ID, nil, workflow)
 
@@ -114,22 +150,61 @@ func (s *WorkflowService) Update(ctx context.Context, id uuid.UUID, input port.U
 		workflow.Description = input.Description
 	}
 	if input.Definition != nil {
+		// Comments belong to the definition they were written with
 		workflow.Definition = input.Definition
+		workflow.DefinitionComments = input.DefinitionComments
 	}
 	if input.Schedule != nil {
-		workflow.Schedule = input.Schedule
+		workflow.Schedule = normalizeSchedule(input.Schedule)
+	}
+	if input.ScheduleOptions != nil {
+		workflow.ScheduleOptions = input.ScheduleOptions
 	}
 	if input.Status != nil {
 		workflow.Status = *input.Status
 	}
+
+	// Check the definition when it changes or the workflow is activated
+	activating := workflow.Status == domain.WorkflowStatusActive && oldWorkflow.Status != domain.WorkflowStatusActive
+	if len(input.Definition) > 0 || activating {
+		if err := s.validate(workflow.Definition); err != nil {
+			return nil, err
+		}
+	}
+	scheduleChanged := input.Schedule != nil || input.ScheduleOptions != nil
+	if scheduleChanged || len(input.Definition) > 0 || activating {
+		if err := validateSchedule(workflow); err != nil {
+			return nil, err
+		}
+	}
 	workflow.UpdatedAt = time.Now()
 
+	// Every new definition is published as a new version
+	publishing := input.Definition != nil && !sameDefinition(oldWorkflow.Definition, workflow.Definition)
+	if publishing {
+		if err := s.nextVersion(ctx, workflow); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.workflowRepo.Update(ctx, workflow); err != nil {
 		return nil, err
 	}
+	if publishing {
+		if err := s.saveVersion(ctx, workflow, input.UpdatedBy, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Activating or deactivating the workflow resumes or pauses its schedule
+	if scheduleChanged || workflow.Status != oldWorkflow.Status {
+		if err := s.syncSchedule(ctx, workflow); err != nil {
+			return nil, err
+		}
+	}
 
 	// Log audit
-	s.logAudit(ctx, workflow.TenantID, nil, domain.AuditEventWorkflowUpdated, workflow.ID, &oldWorkflow, workflow)
+	s.logAudit(ctx, workflow.TenantID, input.UpdatedBy, domain.AuditEventWorkflowUpdated, workflow.ID, &oldWorkflow, workflow)
 
 	return workflow, nil
 }
@@ -141,6 +216,13 @@ func (s *WorkflowService) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	// Remove the schedule first so it can't start runs of a deleted workflow
+	if workflow.IsScheduled() && s.scheduler != nil {
+		if err := s.scheduler.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete workflow schedule: %w", err)
+		}
+	}
+
 	if err := s.workflowRepo.Delete(ctx, id); err != nil {
 		return err
 	}
@@ -151,8 +233,14 @@ func (s *WorkflowService) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// Execute starts a workflow execution
+// Execute starts an execution of the latest workflow version
 func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID string, input map[string]interface{}) (*domain.Execution, error) {
+	return s.ExecuteVersion(ctx, id, 0, userID, input)
+}
+
+// ExecuteVersion starts an execution of a specific workflow version. Version
+// 0 runs the latest version.
+func (s *WorkflowService) ExecuteVersion(ctx context.Context, id uuid.UUID, version int32, userID string, input map[string]interface{}) (*domain.Execution, error) {
 	workflow, err := s.workflowRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -162,6 +250,16 @@ func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID stri
 		return nil, domain.ErrWorkflowCannotExecute
 	}
 
+	workflow, err = s.pinVersion(ctx, workflow, version)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err = PrepareInput(workflow, input)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create execution record
 	inputJSON, _ := json.Marshal(input)
 	userUUID, _ := uuid.Parse(userID)
@@ -175,6 +273,8 @@ func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID stri
 		CreatedBy:   &userUUID,
 		CreatedAt:   time.Now(),
 		TriggeredBy: stringPtr("user:" + userID),
+		// Record the version so it's clear which definition ran
+		WorkflowVersion: &workflow.Version,
 	}
 
 	if err := s.executionRepo.Save(ctx, execution); err != nil {
@@ -182,7 +282,7 @@ func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID stri
 	}
 
 	// Execute via Temporal
-	result, err := s.executor.Execute(ctx, workflow, input)
+	result, err := s.executor.Execute(ctx, workflow, execution.ID, input, nil)
 	if err != nil {
 		errMsg := err.Error()
 		execution.MarkAsFailed(errMsg)
@@ -190,12 +290,10 @@ func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID stri
 		return nil, fmt.Errorf("failed to start workflow: %w", err)
 	}
 
-	// Update with Temporal IDs
-	execution.TemporalWorkflowID = &result.TemporalWorkflowID
-	execution.TemporalRunID = &result.TemporalRunID
-	execution.MarkAsRunning()
-
-	if err := s.executionRepo.Update(ctx, execution); err != nil {
+	// Update with Temporal IDs. A quick run may already have been recorded
+	// as finished by the worker, which must not be overwritten.
+	execution, err = s.executionRepo.MarkRunning(ctx, execution.ID, result.TemporalWorkflowID, result.TemporalRunID)
+	if err != nil {
 		return nil, fmt.Errorf("failed to update execution: %w", err)
 	}
 
@@ -205,6 +303,133 @@ func (s *WorkflowService) Execute(ctx context.Context, id uuid.UUID, userID stri
 	return execution, nil
 }
 
+// DryRun simulates a run of a workflow version without side effects and
+// returns its step trace. Unlike executions, workflows that can't be executed
+// yet can be dry run, so they can be reviewed before they are enabled.
+func (s *WorkflowService) DryRun(ctx context.Context, id uuid.UUID, input port.DryRunInput) (*domain.DryRun, error) {
+	workflow, err := s.workflowRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err = s.pinVersion(ctx, workflow, input.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validate(workflow.Definition); err != nil {
+		return nil, err
+	}
+
+	runInput, err := PrepareInput(workflow, input.Input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executor.DryRun(ctx, workflow, runInput, input.Fixtures)
+}
+
+// pinVersion returns the workflow with the definition of the given version.
+// Version 0 is the latest version.
+func (s *WorkflowService) pinVersion(ctx context.Context, workflow *domain.Workflow, version int32) (*domain.Workflow, error) {
+	if version == 0 || version == workflow.Version {
+		return workflow, nil
+	}
+	v, err := s.versionRepo.FindByVersion(ctx, workflow.ID, version)
+	if err != nil {
+		return nil, err
+	}
+	pinned := *workflow
+	pinned.Definition = v.Definition
+	pinned.Version = v.Version
+	return &pinned, nil
+}
+
+// PrepareInput validates input against the workflow's input schema and fills
+// in defaults. Invalid input returns a validation error with one entry per
+// field that wraps domain.ErrInvalidInput. Every path that starts a run
+// goes through it.
+func PrepareInput(workflow *domain.Workflow, input map[string]interface{}) (map[string]interface{}, error) {
+	def, err := workflow.ParseDefinition()
+	if err != nil {
+		return nil, err
+	}
+	if def.Inputs == nil {
+		return input, nil
+	}
+
+	prepared, inputErrs := def.Inputs.Apply(input)
+	if len(inputErrs) > 0 {
+		v := validation.New()
+		for _, e := range inputErrs {
+			v.AddError(e.Field, e.Field+" "+e.Message)
+		}
+		appErr := v.Error()
+		appErr.Err = domain.ErrInvalidInput
+		return nil, appErr
+	}
+	return prepared, nil
+}
+
+// validateSchedule checks the cron expression and schedule options of a
+// scheduled workflow. Scheduled runs get no input, so every required input
+// needs a default. Problems are returned as a validation error with one
+// entry per field that wraps domain.ErrInvalidSchedule.
+func validateSchedule(workflow *domain.Workflow) error {
+	if !workflow.IsScheduled() {
+		return nil
+	}
+
+	v := validation.New()
+	v.CronExpression("schedule", *workflow.Schedule)
+
+	if opts := workflow.ScheduleOptions; opts != nil {
+		if opts.Timezone != "" {
+			_, err := time.LoadLocation(opts.Timezone)
+			v.Custom("schedule_options.timezone", err == nil, fmt.Sprintf("timezone %q is not a known time zone", opts.Timezone))
+		}
+		if opts.Jitter != "" {
+			jitter, err := time.ParseDuration(opts.Jitter)
+			v.Custom("schedule_options.jitter", err == nil && jitter >= 0, fmt.Sprintf("jitter %q must be a duration such as 30s", opts.Jitter))
+		}
+		if opts.Overlap != "" {
+			v.Enum("schedule_options.overlap", opts.Overlap, domain.ScheduleOverlapPolicies)
+		}
+	}
+
+	if def, err := workflow.ParseDefinition(); err == nil && def.Inputs != nil {
+		_, inputErrs := def.Inputs.Apply(nil)
+		for _, e := range inputErrs {
+			v.AddError("input."+e.Field, fmt.Sprintf("scheduled runs have no input, so %s needs a default", e.Field))
+		}
+	}
+
+	if appErr := v.Error(); appErr != nil {
+		appErr.Err = domain.ErrInvalidSchedule
+		return appErr
+	}
+	return nil
+}
+
+// syncSchedule brings the workflow's Temporal schedule in line with its cron
+// expression and status
+func (s *WorkflowService) syncSchedule(ctx context.Context, workflow *domain.Workflow) error {
+	if s.scheduler == nil {
+		return nil
+	}
+	if err := s.scheduler.Sync(ctx, workflow); err != nil {
+		return fmt.Errorf("failed to sync workflow schedule: %w", err)
+	}
+	return nil
+}
+
+// normalizeSchedule treats an empty cron expression as no schedule
+func normalizeSchedule(schedule *string) *string {
+	if schedule == nil || *schedule == "" {
+		return nil
+	}
+	return schedule
+}
+
 // ListExecutions returns paginated executions for a workflow
 func (s *WorkflowService) ListExecutions(ctx context.Context, workflowID uuid.UUID, page, limit int) (*port.ExecutionListResult, error) {
 	offset := (page - 1) * limit
@@ -225,6 +450,149 @@ func (s *WorkflowService) ListExecutions(ctx context.Context, workflowID uuid.UU
 	}, nil
 }
 
+// ListVersions returns the versions of a workflow, newest first
+func (s *WorkflowService) ListVersions(ctx context.Context, workflowID uuid.UUID, page, limit int) (*port.WorkflowVersionListResult, error) {
+	if _, err := s.workflowRepo.FindByID(ctx, workflowID); err != nil {
+		return nil, err
+	}
+
+	offset := (page - 1) * limit
+
+	versions, err := s.versionRepo.FindByWorkflow(ctx, workflowID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.versionRepo.CountByWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.WorkflowVersionListResult{
+		Versions: versions,
+		Total:    total,
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// GetVersion returns a single version of a workflow
+func (s *WorkflowService) GetVersion(ctx context.Context, workflowID uuid.UUID, version int32) (*domain.WorkflowVersion, error) {
+	if _, err := s.workflowRepo.FindByID(ctx, workflowID); err != nil {
+		return nil, err
+	}
+	return s.versionRepo.FindByVersion(ctx, workflowID, version)
+}
+
+// DiffVersions returns the changes between two versions of a workflow
+func (s *WorkflowService) DiffVersions(ctx context.Context, workflowID uuid.UUID, from, to int32) ([]domain.DefinitionChange, error) {
+	fromVersion, err := s.GetVersion(ctx, workflowID, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := s.versionRepo.FindByVersion(ctx, workflowID, to)
+	if err != nil {
+		return nil, err
+	}
+	return domain.DiffDefinitions(fromVersion.Definition, toVersion.Definition)
+}
+
+// Rollback restores the definition of an earlier version. The restored
+// definition is published as a new version, so history is never rewritten.
+func (s *WorkflowService) Rollback(ctx context.Context, workflowID uuid.UUID, version int32, userID *uuid.UUID) (*domain.Workflow, error) {
+	workflow, err := s.workflowRepo.FindByID(ctx, workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.versionRepo.FindByVersion(ctx, workflowID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if sameDefinition(workflow.Definition, target.Definition) {
+		return workflow, nil
+	}
+	if err := s.validate(target.Definition); err != nil {
+		return nil, err
+	}
+
+	oldWorkflow := *workflow
+	workflow.Definition = target.Definition
+	workflow.DefinitionComments = nil
+	workflow.UpdatedAt = time.Now()
+
+	if err := s.nextVersion(ctx, workflow); err != nil {
+		return nil, err
+	}
+	if err := s.workflowRepo.Update(ctx, workflow); err != nil {
+		return nil, err
+	}
+	if err := s.saveVersion(ctx, workflow, userID, &target.Version); err != nil {
+		return nil, err
+	}
+
+	// Log audit
+	s.logAudit(ctx, workflow.TenantID, userID, domain.AuditEventWorkflowRolledBack, workflow.ID, &oldWorkflow, workflow)
+
+	return workflow, nil
+}
+
+// nextVersion sets workflow.Version to the version after the latest stored
+// one. A workflow created without a definition gets its first version when
+// one is set. The version is saved only once the workflow is updated, so a
+// failed update doesn't leave a version behind that later ones collide with.
+func (s *WorkflowService) nextVersion(ctx context.Context, workflow *domain.Workflow) error {
+	latest, err := s.versionRepo.LatestVersion(ctx, workflow.ID)
+	if err != nil {
+		return err
+	}
+	workflow.Version = latest + 1
+	return nil
+}
+
+func (s *WorkflowService) saveVersion(ctx context.Context, workflow *domain.Workflow, userID *uuid.UUID, restoredFrom *int32) error {
+	version := &domain.WorkflowVersion{
+		ID:           uuid.New(),
+		TenantID:     workflow.TenantID,
+		WorkflowID:   workflow.ID,
+		Version:      workflow.Version,
+		Definition:   workflow.Definition,
+		RestoredFrom: restoredFrom,
+		CreatedBy:    userID,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.versionRepo.Save(ctx, version); err != nil {
+		return fmt.Errorf("failed to save workflow version: %w", err)
+	}
+	return nil
+}
+
+// sameDefinition reports whether two definitions are equal as JSON
+func sameDefinition(a, b json.RawMessage) bool {
+	changes, err := domain.DiffDefinitions(a, b)
+	return err == nil && len(changes) == 0
+}
+
+// ValidateDefinition checks a workflow definition without storing it
+func (s *WorkflowService) ValidateDefinition(ctx context.Context, definition []byte) []domain.DefinitionError {
+	if s.validator == nil {
+		return nil
+	}
+	return s.validator.Validate(definition)
+}
+
+// validate rejects definitions with validation errors
+func (s *WorkflowService) validate(definition json.RawMessage) error {
+	if s.validator == nil {
+		return nil
+	}
+	if errs := s.validator.Validate(definition); len(errs) > 0 {
+		return &domain.DefinitionValidationError{Errors: errs}
+	}
+	return nil
+}
+
 func (s *WorkflowService) logAudit(ctx context.Context, tenantID uuid.UUID, userID *uuid.UUID, eventType string, resourceID uuid.UUID, oldValue, newValue interface{}) {
 	if s.auditService == nil {
 		return
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/orchestrix/orchestrix-api/internal/core/domain"
 	"github.com/orchestrix/orchestrix-api/internal/core/port"
 	"github.com/orchestrix/orchestrix-api/internal/core/service/mocks"
+	"github.com/orchestrix/orchestrix-api/pkg/apperror"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -19,8 +21,10 @@ func TestWorkflowService_List(t *testing.T) {
 
 	t.Run("returns paginated workflows", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -34,7 +38,7 @@ func TestWorkflowService_List(t *testing.T) {
 			})
 		}
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.List(ctx, tenantID, 1, 10)
 
@@ -48,12 +52,14 @@ func TestWorkflowService_List(t *testing.T) {
 
 	t.Run("returns empty list when no workflows", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.List(ctx, tenantID, 1, 10)
 
@@ -64,13 +70,15 @@ func TestWorkflowService_List(t *testing.T) {
 
 	t.Run("returns error when tenant context fails", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 		tenantSetter.SetErr = domain.ErrUnauthorized
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.List(ctx, tenantID, 1, 10)
 
@@ -85,8 +93,10 @@ func TestWorkflowService_GetByID(t *testing.T) {
 
 	t.Run("returns workflow when found", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -99,7 +109,7 @@ func TestWorkflowService_GetByID(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(expected)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.GetByID(ctx, workflowID)
 
@@ -110,12 +120,14 @@ func TestWorkflowService_GetByID(t *testing.T) {
 
 	t.Run("returns error when not found", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.GetByID(ctx, uuid.New())
 
@@ -132,12 +144,14 @@ func TestWorkflowService_Create(t *testing.T) {
 
 	t.Run("creates workflow successfully", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		input := port.CreateWorkflowInput{
 			TenantID:    tenantID,
@@ -158,15 +172,63 @@ func TestWorkflowService_Create(t *testing.T) {
 		assert.True(t, auditService.LogCalled)
 	})
 
+	t.Run("rejects invalid definition", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		validator.Errors = []domain.DefinitionError{{Path: "$.steps[0].config.url", Message: "url is required"}}
+		auditService := mocks.NewMockAuditService()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
+
+		input := port.CreateWorkflowInput{
+			TenantID:   tenantID,
+			Name:       "New Workflow",
+			Definition: json.RawMessage(`{"steps":[{"type":"http"}]}`),
+		}
+
+		result, err := svc.Create(ctx, input)
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidDefinition)
+		var validationErr *domain.DefinitionValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, validator.Errors, validationErr.Errors)
+		assert.False(t, workflowRepo.SaveCalled)
+	})
+
+	t.Run("skips validation for drafts without definition", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		auditService := mocks.NewMockAuditService()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
+
+		_, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "Draft"})
+
+		require.NoError(t, err)
+		assert.False(t, validator.ValidateCalled)
+	})
+
 	t.Run("returns error when save fails", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 		workflowRepo.SaveErr = domain.ErrInternal
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		input := port.CreateWorkflowInput{
 			TenantID: tenantID,
@@ -186,8 +248,10 @@ func TestWorkflowService_Update(t *testing.T) {
 
 	t.Run("updates workflow successfully", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -200,7 +264,7 @@ func TestWorkflowService_Update(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(existing)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		newName := "Updated Name"
 		input := port.UpdateWorkflowInput{
@@ -217,8 +281,10 @@ func TestWorkflowService_Update(t *testing.T) {
 
 	t.Run("updates status to active", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -231,7 +297,7 @@ func TestWorkflowService_Update(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(existing)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		newStatus := domain.WorkflowStatusActive
 		input := port.UpdateWorkflowInput{
@@ -242,16 +308,50 @@ func TestWorkflowService_Update(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, domain.WorkflowStatusActive, result.Status)
+		assert.True(t, validator.ValidateCalled)
+	})
+
+	t.Run("rejects activation with invalid definition", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		validator.Errors = []domain.DefinitionError{{Path: "$.steps", Message: "at least one step is required"}}
+		auditService := mocks.NewMockAuditService()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{
+			ID:         workflowID,
+			TenantID:   tenantID,
+			Name:       "Test Workflow",
+			Definition: json.RawMessage(`{"steps":[]}`),
+			Status:     domain.WorkflowStatusDraft,
+		})
+
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
+
+		newStatus := domain.WorkflowStatusActive
+		result, err := svc.Update(ctx, workflowID, port.UpdateWorkflowInput{Status: &newStatus})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidDefinition)
+		assert.JSONEq(t, `{"steps":[]}`, string(validator.Definition))
+		assert.False(t, workflowRepo.UpdateCalled)
 	})
 
 	t.Run("returns error when workflow not found", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		input := port.UpdateWorkflowInput{}
 
@@ -268,8 +368,10 @@ func TestWorkflowService_Delete(t *testing.T) {
 
 	t.Run("deletes workflow successfully", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -281,7 +383,7 @@ func TestWorkflowService_Delete(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(existing)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		err := svc.Delete(ctx, workflowID)
 
@@ -292,12 +394,14 @@ func TestWorkflowService_Delete(t *testing.T) {
 
 	t.Run("returns error when workflow not found", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		err := svc.Delete(ctx, uuid.New())
 
@@ -313,8 +417,10 @@ func TestWorkflowService_Execute(t *testing.T) {
 
 	t.Run("executes active workflow successfully", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -327,7 +433,7 @@ func TestWorkflowService_Execute(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(workflow)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		input := map[string]interface{}{"key": "value"}
 		result, err := svc.Execute(ctx, workflowID, userID, input)
@@ -340,10 +446,36 @@ func TestWorkflowService_Execute(t *testing.T) {
 		assert.True(t, auditService.LogCalled)
 	})
 
+	t.Run("keeps the outcome of runs that finish before they are marked running", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{ID: workflowID, TenantID: tenantID, Status: domain.WorkflowStatusActive})
+
+		// The worker records the outcome while the API is still starting the run
+		executor.OnExecute = func(executionID uuid.UUID) {
+			require.NoError(t, executionRepo.UpdateStatus(ctx, executionID, domain.ExecutionStatusCompleted, nil))
+		}
+
+		svc := NewWorkflowService(workflowRepo, mocks.NewMockWorkflowVersionRepository(), executionRepo, executor, nil,
+			mocks.NewMockDefinitionValidator(), mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+
+		result, err := svc.Execute(ctx, workflowID, userID, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.ExecutionStatusCompleted, result.Status)
+		require.NotNil(t, result.TemporalWorkflowID)
+		assert.Equal(t, "temporal-workflow-123", *result.TemporalWorkflowID)
+	})
+
 	t.Run("returns error when workflow is not active", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -356,7 +488,7 @@ func TestWorkflowService_Execute(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(workflow)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.Execute(ctx, workflowID, userID, nil)
 
@@ -365,14 +497,86 @@ func TestWorkflowService_Execute(t *testing.T) {
 		assert.Equal(t, domain.ErrWorkflowCannotExecute, err)
 	})
 
+	t.Run("validates and defaults input", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		auditService := mocks.NewMockAuditService()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{
+			ID:       workflowID,
+			TenantID: tenantID,
+			Name:     "Test Workflow",
+			Status:   domain.WorkflowStatusActive,
+			Definition: json.RawMessage(`{
+				"inputs": {
+					"properties": {
+						"host": {"type": "string"},
+						"port": {"type": "integer", "default": 443}
+					},
+					"required": ["host"]
+				},
+				"steps": [{"type": "log"}]
+			}`),
+		})
+
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
+
+		result, err := svc.Execute(ctx, workflowID, userID, map[string]interface{}{"host": "web-1"})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"host": "web-1", "port": 443}`, string(result.Input))
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		auditService := mocks.NewMockAuditService()
+		tenantSetter := mocks.NewMockTenantContextSetter()
+
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{
+			ID:         workflowID,
+			TenantID:   tenantID,
+			Name:       "Test Workflow",
+			Status:     domain.WorkflowStatusActive,
+			Definition: json.RawMessage(`{"inputs": {"properties": {"port": {"type": "integer"}}, "required": ["host"]}, "steps": [{"type": "log"}]}`),
+		})
+
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
+
+		result, err := svc.Execute(ctx, workflowID, userID, map[string]interface{}{"port": "443"})
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		appErr, ok := apperror.GetAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, map[string]string{
+			"host": "host is required",
+			"port": "port must be an integer",
+		}, appErr.Details["fields"])
+		assert.False(t, executionRepo.SaveCalled)
+		assert.False(t, executor.ExecuteCalled)
+	})
+
 	t.Run("returns error when workflow not found", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.Execute(ctx, uuid.New(), userID, nil)
 
@@ -382,9 +586,11 @@ func TestWorkflowService_Execute(t *testing.T) {
 
 	t.Run("returns error when executor fails", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
 		executor.ExecuteErr = domain.ErrInternal
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
@@ -397,7 +603,7 @@ func TestWorkflowService_Execute(t *testing.T) {
 		}
 		workflowRepo.AddWorkflow(workflow)
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.Execute(ctx, workflowID, userID, nil)
 
@@ -406,18 +612,99 @@ func TestWorkflowService_Execute(t *testing.T) {
 	})
 }
 
+func TestWorkflowService_DryRun(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	newService := func() (*WorkflowService, *mocks.MockWorkflowRepository, *mocks.MockWorkflowExecutor, *mocks.MockDefinitionValidator, *mocks.MockExecutionRepository) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		executionRepo := mocks.NewMockExecutionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
+		svc := NewWorkflowService(workflowRepo, mocks.NewMockWorkflowVersionRepository(), executionRepo, executor, nil,
+			validator, mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+		return svc, workflowRepo, executor, validator, executionRepo
+	}
+
+	definition := json.RawMessage(`{
+		"inputs": {"properties": {"host": {"type": "string"}, "port": {"type": "integer", "default": 443}}},
+		"steps": [{"id": "check", "type": "http", "config": {"url": "https://${input.host}:${input.port}/health"}}]
+	}`)
+
+	t.Run("simulates drafts with defaulted input and fixtures", func(t *testing.T) {
+		svc, workflowRepo, executor, _, executionRepo := newService()
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{
+			ID:         workflowID,
+			TenantID:   tenantID,
+			Status:     domain.WorkflowStatusDraft,
+			Definition: definition,
+		})
+		fixtures := map[string]interface{}{"check": map[string]interface{}{"status_code": 503}}
+
+		result, err := svc.DryRun(ctx, workflowID, port.DryRunInput{
+			Input:    map[string]interface{}{"host": "web-1"},
+			Fixtures: fixtures,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "completed", result.Status)
+		input, _ := json.Marshal(executor.DryRunInput)
+		assert.JSONEq(t, `{"host": "web-1", "port": 443}`, string(input))
+		assert.Equal(t, fixtures, executor.Fixtures)
+		assert.False(t, executor.ExecuteCalled)
+		assert.False(t, executionRepo.SaveCalled)
+	})
+
+	t.Run("rejects invalid definitions", func(t *testing.T) {
+		svc, workflowRepo, executor, validator, _ := newService()
+		validator.Errors = []domain.DefinitionError{{Path: "$.steps[0].config.url", Message: "url is required"}}
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{ID: workflowID, TenantID: tenantID, Definition: definition})
+
+		_, err := svc.DryRun(ctx, workflowID, port.DryRunInput{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDefinition)
+		assert.Nil(t, executor.Workflow)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		svc, workflowRepo, executor, _, _ := newService()
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{ID: workflowID, TenantID: tenantID, Definition: definition})
+
+		_, err := svc.DryRun(ctx, workflowID, port.DryRunInput{Input: map[string]interface{}{"port": "443"}})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Nil(t, executor.Workflow)
+	})
+
+	t.Run("returns executor errors", func(t *testing.T) {
+		svc, workflowRepo, executor, _, _ := newService()
+		executor.DryRunErr = domain.ErrDryRunTimedOut
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{ID: workflowID, TenantID: tenantID, Definition: definition})
+
+		_, err := svc.DryRun(ctx, workflowID, port.DryRunInput{})
+
+		assert.ErrorIs(t, err, domain.ErrDryRunTimedOut)
+	})
+}
+
 func TestWorkflowService_ListExecutions(t *testing.T) {
 	ctx := context.Background()
 	workflowID := uuid.New()
 
 	t.Run("returns paginated executions", func(t *testing.T) {
 		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
 		executionRepo := mocks.NewMockExecutionRepository()
 		executor := mocks.NewMockWorkflowExecutor()
+		validator := mocks.NewMockDefinitionValidator()
 		auditService := mocks.NewMockAuditService()
 		tenantSetter := mocks.NewMockTenantContextSetter()
 
-		svc := NewWorkflowService(workflowRepo, executionRepo, executor, auditService, tenantSetter)
+		svc := NewWorkflowService(workflowRepo, versionRepo, executionRepo, executor, nil, validator, auditService, tenantSetter)
 
 		result, err := svc.ListExecutions(ctx, workflowID, 1, 10)
 
@@ -427,3 +714,341 @@ func TestWorkflowService_ListExecutions(t *testing.T) {
 		assert.Equal(t, 10, result.Limit)
 	})
 }
+
+func TestWorkflowService_Versions(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+	userID := uuid.New()
+
+	newService := func() (*WorkflowService, *mocks.MockWorkflowRepository, *mocks.MockWorkflowVersionRepository, *mocks.MockWorkflowExecutor) {
+		workflowRepo := mocks.NewMockWorkflowRepository()
+		versionRepo := mocks.NewMockWorkflowVersionRepository()
+		executor := mocks.NewMockWorkflowExecutor()
+		svc := NewWorkflowService(workflowRepo, versionRepo, mocks.NewMockExecutionRepository(), executor, nil,
+			mocks.NewMockDefinitionValidator(), mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+		return svc, workflowRepo, versionRepo, executor
+	}
+
+	v1 := json.RawMessage(`{"steps":[{"id":"a","type":"log"}]}`)
+	v2 := json.RawMessage(`{"steps":[{"id":"a","type":"log","config":{"message":"hi"}}]}`)
+
+	t.Run("create stores the first version", func(t *testing.T) {
+		svc, _, versionRepo, _ := newService()
+
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1, CreatedBy: &userID})
+
+		require.NoError(t, err)
+		versions := versionRepo.Versions()
+		require.Len(t, versions, 1)
+		assert.Equal(t, int32(1), versions[0].Version)
+		assert.Equal(t, workflow.ID, versions[0].WorkflowID)
+		assert.Equal(t, &userID, versions[0].CreatedBy)
+	})
+
+	t.Run("update publishes a new version only when the definition changes", func(t *testing.T) {
+		svc, _, versionRepo, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+
+		name := "renamed"
+		updated, err := svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Name: &name, Definition: []byte(`{"steps": [{"type": "log", "id": "a"}]}`)})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), updated.Version)
+
+		updated, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2, UpdatedBy: &userID})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), updated.Version)
+
+		versions := versionRepo.Versions()
+		require.Len(t, versions, 2)
+		assert.Equal(t, int32(2), versions[1].Version)
+		assert.JSONEq(t, string(v2), string(versions[1].Definition))
+		assert.Equal(t, &userID, versions[1].CreatedBy)
+	})
+
+	t.Run("first definition of a draft becomes version 1", func(t *testing.T) {
+		svc, _, versionRepo, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "draft"})
+		require.NoError(t, err)
+		assert.Empty(t, versionRepo.Versions())
+
+		updated, err := svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v1})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), updated.Version)
+		require.Len(t, versionRepo.Versions(), 1)
+	})
+
+	t.Run("a failed update leaves no version behind", func(t *testing.T) {
+		svc, workflowRepo, versionRepo, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+		stored := *workflow
+
+		workflowRepo.UpdateErr = errors.New("connection lost")
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+		require.Error(t, err)
+		assert.Len(t, versionRepo.Versions(), 1)
+
+		// The database still holds the workflow as it was
+		workflowRepo.UpdateErr = nil
+		workflowRepo.AddWorkflow(&stored)
+
+		updated, err := svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), updated.Version)
+		versions := versionRepo.Versions()
+		require.Len(t, versions, 2)
+		assert.JSONEq(t, string(v2), string(versions[1].Definition))
+	})
+
+	t.Run("rollback publishes the old definition as a new version", func(t *testing.T) {
+		svc, _, versionRepo, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+		require.NoError(t, err)
+
+		result, err := svc.Rollback(ctx, workflow.ID, 1, &userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), result.Version)
+		assert.JSONEq(t, string(v1), string(result.Definition))
+		latest, err := versionRepo.FindByVersion(ctx, workflow.ID, 3)
+		require.NoError(t, err)
+		require.NotNil(t, latest.RestoredFrom)
+		assert.Equal(t, int32(1), *latest.RestoredFrom)
+	})
+
+	t.Run("comments are kept until the definition changes", func(t *testing.T) {
+		svc, _, _, _ := newService()
+		comments := domain.DefinitionComments{"$.steps[0]": {Head: "# say hello"}}
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1, DefinitionComments: comments})
+		require.NoError(t, err)
+		assert.Equal(t, comments, workflow.DefinitionComments)
+
+		name := "renamed"
+		updated, err := svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, comments, updated.DefinitionComments)
+
+		updated, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+		require.NoError(t, err)
+		assert.Nil(t, updated.DefinitionComments)
+
+		updated, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v1, DefinitionComments: comments})
+		require.NoError(t, err)
+		assert.Equal(t, comments, updated.DefinitionComments)
+
+		result, err := svc.Rollback(ctx, workflow.ID, 2, &userID)
+		require.NoError(t, err)
+		assert.Nil(t, result.DefinitionComments)
+	})
+
+	t.Run("rollback to an unknown version fails", func(t *testing.T) {
+		svc, _, _, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+
+		_, err = svc.Rollback(ctx, workflow.ID, 7, &userID)
+
+		assert.ErrorIs(t, err, domain.ErrWorkflowVersionNotFound)
+	})
+
+	t.Run("diff reports the changed paths", func(t *testing.T) {
+		svc, _, _, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+		require.NoError(t, err)
+
+		changes, err := svc.DiffVersions(ctx, workflow.ID, 1, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.DefinitionChange{
+			{Path: "$.steps[0].config", Op: domain.DefinitionChangeAdded, To: map[string]interface{}{"message": "hi"}},
+		}, changes)
+	})
+
+	t.Run("lists versions newest first", func(t *testing.T) {
+		svc, _, _, _ := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2})
+		require.NoError(t, err)
+
+		result, err := svc.ListVersions(ctx, workflow.ID, 1, 10)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Total)
+		require.Len(t, result.Versions, 2)
+		assert.Equal(t, int32(2), result.Versions[0].Version)
+	})
+
+	t.Run("executions are pinned to the version that ran", func(t *testing.T) {
+		svc, workflowRepo, _, executor := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: v1})
+		require.NoError(t, err)
+		active := domain.WorkflowStatusActive
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Definition: v2, Status: &active})
+		require.NoError(t, err)
+
+		latest, err := svc.Execute(ctx, workflow.ID, userID.String(), nil)
+		require.NoError(t, err)
+		require.NotNil(t, latest.WorkflowVersion)
+		assert.Equal(t, int32(2), *latest.WorkflowVersion)
+
+		pinned, err := svc.ExecuteVersion(ctx, workflow.ID, 1, userID.String(), nil)
+		require.NoError(t, err)
+		require.NotNil(t, pinned.WorkflowVersion)
+		assert.Equal(t, int32(1), *pinned.WorkflowVersion)
+		assert.JSONEq(t, string(v1), string(executor.Workflow.Definition))
+
+		stored, err := workflowRepo.FindByID(ctx, workflow.ID)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(v2), string(stored.Definition), "pinned runs must not change the workflow")
+	})
+
+	t.Run("executing an unknown version fails", func(t *testing.T) {
+		svc, workflowRepo, _, executor := newService()
+		workflowID := uuid.New()
+		workflowRepo.AddWorkflow(&domain.Workflow{ID: workflowID, TenantID: tenantID, Status: domain.WorkflowStatusActive, Version: 1})
+
+		_, err := svc.ExecuteVersion(ctx, workflowID, 3, userID.String(), nil)
+
+		assert.ErrorIs(t, err, domain.ErrWorkflowVersionNotFound)
+		assert.False(t, executor.ExecuteCalled)
+	})
+}
+
+func TestWorkflowService_Schedule(t *testing.T) {
+	ctx := context.Background()
+	tenantID := uuid.New()
+
+	newService := func() (*WorkflowService, *mocks.MockWorkflowScheduler) {
+		scheduler := mocks.NewMockWorkflowScheduler()
+		svc := NewWorkflowService(mocks.NewMockWorkflowRepository(), mocks.NewMockWorkflowVersionRepository(),
+			mocks.NewMockExecutionRepository(), mocks.NewMockWorkflowExecutor(), scheduler,
+			mocks.NewMockDefinitionValidator(), mocks.NewMockAuditService(), mocks.NewMockTenantContextSetter())
+		return svc, scheduler
+	}
+
+	definition := json.RawMessage(`{"steps":[{"id":"a","type":"log"}]}`)
+	cron := "*/5 * * * *"
+	active := domain.WorkflowStatusActive
+	inactive := domain.WorkflowStatusInactive
+
+	t.Run("drafts get a paused schedule", func(t *testing.T) {
+		svc, scheduler := newService()
+
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+
+		require.NoError(t, err)
+		assert.Contains(t, scheduler.Schedules, workflow.ID)
+		assert.True(t, scheduler.Paused[workflow.ID])
+	})
+
+	t.Run("activating and deactivating resumes and pauses the schedule", func(t *testing.T) {
+		svc, scheduler := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+		require.NoError(t, err)
+
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Status: &active})
+		require.NoError(t, err)
+		assert.False(t, scheduler.Paused[workflow.ID])
+
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Status: &inactive})
+		require.NoError(t, err)
+		assert.True(t, scheduler.Paused[workflow.ID])
+	})
+
+	t.Run("updates schedule options", func(t *testing.T) {
+		svc, scheduler := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+		require.NoError(t, err)
+
+		opts := &domain.ScheduleOptions{Timezone: "Europe/Berlin", Jitter: "30s", Overlap: domain.ScheduleOverlapBufferOne}
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{ScheduleOptions: opts})
+
+		require.NoError(t, err)
+		assert.Equal(t, opts, scheduler.Schedules[workflow.ID].ScheduleOptions)
+	})
+
+	t.Run("clearing the schedule removes it", func(t *testing.T) {
+		svc, scheduler := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+		require.NoError(t, err)
+
+		empty := ""
+		updated, err := svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Schedule: &empty})
+
+		require.NoError(t, err)
+		assert.Nil(t, updated.Schedule)
+		assert.NotContains(t, scheduler.Schedules, workflow.ID)
+	})
+
+	t.Run("deleting the workflow deletes the schedule", func(t *testing.T) {
+		svc, scheduler := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.Delete(ctx, workflow.ID))
+
+		assert.NotContains(t, scheduler.Schedules, workflow.ID)
+	})
+
+	t.Run("unscheduled workflows are not synced", func(t *testing.T) {
+		svc, scheduler := newService()
+		scheduler.SyncErr = errors.New("should not be called")
+
+		_, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		svc, scheduler := newService()
+		badCron := "every five minutes"
+
+		_, err := svc.Create(ctx, port.CreateWorkflowInput{
+			TenantID:        tenantID,
+			Name:            "wf",
+			Definition:      definition,
+			Schedule:        &badCron,
+			ScheduleOptions: &domain.ScheduleOptions{Timezone: "Mars/Olympus", Jitter: "-1s", Overlap: "queue"},
+		})
+
+		require.ErrorIs(t, err, domain.ErrInvalidSchedule)
+		appErr, ok := apperror.GetAppError(err)
+		require.True(t, ok)
+		fields := appErr.Details["fields"].(map[string]string)
+		assert.Contains(t, fields, "schedule")
+		assert.Contains(t, fields, "schedule_options.timezone")
+		assert.Contains(t, fields, "schedule_options.jitter")
+		assert.Contains(t, fields, "schedule_options.overlap")
+		assert.Empty(t, scheduler.Schedules)
+	})
+
+	t.Run("rejects required inputs without defaults", func(t *testing.T) {
+		svc, _ := newService()
+		withInputs := json.RawMessage(`{"inputs":{"properties":{"host":{"type":"string"}},"required":["host"]},"steps":[{"type":"log"}]}`)
+
+		_, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: withInputs, Schedule: &cron})
+
+		require.ErrorIs(t, err, domain.ErrInvalidSchedule)
+		appErr, _ := apperror.GetAppError(err)
+		assert.Equal(t, map[string]string{"input.host": "scheduled runs have no input, so host needs a default"}, appErr.Details["fields"])
+	})
+
+	t.Run("returns sync errors", func(t *testing.T) {
+		svc, scheduler := newService()
+		workflow, err := svc.Create(ctx, port.CreateWorkflowInput{TenantID: tenantID, Name: "wf", Definition: definition, Schedule: &cron})
+		require.NoError(t, err)
+		scheduler.SyncErr = errors.New("temporal unavailable")
+
+		_, err = svc.Update(ctx, workflow.ID, port.UpdateWorkflowInput{Status: &active})
+
+		assert.ErrorContains(t, err, "temporal unavailable")
+	})
+}
@@ -31,26 +31,27 @@ INSERT INTO alert_rules (
     condition_type, condition_config,
     severity, alert_title_template, alert_message_template,
     trigger_workflow_id, trigger_input_template,
-    cooldown_seconds, created_by
+    cooldown_seconds, created_by, trigger_workflow_version
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-RETURNING id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+RETURNING id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version
 `
 
 type CreateAlertRuleParams struct {
-	TenantID             uuid.UUID       `db:"tenant_id" json:"tenant_id"`
-	Name                 string          `db:"name" json:"name"`
-	Description          *string         `db:"description" json:"description"`
-	Enabled              bool            `db:"enabled" json:"enabled"`
-	ConditionType        string          `db:"condition_type" json:"condition_type"`
-	ConditionConfig      json.RawMessage `db:"condition_config" json:"condition_config"`
-	Severity             string          `db:"severity" json:"severity"`
-	AlertTitleTemplate   string          `db:"alert_title_template" json:"alert_title_template"`
-	AlertMessageTemplate *string         `db:"alert_message_template" json:"alert_message_template"`
-	TriggerWorkflowID    pgtype.UUID     `db:"trigger_workflow_id" json:"trigger_workflow_id"`
-	TriggerInputTemplate []byte          `db:"trigger_input_template" json:"trigger_input_template"`
-	CooldownSeconds      int32           `db:"cooldown_seconds" json:"cooldown_seconds"`
-	CreatedBy            pgtype.UUID     `db:"created_by" json:"created_by"`
+	TenantID               uuid.UUID       `db:"tenant_id" json:"tenant_id"`
+	Name                   string          `db:"name" json:"name"`
+	Description            *string         `db:"description" json:"description"`
+	Enabled                bool            `db:"enabled" json:"enabled"`
+	ConditionType          string          `db:"condition_type" json:"condition_type"`
+	ConditionConfig        json.RawMessage `db:"condition_config" json:"condition_config"`
+	Severity               string          `db:"severity" json:"severity"`
+	AlertTitleTemplate     string          `db:"alert_title_template" json:"alert_title_template"`
+	AlertMessageTemplate   *string         `db:"alert_message_template" json:"alert_message_template"`
+	TriggerWorkflowID      pgtype.UUID     `db:"trigger_workflow_id" json:"trigger_workflow_id"`
+	TriggerInputTemplate   []byte          `db:"trigger_input_template" json:"trigger_input_template"`
+	CooldownSeconds        int32           `db:"cooldown_seconds" json:"cooldown_seconds"`
+	CreatedBy              pgtype.UUID     `db:"created_by" json:"created_by"`
+	TriggerWorkflowVersion *int32          `db:"trigger_workflow_version" json:"trigger_workflow_version"`
 }
 
 func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (AlertRule, error) {
@@ -68,6 +69,7 @@ func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams
 		arg.TriggerInputTemplate,
 		arg.CooldownSeconds,
 		arg.CreatedBy,
+		arg.TriggerWorkflowVersion,
 	)
 	var i AlertRule
 	err := row.Scan(
@@ -88,6 +90,7 @@ func (q *Queries) CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TriggerWorkflowVersion,
 	)
 	return i, err
 }
@@ -108,7 +111,7 @@ func (q *Queries) DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams
 }
 
 const getAlertRule = `-- name: GetAlertRule :one
-SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at FROM alert_rules
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
 WHERE id = $1 AND tenant_id = $2
 `
 
@@ -138,12 +141,51 @@ func (q *Queries) GetAlertRule(ctx context.Context, arg GetAlertRuleParams) (Ale
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TriggerWorkflowVersion,
+	)
+	return i, err
+}
+
+const getAlertRuleByName = `-- name: GetAlertRuleByName :one
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
+WHERE tenant_id = $1 AND name = $2
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetAlertRuleByNameParams struct {
+	TenantID uuid.UUID `db:"tenant_id" json:"tenant_id"`
+	Name     string    `db:"name" json:"name"`
+}
+
+func (q *Queries) GetAlertRuleByName(ctx context.Context, arg GetAlertRuleByNameParams) (AlertRule, error) {
+	row := q.db.QueryRow(ctx, getAlertRuleByName, arg.TenantID, arg.Name)
+	var i AlertRule
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Description,
+		&i.Enabled,
+		&i.ConditionType,
+		&i.ConditionConfig,
+		&i.Severity,
+		&i.AlertTitleTemplate,
+		&i.AlertMessageTemplate,
+		&i.TriggerWorkflowID,
+		&i.TriggerInputTemplate,
+		&i.CooldownSeconds,
+		&i.LastTriggeredAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TriggerWorkflowVersion,
 	)
 	return i, err
 }
 
 const getAlertRulesForMetric = `-- name: GetAlertRulesForMetric :many
-SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at FROM alert_rules
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
 WHERE tenant_id = $1
     AND enabled = true
     AND condition_type = 'metric_threshold'
@@ -183,6 +225,7 @@ func (q *Queries) GetAlertRulesForMetric(ctx context.Context, arg GetAlertRulesF
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TriggerWorkflowVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -195,7 +238,7 @@ func (q *Queries) GetAlertRulesForMetric(ctx context.Context, arg GetAlertRulesF
 }
 
 const listAlertRules = `-- name: ListAlertRules :many
-SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at FROM alert_rules
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
 WHERE tenant_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -234,6 +277,7 @@ func (q *Queries) ListAlertRules(ctx context.Context, arg ListAlertRulesParams)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TriggerWorkflowVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -246,7 +290,7 @@ func (q *Queries) ListAlertRules(ctx context.Context, arg ListAlertRulesParams)
 }
 
 const listAlertRulesByConditionType = `-- name: ListAlertRulesByConditionType :many
-SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at FROM alert_rules
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
 WHERE tenant_id = $1 AND condition_type = $2 AND enabled = true
 `
 
@@ -282,6 +326,57 @@ func (q *Queries) ListAlertRulesByConditionType(ctx context.Context, arg ListAle
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TriggerWorkflowVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAlertRulesByWorkflow = `-- name: ListAlertRulesByWorkflow :many
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
+WHERE tenant_id = $1 AND trigger_workflow_id = $2
+ORDER BY name
+`
+
+type ListAlertRulesByWorkflowParams struct {
+	TenantID          uuid.UUID   `db:"tenant_id" json:"tenant_id"`
+	TriggerWorkflowID pgtype.UUID `db:"trigger_workflow_id" json:"trigger_workflow_id"`
+}
+
+func (q *Queries) ListAlertRulesByWorkflow(ctx context.Context, arg ListAlertRulesByWorkflowParams) ([]AlertRule, error) {
+	rows, err := q.db.Query(ctx, listAlertRulesByWorkflow, arg.TenantID, arg.TriggerWorkflowID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AlertRule{}
+	for rows.Next() {
+		var i AlertRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.Name,
+			&i.Description,
+			&i.Enabled,
+			&i.ConditionType,
+			&i.ConditionConfig,
+			&i.Severity,
+			&i.AlertTitleTemplate,
+			&i.AlertMessageTemplate,
+			&i.TriggerWorkflowID,
+			&i.TriggerInputTemplate,
+			&i.CooldownSeconds,
+			&i.LastTriggeredAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TriggerWorkflowVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -294,7 +389,7 @@ func (q *Queries) ListAlertRulesByConditionType(ctx context.Context, arg ListAle
 }
 
 const listEnabledAlertRules = `-- name: ListEnabledAlertRules :many
-SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at FROM alert_rules
+SELECT id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version FROM alert_rules
 WHERE tenant_id = $1 AND enabled = true
 ORDER BY name
 `
@@ -326,6 +421,7 @@ func (q *Queries) ListEnabledAlertRules(ctx context.Context, tenantID uuid.UUID)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TriggerWorkflowVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -350,25 +446,27 @@ SET
     alert_message_template = COALESCE($10, alert_message_template),
     trigger_workflow_id = $11,
     trigger_input_template = $12,
-    cooldown_seconds = COALESCE($13, cooldown_seconds)
+    cooldown_seconds = COALESCE($13, cooldown_seconds),
+    trigger_workflow_version = $14
 WHERE id = $1 AND tenant_id = $2
-RETURNING id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at
+RETURNING id, tenant_id, name, description, enabled, condition_type, condition_config, severity, alert_title_template, alert_message_template, trigger_workflow_id, trigger_input_template, cooldown_seconds, last_triggered_at, created_by, created_at, updated_at, trigger_workflow_version
 `
 
 type UpdateAlertRuleParams struct {
-	ID                   uuid.UUID       `db:"id" json:"id"`
-	TenantID             uuid.UUID       `db:"tenant_id" json:"tenant_id"`
-	Name                 string          `db:"name" json:"name"`
-	Description          *string         `db:"description" json:"description"`
-	Enabled              bool            `db:"enabled" json:"enabled"`
-	ConditionType        string          `db:"condition_type" json:"condition_type"`
-	ConditionConfig      json.RawMessage `db:"condition_config" json:"condition_config"`
-	Severity             string          `db:"severity" json:"severity"`
-	AlertTitleTemplate   string          `db:"alert_title_template" json:"alert_title_template"`
-	AlertMessageTemplate *string         `db:"alert_message_template" json:"alert_message_template"`
-	TriggerWorkflowID    pgtype.UUID     `db:"trigger_workflow_id" json:"trigger_workflow_id"`
-	TriggerInputTemplate []byte          `db:"trigger_input_template" json:"trigger_input_template"`
-	CooldownSeconds      int32           `db:"cooldown_seconds" json:"cooldown_seconds"`
+	ID                     uuid.UUID       `db:"id" json:"id"`
+	TenantID               uuid.UUID       `db:"tenant_id" json:"tenant_id"`
+	Name                   string          `db:"name" json:"name"`
+	Description            *string         `db:"description" json:"description"`
+	Enabled                bool            `db:"enabled" json:"enabled"`
+	ConditionType          string          `db:"condition_type" json:"condition_type"`
+	ConditionConfig        json.RawMessage `db:"condition_config" json:"condition_config"`
+	Severity               string          `db:"severity" json:"severity"`
+	AlertTitleTemplate     string          `db:"alert_title_template" json:"alert_title_template"`
+	AlertMessageTemplate   *string         `db:"alert_message_template" json:"alert_message_template"`
+	TriggerWorkflowID      pgtype.UUID     `db:"trigger_workflow_id" json:"trigger_workflow_id"`
+	TriggerInputTemplate   []byte          `db:"trigger_input_template" json:"trigger_input_template"`
+	CooldownSeconds        int32           `db:"cooldown_seconds" json:"cooldown_seconds"`
+	TriggerWorkflowVersion *int32          `db:"trigger_workflow_version" json:"trigger_workflow_version"`
 }
 
 func (q *Queries) UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (AlertRule, error) {
@@ -386,6 +484,7 @@ func (q *Queries) UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams
 		arg.TriggerWorkflowID,
 		arg.TriggerInputTemplate,
 		arg.CooldownSeconds,
+		arg.TriggerWorkflowVersion,
 	)
 	var i AlertRule
 	err := row.Scan(
@@ -406,6 +505,7 @@ func (q *Queries) UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TriggerWorkflowVersion,
 	)
 	return i, err
 }
@@ -17,7 +17,7 @@ const completeExecution = `-- name: CompleteExecution :one
 UPDATE executions
 SET status = $2, output = $3, completed_at = NOW()
 WHERE id = $1
-RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by
+RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of
 `
 
 type CompleteExecutionParams struct {
@@ -44,6 +44,9 @@ func (q *Queries) CompleteExecution(ctx context.Context, arg CompleteExecutionPa
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
 	)
 	return i, err
 }
@@ -76,28 +79,36 @@ func (q *Queries) CountExecutionsByStatus(ctx context.Context, arg CountExecutio
 }
 
 const createExecution = `-- name: CreateExecution :one
-INSERT INTO executions (tenant_id, workflow_id, temporal_workflow_id, status, input, triggered_by)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by
+INSERT INTO executions (id, tenant_id, workflow_id, temporal_workflow_id, status, input, triggered_by, parent_execution_id, workflow_version, retry_of)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of
 `
 
 type CreateExecutionParams struct {
-	TenantID           uuid.UUID `db:"tenant_id" json:"tenant_id"`
-	WorkflowID         uuid.UUID `db:"workflow_id" json:"workflow_id"`
-	TemporalWorkflowID *string   `db:"temporal_workflow_id" json:"temporal_workflow_id"`
-	Status             string    `db:"status" json:"status"`
-	Input              []byte    `db:"input" json:"input"`
-	TriggeredBy        *string   `db:"triggered_by" json:"triggered_by"`
+	ID                 uuid.UUID   `db:"id" json:"id"`
+	TenantID           uuid.UUID   `db:"tenant_id" json:"tenant_id"`
+	WorkflowID         uuid.UUID   `db:"workflow_id" json:"workflow_id"`
+	TemporalWorkflowID *string     `db:"temporal_workflow_id" json:"temporal_workflow_id"`
+	Status             string      `db:"status" json:"status"`
+	Input              []byte      `db:"input" json:"input"`
+	TriggeredBy        *string     `db:"triggered_by" json:"triggered_by"`
+	ParentExecutionID  pgtype.UUID `db:"parent_execution_id" json:"parent_execution_id"`
+	WorkflowVersion    *int32      `db:"workflow_version" json:"workflow_version"`
+	RetryOf            pgtype.UUID `db:"retry_of" json:"retry_of"`
 }
 
 func (q *Queries) CreateExecution(ctx context.Context, arg CreateExecutionParams) (Execution, error) {
 	row := q.db.QueryRow(ctx, createExecution,
+		arg.ID,
 		arg.TenantID,
 		arg.WorkflowID,
 		arg.TemporalWorkflowID,
 		arg.Status,
 		arg.Input,
 		arg.TriggeredBy,
+		arg.ParentExecutionID,
+		arg.WorkflowVersion,
+		arg.RetryOf,
 	)
 	var i Execution
 	err := row.Scan(
@@ -115,6 +126,9 @@ func (q *Queries) CreateExecution(ctx context.Context, arg CreateExecutionParams
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
 	)
 	return i, err
 }
@@ -123,7 +137,7 @@ const failExecution = `-- name: FailExecution :one
 UPDATE executions
 SET status = 'failed', error = $2, completed_at = NOW()
 WHERE id = $1
-RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by
+RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of
 `
 
 type FailExecutionParams struct {
@@ -149,12 +163,15 @@ func (q *Queries) FailExecution(ctx context.Context, arg FailExecutionParams) (E
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
 	)
 	return i, err
 }
 
 const getExecution = `-- name: GetExecution :one
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions WHERE id = $1
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions WHERE id = $1
 `
 
 func (q *Queries) GetExecution(ctx context.Context, id uuid.UUID) (Execution, error) {
@@ -175,12 +192,15 @@ func (q *Queries) GetExecution(ctx context.Context, id uuid.UUID) (Execution, er
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
 	)
 	return i, err
 }
 
 const getExecutionByTemporalID = `-- name: GetExecutionByTemporalID :one
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions WHERE temporal_workflow_id = $1
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions WHERE temporal_workflow_id = $1
 `
 
 func (q *Queries) GetExecutionByTemporalID(ctx context.Context, temporalWorkflowID *string) (Execution, error) {
@@ -201,6 +221,9 @@ func (q *Queries) GetExecutionByTemporalID(ctx context.Context, temporalWorkflow
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
 	)
 	return i, err
 }
@@ -245,8 +268,52 @@ func (q *Queries) GetExecutionStats(ctx context.Context, arg GetExecutionStatsPa
 	return i, err
 }
 
+const listChildExecutions = `-- name: ListChildExecutions :many
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions
+WHERE parent_execution_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListChildExecutions(ctx context.Context, parentExecutionID pgtype.UUID) ([]Execution, error) {
+	rows, err := q.db.Query(ctx, listChildExecutions, parentExecutionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Execution{}
+	for rows.Next() {
+		var i Execution
+		if err := rows.Scan(
+			&i.ID,
+			&i.TenantID,
+			&i.WorkflowID,
+			&i.TemporalWorkflowID,
+			&i.TemporalRunID,
+			&i.Status,
+			&i.Input,
+			&i.Output,
+			&i.Error,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.TriggeredBy,
+			&i.ParentExecutionID,
+			&i.WorkflowVersion,
+			&i.RetryOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listExecutions = `-- name: ListExecutions :many
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions
 WHERE tenant_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -282,6 +349,9 @@ func (q *Queries) ListExecutions(ctx context.Context, arg ListExecutionsParams)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.TriggeredBy,
+			&i.ParentExecutionID,
+			&i.WorkflowVersion,
+			&i.RetryOf,
 		); err != nil {
 			return nil, err
 		}
@@ -294,7 +364,7 @@ func (q *Queries) ListExecutions(ctx context.Context, arg ListExecutionsParams)
 }
 
 const listExecutionsByStatus = `-- name: ListExecutionsByStatus :many
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions
 WHERE tenant_id = $1 AND status = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -336,6 +406,9 @@ func (q *Queries) ListExecutionsByStatus(ctx context.Context, arg ListExecutions
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.TriggeredBy,
+			&i.ParentExecutionID,
+			&i.WorkflowVersion,
+			&i.RetryOf,
 		); err != nil {
 			return nil, err
 		}
@@ -348,7 +421,7 @@ func (q *Queries) ListExecutionsByStatus(ctx context.Context, arg ListExecutions
 }
 
 const listExecutionsByWorkflow = `-- name: ListExecutionsByWorkflow :many
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions
 WHERE workflow_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -384,6 +457,9 @@ func (q *Queries) ListExecutionsByWorkflow(ctx context.Context, arg ListExecutio
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.TriggeredBy,
+			&i.ParentExecutionID,
+			&i.WorkflowVersion,
+			&i.RetryOf,
 		); err != nil {
 			return nil, err
 		}
@@ -396,7 +472,7 @@ func (q *Queries) ListExecutionsByWorkflow(ctx context.Context, arg ListExecutio
 }
 
 const listRecentExecutions = `-- name: ListRecentExecutions :many
-SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by FROM executions
+SELECT id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of FROM executions
 WHERE tenant_id = $1 AND created_at > $2
 ORDER BY created_at DESC
 LIMIT $3
@@ -432,6 +508,9 @@ func (q *Queries) ListRecentExecutions(ctx context.Context, arg ListRecentExecut
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.TriggeredBy,
+			&i.ParentExecutionID,
+			&i.WorkflowVersion,
+			&i.RetryOf,
 		); err != nil {
 			return nil, err
 		}
@@ -443,6 +522,73 @@ func (q *Queries) ListRecentExecutions(ctx context.Context, arg ListRecentExecut
 	return items, nil
 }
 
+const markExecutionRunning = `-- name: MarkExecutionRunning :one
+UPDATE executions
+SET temporal_workflow_id = $2, temporal_run_id = $3,
+    status = CASE WHEN status = 'pending' THEN 'running' ELSE status END,
+    started_at = COALESCE(started_at, NOW())
+WHERE id = $1
+RETURNING id, tenant_id, workflow_id, temporal_workflow_id, temporal_run_id, status, input, output, error, started_at, completed_at, created_by, created_at, triggered_by, parent_execution_id, workflow_version, retry_of
+`
+
+type MarkExecutionRunningParams struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	TemporalWorkflowID *string   `db:"temporal_workflow_id" json:"temporal_workflow_id"`
+	TemporalRunID      *string   `db:"temporal_run_id" json:"temporal_run_id"`
+}
+
+func (q *Queries) MarkExecutionRunning(ctx context.Context, arg MarkExecutionRunningParams) (Execution, error) {
+	row := q.db.QueryRow(ctx, markExecutionRunning, arg.ID, arg.TemporalWorkflowID, arg.TemporalRunID)
+	var i Execution
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.WorkflowID,
+		&i.TemporalWorkflowID,
+		&i.TemporalRunID,
+		&i.Status,
+		&i.Input,
+		&i.Output,
+		&i.Error,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.TriggeredBy,
+		&i.ParentExecutionID,
+		&i.WorkflowVersion,
+		&i.RetryOf,
+	)
+	return i, err
+}
+
+const updateExecution = `-- name: UpdateExecution :exec
+UPDATE executions
+SET status = $2, output = $3, error = $4, started_at = $5, completed_at = $6
+WHERE id = $1
+`
+
+type UpdateExecutionParams struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	Status      string             `db:"status" json:"status"`
+	Output      []byte             `db:"output" json:"output"`
+	Error       *string            `db:"error" json:"error"`
+	StartedAt   pgtype.Timestamptz `db:"started_at" json:"started_at"`
+	CompletedAt pgtype.Timestamptz `db:"completed_at" json:"completed_at"`
+}
+
+func (q *Queries) UpdateExecution(ctx context.Context, arg UpdateExecutionParams) error {
+	_, err := q.db.Exec(ctx, updateExecution,
+		arg.ID,
+		arg.Status,
+		arg.Output,
+		arg.Error,
+		arg.StartedAt,
+		arg.CompletedAt,
+	)
+	return err
+}
+
 const updateExecutionRunID = `-- name: UpdateExecutionRunID :exec
 UPDATE executions
 SET temporal_run_id = $2, status = $3, started_at = $4
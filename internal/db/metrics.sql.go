@@ -159,6 +159,35 @@ func (q *Queries) GetLatestMetric(ctx context.Context, arg GetLatestMetricParams
 	return i, err
 }
 
+const getLatestMetricByLabels = `-- name: GetLatestMetricByLabels :one
+SELECT id, tenant_id, name, value, labels, source, timestamp, created_at FROM metrics
+WHERE tenant_id = $1 AND name = $2 AND labels @> $3
+ORDER BY timestamp DESC
+LIMIT 1
+`
+
+type GetLatestMetricByLabelsParams struct {
+	TenantID uuid.UUID `db:"tenant_id" json:"tenant_id"`
+	Name     string    `db:"name" json:"name"`
+	Labels   []byte    `db:"labels" json:"labels"`
+}
+
+func (q *Queries) GetLatestMetricByLabels(ctx context.Context, arg GetLatestMetricByLabelsParams) (Metric, error) {
+	row := q.db.QueryRow(ctx, getLatestMetricByLabels, arg.TenantID, arg.Name, arg.Labels)
+	var i Metric
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Value,
+		&i.Labels,
+		&i.Source,
+		&i.Timestamp,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getMetricDefinition = `-- name: GetMetricDefinition :one
 SELECT id, tenant_id, name, display_name, description, unit, type, aggregation, alert_threshold, created_at, updated_at, retention_days FROM metric_definitions
 WHERE tenant_id = $1 AND name = $2
@@ -350,6 +379,65 @@ func (q *Queries) GetMetricsAggregate(ctx context.Context, arg GetMetricsAggrega
 	return i, err
 }
 
+const getMetricsAggregateWithLabels = `-- name: GetMetricsAggregateWithLabels :one
+SELECT
+    COUNT(*) as count,
+    AVG(value) as avg_value,
+    MIN(value) as min_value,
+    MAX(value) as max_value,
+    SUM(value) as sum_value,
+    approx_percentile(0.50, percentile_agg(value)) as p50,
+    approx_percentile(0.95, percentile_agg(value)) as p95,
+    approx_percentile(0.99, percentile_agg(value)) as p99
+FROM metrics
+WHERE tenant_id = $1
+    AND name = $2
+    AND labels @> $3
+    AND timestamp >= $4
+    AND timestamp <= $5
+`
+
+type GetMetricsAggregateWithLabelsParams struct {
+	TenantID    uuid.UUID `db:"tenant_id" json:"tenant_id"`
+	Name        string    `db:"name" json:"name"`
+	Labels      []byte    `db:"labels" json:"labels"`
+	Timestamp   time.Time `db:"timestamp" json:"timestamp"`
+	Timestamp_2 time.Time `db:"timestamp_2" json:"timestamp_2"`
+}
+
+type GetMetricsAggregateWithLabelsRow struct {
+	Count    int64       `db:"count" json:"count"`
+	AvgValue float64     `db:"avg_value" json:"avg_value"`
+	MinValue interface{} `db:"min_value" json:"min_value"`
+	MaxValue interface{} `db:"max_value" json:"max_value"`
+	SumValue int64       `db:"sum_value" json:"sum_value"`
+	P50      interface{} `db:"p50" json:"p50"`
+	P95      interface{} `db:"p95" json:"p95"`
+	P99      interface{} `db:"p99" json:"p99"`
+}
+
+func (q *Queries) GetMetricsAggregateWithLabels(ctx context.Context, arg GetMetricsAggregateWithLabelsParams) (GetMetricsAggregateWithLabelsRow, error) {
+	row := q.db.QueryRow(ctx, getMetricsAggregateWithLabels,
+		arg.TenantID,
+		arg.Name,
+		arg.Labels,
+		arg.Timestamp,
+		arg.Timestamp_2,
+	)
+	var i GetMetricsAggregateWithLabelsRow
+	err := row.Scan(
+		&i.Count,
+		&i.AvgValue,
+		&i.MinValue,
+		&i.MaxValue,
+		&i.SumValue,
+		&i.P50,
+		&i.P95,
+		&i.P99,
+	)
+	return i, err
+}
+
 const getMetricsAggregateWithPercentiles = `-- name: GetMetricsAggregateWithPercentiles :one
 SELECT
     COUNT(*) as count,
@@ -34,23 +34,24 @@ type Alert struct {
 }
 
 type AlertRule struct {
-	ID                   uuid.UUID          `db:"id" json:"id"`
-	TenantID             uuid.UUID          `db:"tenant_id" json:"tenant_id"`
-	Name                 string             `db:"name" json:"name"`
-	Description          *string            `db:"description" json:"description"`
-	Enabled              bool               `db:"enabled" json:"enabled"`
-	ConditionType        string             `db:"condition_type" json:"condition_type"`
-	ConditionConfig      json.RawMessage    `db:"condition_config" json:"condition_config"`
-	Severity             string             `db:"severity" json:"severity"`
-	AlertTitleTemplate   string             `db:"alert_title_template" json:"alert_title_template"`
-	AlertMessageTemplate *string            `db:"alert_message_template" json:"alert_message_template"`
-	TriggerWorkflowID    pgtype.UUID        `db:"trigger_workflow_id" json:"trigger_workflow_id"`
-	TriggerInputTemplate []byte             `db:"trigger_input_template" json:"trigger_input_template"`
-	CooldownSeconds      int32              `db:"cooldown_seconds" json:"cooldown_seconds"`
-	LastTriggeredAt      pgtype.Timestamptz `db:"last_triggered_at" json:"last_triggered_at"`
-	CreatedBy            pgtype.UUID        `db:"created_by" json:"created_by"`
-	CreatedAt            time.Time          `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time          `db:"updated_at" json:"updated_at"`
+	ID                     uuid.UUID          `db:"id" json:"id"`
+	TenantID               uuid.UUID          `db:"tenant_id" json:"tenant_id"`
+	Name                   string             `db:"name" json:"name"`
+	Description            *string            `db:"description" json:"description"`
+	Enabled                bool               `db:"enabled" json:"enabled"`
+	ConditionType          string             `db:"condition_type" json:"condition_type"`
+	ConditionConfig        json.RawMessage    `db:"condition_config" json:"condition_config"`
+	Severity               string             `db:"severity" json:"severity"`
+	AlertTitleTemplate     string             `db:"alert_title_template" json:"alert_title_template"`
+	AlertMessageTemplate   *string            `db:"alert_message_template" json:"alert_message_template"`
+	TriggerWorkflowID      pgtype.UUID        `db:"trigger_workflow_id" json:"trigger_workflow_id"`
+	TriggerInputTemplate   []byte             `db:"trigger_input_template" json:"trigger_input_template"`
+	CooldownSeconds        int32              `db:"cooldown_seconds" json:"cooldown_seconds"`
+	LastTriggeredAt        pgtype.Timestamptz `db:"last_triggered_at" json:"last_triggered_at"`
+	CreatedBy              pgtype.UUID        `db:"created_by" json:"created_by"`
+	CreatedAt              time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt              time.Time          `db:"updated_at" json:"updated_at"`
+	TriggerWorkflowVersion *int32             `db:"trigger_workflow_version" json:"trigger_workflow_version"`
 }
 
 type AuditLog struct {
@@ -83,6 +84,26 @@ type Execution struct {
 	CreatedBy          pgtype.UUID        `db:"created_by" json:"created_by"`
 	CreatedAt          time.Time          `db:"created_at" json:"created_at"`
 	TriggeredBy        *string            `db:"triggered_by" json:"triggered_by"`
+	ParentExecutionID  pgtype.UUID        `db:"parent_execution_id" json:"parent_execution_id"`
+	WorkflowVersion    *int32             `db:"workflow_version" json:"workflow_version"`
+	RetryOf            pgtype.UUID        `db:"retry_of" json:"retry_of"`
+}
+
+type ExecutionStep struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	TenantID    uuid.UUID          `db:"tenant_id" json:"tenant_id"`
+	ExecutionID uuid.UUID          `db:"execution_id" json:"execution_id"`
+	Position    int32              `db:"position" json:"position"`
+	StepID      string             `db:"step_id" json:"step_id"`
+	StepName    *string            `db:"step_name" json:"step_name"`
+	StepType    string             `db:"step_type" json:"step_type"`
+	Status      string             `db:"status" json:"status"`
+	Attempts    int32              `db:"attempts" json:"attempts"`
+	Output      []byte             `db:"output" json:"output"`
+	Error       *string            `db:"error" json:"error"`
+	StartedAt   time.Time          `db:"started_at" json:"started_at"`
+	CompletedAt pgtype.Timestamptz `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time          `db:"created_at" json:"created_at"`
 }
 
 type Metric struct {
@@ -136,6 +157,18 @@ type MetricsHourly struct {
 	P99      interface{} `db:"p99" json:"p99"`
 }
 
+type Secret struct {
+	ID             uuid.UUID   `db:"id" json:"id"`
+	TenantID       uuid.UUID   `db:"tenant_id" json:"tenant_id"`
+	Name           string      `db:"name" json:"name"`
+	Description    *string     `db:"description" json:"description"`
+	EncryptedValue []byte      `db:"encrypted_value" json:"encrypted_value"`
+	EncryptedKey   []byte      `db:"encrypted_key" json:"encrypted_key"`
+	CreatedBy      pgtype.UUID `db:"created_by" json:"created_by"`
+	CreatedAt      time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time   `db:"updated_at" json:"updated_at"`
+}
+
 type Tenant struct {
 	ID        uuid.UUID `db:"id" json:"id"`
 	Name      string    `db:"name" json:"name"`
@@ -158,15 +191,28 @@ type User struct {
 }
 
 type Workflow struct {
-	ID          uuid.UUID       `db:"id" json:"id"`
-	TenantID    uuid.UUID       `db:"tenant_id" json:"tenant_id"`
-	Name        string          `db:"name" json:"name"`
-	Description *string         `db:"description" json:"description"`
-	Definition  json.RawMessage `db:"definition" json:"definition"`
-	Schedule    *string         `db:"schedule" json:"schedule"`
-	Status      string          `db:"status" json:"status"`
-	Version     int32           `db:"version" json:"version"`
-	CreatedBy   pgtype.UUID     `db:"created_by" json:"created_by"`
-	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+	ID                 uuid.UUID       `db:"id" json:"id"`
+	TenantID           uuid.UUID       `db:"tenant_id" json:"tenant_id"`
+	Name               string          `db:"name" json:"name"`
+	Description        *string         `db:"description" json:"description"`
+	Definition         json.RawMessage `db:"definition" json:"definition"`
+	Schedule           *string         `db:"schedule" json:"schedule"`
+	Status             string          `db:"status" json:"status"`
+	Version            int32           `db:"version" json:"version"`
+	CreatedBy          pgtype.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt          time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time       `db:"updated_at" json:"updated_at"`
+	ScheduleOptions    []byte          `db:"schedule_options" json:"schedule_options"`
+	DefinitionComments []byte          `db:"definition_comments" json:"definition_comments"`
+}
+
+type WorkflowVersion struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	TenantID     uuid.UUID       `db:"tenant_id" json:"tenant_id"`
+	WorkflowID   uuid.UUID       `db:"workflow_id" json:"workflow_id"`
+	Version      int32           `db:"version" json:"version"`
+	Definition   json.RawMessage `db:"definition" json:"definition"`
+	RestoredFrom *int32          `db:"restored_from" json:"restored_from"`
+	CreatedBy    pgtype.UUID     `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
 }
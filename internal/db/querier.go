@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
@@ -26,34 +27,42 @@ type Querier interface {
 	CountUsersByTenant(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountWorkflows(ctx context.Context, tenantID uuid.UUID) (int64, error)
 	CountWorkflowsByStatus(ctx context.Context, arg CountWorkflowsByStatusParams) (int64, error)
+	CountWorkflowVersions(ctx context.Context, workflowID uuid.UUID) (int64, error)
 	CreateAlert(ctx context.Context, arg CreateAlertParams) (Alert, error)
 	CreateAlertRule(ctx context.Context, arg CreateAlertRuleParams) (AlertRule, error)
 	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) (AuditLog, error)
 	CreateExecution(ctx context.Context, arg CreateExecutionParams) (Execution, error)
 	// Metric Definitions
 	CreateMetricDefinition(ctx context.Context, arg CreateMetricDefinitionParams) (MetricDefinition, error)
+	CreateSecret(ctx context.Context, arg CreateSecretParams) (Secret, error)
 	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (Workflow, error)
+	CreateWorkflowVersion(ctx context.Context, arg CreateWorkflowVersionParams) (WorkflowVersion, error)
 	DeleteAlertRule(ctx context.Context, arg DeleteAlertRuleParams) error
 	DeleteMetricDefinition(ctx context.Context, arg DeleteMetricDefinitionParams) error
 	DeleteOldMetrics(ctx context.Context, arg DeleteOldMetricsParams) error
+	DeleteSecret(ctx context.Context, arg DeleteSecretParams) error
 	DeleteTenant(ctx context.Context, id uuid.UUID) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
 	DeleteWorkflow(ctx context.Context, id uuid.UUID) error
 	FailExecution(ctx context.Context, arg FailExecutionParams) (Execution, error)
 	GetAlert(ctx context.Context, id uuid.UUID) (Alert, error)
 	GetAlertRule(ctx context.Context, arg GetAlertRuleParams) (AlertRule, error)
+	GetAlertRuleByName(ctx context.Context, arg GetAlertRuleByNameParams) (AlertRule, error)
 	GetAlertRulesForMetric(ctx context.Context, arg GetAlertRulesForMetricParams) ([]AlertRule, error)
 	GetExecution(ctx context.Context, id uuid.UUID) (Execution, error)
 	GetExecutionByTemporalID(ctx context.Context, temporalWorkflowID *string) (Execution, error)
 	GetExecutionStats(ctx context.Context, arg GetExecutionStatsParams) (GetExecutionStatsRow, error)
 	GetLatestMetric(ctx context.Context, arg GetLatestMetricParams) (Metric, error)
+	GetLatestMetricByLabels(ctx context.Context, arg GetLatestMetricByLabelsParams) (Metric, error)
+	GetLatestWorkflowVersion(ctx context.Context, workflowID uuid.UUID) (int32, error)
 	GetMetricDefinition(ctx context.Context, arg GetMetricDefinitionParams) (MetricDefinition, error)
 	GetMetricNames(ctx context.Context, tenantID uuid.UUID) ([]string, error)
 	GetMetricNamesWithPrefix(ctx context.Context, arg GetMetricNamesWithPrefixParams) ([]string, error)
 	GetMetrics(ctx context.Context, arg GetMetricsParams) ([]Metric, error)
 	GetMetricsAggregate(ctx context.Context, arg GetMetricsAggregateParams) (GetMetricsAggregateRow, error)
+	GetMetricsAggregateWithLabels(ctx context.Context, arg GetMetricsAggregateWithLabelsParams) (GetMetricsAggregateWithLabelsRow, error)
 	GetMetricsAggregateWithPercentiles(ctx context.Context, arg GetMetricsAggregateWithPercentilesParams) (GetMetricsAggregateWithPercentilesRow, error)
 	GetMetricsByLabels(ctx context.Context, arg GetMetricsByLabelsParams) ([]Metric, error)
 	// Daily Pre-aggregated Data (from continuous aggregate)
@@ -64,16 +73,21 @@ type Querier interface {
 	GetMetricsSeries(ctx context.Context, arg GetMetricsSeriesParams) ([]GetMetricsSeriesRow, error)
 	GetMetricsSeriesWithLabels(ctx context.Context, arg GetMetricsSeriesWithLabelsParams) ([]GetMetricsSeriesWithLabelsRow, error)
 	GetScheduledWorkflows(ctx context.Context) ([]Workflow, error)
+	GetSecret(ctx context.Context, arg GetSecretParams) (Secret, error)
+	GetSecretByName(ctx context.Context, arg GetSecretByNameParams) (Secret, error)
 	GetTenant(ctx context.Context, id uuid.UUID) (Tenant, error)
 	GetTenantBySlug(ctx context.Context, slug string) (Tenant, error)
 	GetUser(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserByEmail(ctx context.Context, arg GetUserByEmailParams) (User, error)
 	GetUserByExternalID(ctx context.Context, arg GetUserByExternalIDParams) (User, error)
 	GetWorkflow(ctx context.Context, id uuid.UUID) (Workflow, error)
+	GetWorkflowByName(ctx context.Context, arg GetWorkflowByNameParams) (Workflow, error)
+	GetWorkflowVersion(ctx context.Context, arg GetWorkflowVersionParams) (WorkflowVersion, error)
 	InsertMetric(ctx context.Context, arg InsertMetricParams) (Metric, error)
 	InsertMetricsBatch(ctx context.Context, arg []InsertMetricsBatchParams) (int64, error)
 	ListAlertRules(ctx context.Context, arg ListAlertRulesParams) ([]AlertRule, error)
 	ListAlertRulesByConditionType(ctx context.Context, arg ListAlertRulesByConditionTypeParams) ([]AlertRule, error)
+	ListAlertRulesByWorkflow(ctx context.Context, arg ListAlertRulesByWorkflowParams) ([]AlertRule, error)
 	ListAlerts(ctx context.Context, arg ListAlertsParams) ([]Alert, error)
 	ListAlertsBySeverity(ctx context.Context, arg ListAlertsBySeverityParams) ([]Alert, error)
 	ListAlertsByStatus(ctx context.Context, arg ListAlertsByStatusParams) ([]Alert, error)
@@ -82,29 +96,37 @@ type Querier interface {
 	ListAuditLogsByEventType(ctx context.Context, arg ListAuditLogsByEventTypeParams) ([]AuditLog, error)
 	ListAuditLogsByResource(ctx context.Context, arg ListAuditLogsByResourceParams) ([]AuditLog, error)
 	ListAuditLogsByUser(ctx context.Context, arg ListAuditLogsByUserParams) ([]AuditLog, error)
+	ListChildExecutions(ctx context.Context, parentExecutionID pgtype.UUID) ([]Execution, error)
 	ListEnabledAlertRules(ctx context.Context, tenantID uuid.UUID) ([]AlertRule, error)
 	ListExecutions(ctx context.Context, arg ListExecutionsParams) ([]Execution, error)
 	ListExecutionsByStatus(ctx context.Context, arg ListExecutionsByStatusParams) ([]Execution, error)
 	ListExecutionsByWorkflow(ctx context.Context, arg ListExecutionsByWorkflowParams) ([]Execution, error)
+	ListExecutionSteps(ctx context.Context, executionID uuid.UUID) ([]ExecutionStep, error)
 	ListMetricDefinitions(ctx context.Context, arg ListMetricDefinitionsParams) ([]MetricDefinition, error)
 	ListOpenAlerts(ctx context.Context, arg ListOpenAlertsParams) ([]Alert, error)
 	ListRecentExecutions(ctx context.Context, arg ListRecentExecutionsParams) ([]Execution, error)
+	ListSecrets(ctx context.Context, tenantID uuid.UUID) ([]Secret, error)
 	ListTenants(ctx context.Context, arg ListTenantsParams) ([]Tenant, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	ListWorkflows(ctx context.Context, arg ListWorkflowsParams) ([]Workflow, error)
 	ListWorkflowsByStatus(ctx context.Context, arg ListWorkflowsByStatusParams) ([]Workflow, error)
+	ListWorkflowVersions(ctx context.Context, arg ListWorkflowVersionsParams) ([]WorkflowVersion, error)
+	MarkExecutionRunning(ctx context.Context, arg MarkExecutionRunningParams) (Execution, error)
 	ResolveAlert(ctx context.Context, arg ResolveAlertParams) (Alert, error)
 	UpdateAlertRule(ctx context.Context, arg UpdateAlertRuleParams) (AlertRule, error)
 	UpdateAlertRuleLastTriggered(ctx context.Context, id uuid.UUID) error
 	UpdateAlertTriggeredExecution(ctx context.Context, arg UpdateAlertTriggeredExecutionParams) error
+	UpdateExecution(ctx context.Context, arg UpdateExecutionParams) error
 	UpdateExecutionRunID(ctx context.Context, arg UpdateExecutionRunIDParams) error
 	UpdateExecutionStatus(ctx context.Context, arg UpdateExecutionStatusParams) error
 	UpdateExecutionTemporalIDs(ctx context.Context, arg UpdateExecutionTemporalIDsParams) error
 	UpdateMetricDefinition(ctx context.Context, arg UpdateMetricDefinitionParams) (MetricDefinition, error)
+	UpdateSecret(ctx context.Context, arg UpdateSecretParams) (Secret, error)
 	UpdateTenant(ctx context.Context, arg UpdateTenantParams) (Tenant, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
 	UpdateWorkflow(ctx context.Context, arg UpdateWorkflowParams) (Workflow, error)
 	UpdateWorkflowStatus(ctx context.Context, arg UpdateWorkflowStatusParams) (Workflow, error)
+	UpsertExecutionStep(ctx context.Context, arg UpsertExecutionStepParams) (ExecutionStep, error)
 	UpsertUser(ctx context.Context, arg UpsertUserParams) (User, error)
 }
 
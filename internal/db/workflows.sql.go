@@ -41,19 +41,21 @@ func (q *Queries) CountWorkflowsByStatus(ctx context.Context, arg CountWorkflows
 }
 
 const createWorkflow = `-- name: CreateWorkflow :one
-INSERT INTO workflows (tenant_id, name, description, definition, schedule, status, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at
+INSERT INTO workflows (tenant_id, name, description, definition, schedule, status, created_by, schedule_options, definition_comments)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments
 `
 
 type CreateWorkflowParams struct {
-	TenantID    uuid.UUID       `db:"tenant_id" json:"tenant_id"`
-	Name        string          `db:"name" json:"name"`
-	Description *string         `db:"description" json:"description"`
-	Definition  json.RawMessage `db:"definition" json:"definition"`
-	Schedule    *string         `db:"schedule" json:"schedule"`
-	Status      string          `db:"status" json:"status"`
-	CreatedBy   pgtype.UUID     `db:"created_by" json:"created_by"`
+	TenantID           uuid.UUID       `db:"tenant_id" json:"tenant_id"`
+	Name               string          `db:"name" json:"name"`
+	Description        *string         `db:"description" json:"description"`
+	Definition         json.RawMessage `db:"definition" json:"definition"`
+	Schedule           *string         `db:"schedule" json:"schedule"`
+	Status             string          `db:"status" json:"status"`
+	CreatedBy          pgtype.UUID     `db:"created_by" json:"created_by"`
+	ScheduleOptions    []byte          `db:"schedule_options" json:"schedule_options"`
+	DefinitionComments []byte          `db:"definition_comments" json:"definition_comments"`
 }
 
 func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams) (Workflow, error) {
@@ -65,6 +67,8 @@ func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams)
 		arg.Schedule,
 		arg.Status,
 		arg.CreatedBy,
+		arg.ScheduleOptions,
+		arg.DefinitionComments,
 	)
 	var i Workflow
 	err := row.Scan(
@@ -79,6 +83,8 @@ func (q *Queries) CreateWorkflow(ctx context.Context, arg CreateWorkflowParams)
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScheduleOptions,
+		&i.DefinitionComments,
 	)
 	return i, err
 }
@@ -93,7 +99,7 @@ func (q *Queries) DeleteWorkflow(ctx context.Context, id uuid.UUID) error {
 }
 
 const getScheduledWorkflows = `-- name: GetScheduledWorkflows :many
-SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at FROM workflows
+SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments FROM workflows
 WHERE status = 'active' AND schedule IS NOT NULL
 ORDER BY created_at
 `
@@ -119,6 +125,8 @@ func (q *Queries) GetScheduledWorkflows(ctx context.Context) ([]Workflow, error)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScheduleOptions,
+			&i.DefinitionComments,
 		); err != nil {
 			return nil, err
 		}
@@ -131,7 +139,7 @@ func (q *Queries) GetScheduledWorkflows(ctx context.Context) ([]Workflow, error)
 }
 
 const getWorkflow = `-- name: GetWorkflow :one
-SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at FROM workflows WHERE id = $1
+SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments FROM workflows WHERE id = $1
 `
 
 func (q *Queries) GetWorkflow(ctx context.Context, id uuid.UUID) (Workflow, error) {
@@ -149,12 +157,47 @@ func (q *Queries) GetWorkflow(ctx context.Context, id uuid.UUID) (Workflow, erro
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScheduleOptions,
+		&i.DefinitionComments,
+	)
+	return i, err
+}
+
+const getWorkflowByName = `-- name: GetWorkflowByName :one
+SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments FROM workflows
+WHERE tenant_id = $1 AND name = $2
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetWorkflowByNameParams struct {
+	TenantID uuid.UUID `db:"tenant_id" json:"tenant_id"`
+	Name     string    `db:"name" json:"name"`
+}
+
+func (q *Queries) GetWorkflowByName(ctx context.Context, arg GetWorkflowByNameParams) (Workflow, error) {
+	row := q.db.QueryRow(ctx, getWorkflowByName, arg.TenantID, arg.Name)
+	var i Workflow
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.Name,
+		&i.Description,
+		&i.Definition,
+		&i.Schedule,
+		&i.Status,
+		&i.Version,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScheduleOptions,
+		&i.DefinitionComments,
 	)
 	return i, err
 }
 
 const listWorkflows = `-- name: ListWorkflows :many
-SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at FROM workflows
+SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments FROM workflows
 WHERE tenant_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -187,6 +230,8 @@ func (q *Queries) ListWorkflows(ctx context.Context, arg ListWorkflowsParams) ([
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScheduleOptions,
+			&i.DefinitionComments,
 		); err != nil {
 			return nil, err
 		}
@@ -199,7 +244,7 @@ func (q *Queries) ListWorkflows(ctx context.Context, arg ListWorkflowsParams) ([
 }
 
 const listWorkflowsByStatus = `-- name: ListWorkflowsByStatus :many
-SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at FROM workflows
+SELECT id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments FROM workflows
 WHERE tenant_id = $1 AND status = $2
 ORDER BY created_at DESC
 LIMIT $3 OFFSET $4
@@ -238,6 +283,8 @@ func (q *Queries) ListWorkflowsByStatus(ctx context.Context, arg ListWorkflowsBy
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScheduleOptions,
+			&i.DefinitionComments,
 		); err != nil {
 			return nil, err
 		}
@@ -251,18 +298,21 @@ func (q *Queries) ListWorkflowsByStatus(ctx context.Context, arg ListWorkflowsBy
 
 const updateWorkflow = `-- name: UpdateWorkflow :one
 UPDATE workflows
-SET name = $2, description = $3, definition = $4, schedule = $5, status = $6, version = version + 1
+SET name = $2, description = $3, definition = $4, schedule = $5, status = $6, version = $7, schedule_options = $8, definition_comments = $9
 WHERE id = $1
-RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at
+RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments
 `
 
 type UpdateWorkflowParams struct {
-	ID          uuid.UUID       `db:"id" json:"id"`
-	Name        string          `db:"name" json:"name"`
-	Description *string         `db:"description" json:"description"`
-	Definition  json.RawMessage `db:"definition" json:"definition"`
-	Schedule    *string         `db:"schedule" json:"schedule"`
-	Status      string          `db:"status" json:"status"`
+	ID                 uuid.UUID       `db:"id" json:"id"`
+	Name               string          `db:"name" json:"name"`
+	Description        *string         `db:"description" json:"description"`
+	Definition         json.RawMessage `db:"definition" json:"definition"`
+	Schedule           *string         `db:"schedule" json:"schedule"`
+	Status             string          `db:"status" json:"status"`
+	Version            int32           `db:"version" json:"version"`
+	ScheduleOptions    []byte          `db:"schedule_options" json:"schedule_options"`
+	DefinitionComments []byte          `db:"definition_comments" json:"definition_comments"`
 }
 
 func (q *Queries) UpdateWorkflow(ctx context.Context, arg UpdateWorkflowParams) (Workflow, error) {
@@ -273,6 +323,9 @@ func (q *Queries) UpdateWorkflow(ctx context.Context, arg UpdateWorkflowParams)
 		arg.Definition,
 		arg.Schedule,
 		arg.Status,
+		arg.Version,
+		arg.ScheduleOptions,
+		arg.DefinitionComments,
 	)
 	var i Workflow
 	err := row.Scan(
@@ -287,6 +340,8 @@ func (q *Queries) UpdateWorkflow(ctx context.Context, arg UpdateWorkflowParams)
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScheduleOptions,
+		&i.DefinitionComments,
 	)
 	return i, err
 }
@@ -295,7 +350,7 @@ const updateWorkflowStatus = `-- name: UpdateWorkflowStatus :one
 UPDATE workflows
 SET status = $2
 WHERE id = $1
-RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at
+RETURNING id, tenant_id, name, description, definition, schedule, status, version, created_by, created_at, updated_at, schedule_options, definition_comments
 `
 
 type UpdateWorkflowStatusParams struct {
@@ -318,6 +373,8 @@ func (q *Queries) UpdateWorkflowStatus(ctx context.Context, arg UpdateWorkflowSt
 		&i.CreatedBy,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScheduleOptions,
+		&i.DefinitionComments,
 	)
 	return i, err
 }
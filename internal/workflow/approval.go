@@ -0,0 +1,243 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// Approval signal and query names
+const (
+	ApprovalSignalName    = "approval"
+	PendingApprovalsQuery = "pending_approvals"
+)
+
+// DefaultApprovalTimeout is how long an approval step waits for a decision
+// when no timeout is configured
+const DefaultApprovalTimeout = 24 * time.Hour
+
+// Approval actions
+const (
+	ApprovalApprove = "approve"
+	ApprovalReject  = "reject"
+)
+
+// ApprovalConfig for approval step type
+type ApprovalConfig struct {
+	Message       string `json:"message,omitempty"`
+	Timeout       string `json:"timeout,omitempty"`        // e.g., "1h", default 24h
+	DefaultAction string `json:"default_action,omitempty"` // taken on timeout: "reject" (default) or "approve"
+}
+
+// PendingApproval is an approval step waiting for a decision, as returned by
+// the pending_approvals query
+type PendingApproval struct {
+	ID            string    `json:"id"`
+	StepID        string    `json:"step_id"`
+	StepName      string    `json:"step_name"`
+	Message       string    `json:"message,omitempty"`
+	DefaultAction string    `json:"default_action"`
+	RequestedAt   time.Time `json:"requested_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// ApprovalSignal carries a decision on a pending approval
+type ApprovalSignal struct {
+	ApprovalID string    `json:"approval_id"`
+	Action     string    `json:"action"`
+	UserID     string    `json:"user_id,omitempty"`
+	User       string    `json:"user,omitempty"`
+	Comment    string    `json:"comment,omitempty"`
+	DecidedAt  time.Time `json:"decided_at"`
+}
+
+// ApprovalResult is the output of an approval step
+type ApprovalResult struct {
+	Approved  bool      `json:"approved"`
+	Action    string    `json:"action"`
+	UserID    string    `json:"user_id,omitempty"`
+	User      string    `json:"user,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+	DecidedAt time.Time `json:"decided_at"`
+	TimedOut  bool      `json:"timed_out"`
+}
+
+// ParseApprovalConfig parses the config map into ApprovalConfig
+func ParseApprovalConfig(config map[string]interface{}) (*ApprovalConfig, error) {
+	var cfg ApprovalConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.DefaultAction == "" {
+		cfg.DefaultAction = ApprovalReject
+	}
+	if cfg.DefaultAction != ApprovalApprove && cfg.DefaultAction != ApprovalReject {
+		return nil, fmt.Errorf("default_action must be %q or %q", ApprovalApprove, ApprovalReject)
+	}
+	return &cfg, nil
+}
+
+// TimeoutDuration returns the configured timeout or the default
+func (c *ApprovalConfig) TimeoutDuration() (time.Duration, error) {
+	if c.Timeout == "" {
+		return DefaultApprovalTimeout, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", c.Timeout, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return d, nil
+}
+
+// approvals tracks the approval steps of a run that are waiting for a
+// decision. It is shared by every runner of the run, so approvals inside
+// parallel branches and loop iterations are all visible to the query.
+type approvals struct {
+	pending   map[string]*PendingApproval
+	order     []string
+	decisions map[string]ApprovalSignal
+	requested map[string]int // step ID -> times requested, to keep IDs unique
+}
+
+func newApprovals() *approvals {
+	return &approvals{
+		pending:   make(map[string]*PendingApproval),
+		decisions: make(map[string]ApprovalSignal),
+		requested: make(map[string]int),
+	}
+}
+
+// register exposes the pending approvals query and starts receiving approval
+// signals. Decisions for approvals that aren't pending are dropped.
+func (a *approvals) register(ctx workflow.Context) error {
+	err := workflow.SetQueryHandler(ctx, PendingApprovalsQuery, func() ([]PendingApproval, error) {
+		list := make([]PendingApproval, 0, len(a.order))
+		for _, id := range a.order {
+			list = append(list, *a.pending[id])
+		}
+		return list, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	signals := workflow.GetSignalChannel(ctx, ApprovalSignalName)
+	workflow.Go(ctx, func(gctx workflow.Context) {
+		for {
+			var signal ApprovalSignal
+			signals.Receive(gctx, &signal)
+			if _, ok := a.pending[signal.ApprovalID]; !ok {
+				workflow.GetLogger(gctx).Warn("ignoring decision for unknown approval",
+					"approval_id", signal.ApprovalID)
+				continue
+			}
+			a.decisions[signal.ApprovalID] = signal
+		}
+	})
+	return nil
+}
+
+// request adds a pending approval for step and returns its ID. The ID is the
+// step ID, suffixed with a counter when the step runs more than once.
+func (a *approvals) request(step StepDefinition, cfg *ApprovalConfig, now time.Time, timeout time.Duration) string {
+	a.requested[step.ID]++
+	id := step.ID
+	if n := a.requested[step.ID]; n > 1 {
+		id = fmt.Sprintf("%s-%d", step.ID, n)
+	}
+
+	a.pending[id] = &PendingApproval{
+		ID:            id,
+		StepID:        step.ID,
+		StepName:      step.Name,
+		Message:       cfg.Message,
+		DefaultAction: cfg.DefaultAction,
+		RequestedAt:   now,
+		ExpiresAt:     now.Add(timeout),
+	}
+	a.order = append(a.order, id)
+	return id
+}
+
+// resolve removes a pending approval and returns its decision, if any
+func (a *approvals) resolve(id string) (ApprovalSignal, bool) {
+	decision, ok := a.decisions[id]
+	delete(a.decisions, id)
+	delete(a.pending, id)
+	for i, pid := range a.order {
+		if pid == id {
+			a.order = append(a.order[:i], a.order[i+1:]...)
+			break
+		}
+	}
+	return decision, ok
+}
+
+// executeApprovalStep blocks until the approval is approved or rejected via
+// the approval signal, or takes the default action once the timeout expires.
+// A rejected approval fails the step.
+func (r *runner) executeApprovalStep(ctx workflow.Context, step StepDefinition) (*ApprovalResult, error) {
+	cfg, err := ParseApprovalConfig(step.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval config: %w", err)
+	}
+	timeout, err := cfg.TimeoutDuration()
+	if err != nil {
+		return nil, fmt.Errorf("invalid approval config: %w", err)
+	}
+
+	id := r.approvals.request(step, cfg, workflow.Now(ctx), timeout)
+	workflow.GetLogger(ctx).Info("Waiting for approval", "step_id", step.ID, "approval_id", id, "timeout", timeout)
+
+	decided, err := workflow.AwaitWithTimeout(ctx, timeout, func() bool {
+		_, ok := r.approvals.decisions[id]
+		return ok
+	})
+	decision, _ := r.approvals.resolve(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ApprovalResult{
+		Action:    decision.Action,
+		UserID:    decision.UserID,
+		User:      decision.User,
+		Comment:   decision.Comment,
+		DecidedAt: decision.DecidedAt,
+	}
+	if !decided {
+		result.Action = cfg.DefaultAction
+		result.DecidedAt = workflow.Now(ctx)
+		result.TimedOut = true
+	}
+	result.Approved = result.Action == ApprovalApprove
+
+	workflow.GetLogger(ctx).Info("Approval decided",
+		"step_id", step.ID,
+		"approval_id", id,
+		"action", result.Action,
+		"user", result.User,
+		"timed_out", result.TimedOut)
+
+	if !result.Approved {
+		if result.TimedOut {
+			return result, fmt.Errorf("approval timed out after %s", timeout)
+		}
+		return result, fmt.Errorf("approval rejected by %s", decidedBy(result))
+	}
+	return result, nil
+}
+
+func decidedBy(result *ApprovalResult) string {
+	if result.User != "" {
+		return result.User
+	}
+	if result.UserID != "" {
+		return result.UserID
+	}
+	return "unknown user"
+}
@@ -0,0 +1,136 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestParseApprovalConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := ParseApprovalConfig(map[string]interface{}{"message": "Fail over db-1?"})
+
+		require.NoError(t, err)
+		assert.Equal(t, ApprovalReject, cfg.DefaultAction)
+		timeout, err := cfg.TimeoutDuration()
+		require.NoError(t, err)
+		assert.Equal(t, DefaultApprovalTimeout, timeout)
+	})
+
+	t.Run("invalid default action", func(t *testing.T) {
+		_, err := ParseApprovalConfig(map[string]interface{}{"default_action": "ignore"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		cfg, err := ParseApprovalConfig(map[string]interface{}{"timeout": "soon"})
+		require.NoError(t, err)
+
+		_, err = cfg.TimeoutDuration()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestApprovals_RequestAndResolve(t *testing.T) {
+	a := newApprovals()
+	cfg := &ApprovalConfig{DefaultAction: ApprovalReject}
+	step := StepDefinition{ID: "approve", Name: "Approve"}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := a.request(step, cfg, now, time.Hour)
+	second := a.request(step, cfg, now, time.Hour)
+
+	assert.Equal(t, "approve", first)
+	assert.Equal(t, "approve-2", second)
+	assert.Equal(t, []string{"approve", "approve-2"}, a.order)
+	assert.Equal(t, now.Add(time.Hour), a.pending[first].ExpiresAt)
+
+	a.decisions[first] = ApprovalSignal{ApprovalID: first, Action: ApprovalApprove}
+	decision, ok := a.resolve(first)
+
+	assert.True(t, ok)
+	assert.Equal(t, ApprovalApprove, decision.Action)
+	assert.Equal(t, []string{"approve-2"}, a.order)
+	assert.NotContains(t, a.pending, first)
+}
+
+func TestApprovalStep(t *testing.T) {
+	definition := func(config string) string {
+		return `{"steps": [
+			{"id": "approve", "name": "Approve failover", "type": "approval", "config": ` + config + `},
+			{"id": "failover", "type": "log", "config": {"message": "failing over"}}
+		]}`
+	}
+	decide := func(env *testsuite.TestWorkflowEnvironment, signal ApprovalSignal) {
+		env.RegisterDelayedCallback(func() {
+			env.SignalWorkflow(ApprovalSignalName, signal)
+		}, time.Minute)
+	}
+
+	t.Run("continues once approved", func(t *testing.T) {
+		env := newTestEnv()
+		var pending []PendingApproval
+		env.RegisterDelayedCallback(func() {
+			value, err := env.QueryWorkflow(PendingApprovalsQuery)
+			require.NoError(t, err)
+			require.NoError(t, value.Get(&pending))
+		}, 30*time.Second)
+		decide(env, ApprovalSignal{ApprovalID: "approve", Action: ApprovalApprove, User: "alice", Comment: "go ahead"})
+
+		output := runDefinition(t, env, definition(`{"message": "Fail over db-1?"}`))
+
+		require.Len(t, pending, 1)
+		assert.Equal(t, "approve", pending[0].ID)
+		assert.Equal(t, "Fail over db-1?", pending[0].Message)
+
+		assert.Equal(t, "completed", output.Status)
+		require.Len(t, output.StepResults, 2)
+		var result ApprovalResult
+		decodeOutput(t, output.StepResults[0].Output, &result)
+		assert.True(t, result.Approved)
+		assert.False(t, result.TimedOut)
+		assert.Equal(t, "alice", result.User)
+		assert.Equal(t, "go ahead", result.Comment)
+		assert.True(t, output.StepResults[1].Success)
+	})
+
+	t.Run("fails the step when rejected", func(t *testing.T) {
+		env := newTestEnv()
+		decide(env, ApprovalSignal{ApprovalID: "approve", Action: ApprovalReject, User: "bob"})
+
+		output := runDefinition(t, env, definition(`{}`))
+
+		assert.Equal(t, "failed", output.Status)
+		require.Len(t, output.StepResults, 1)
+		assert.Equal(t, "approval rejected by bob", output.StepResults[0].Error)
+	})
+
+	t.Run("takes the default action on timeout", func(t *testing.T) {
+		env := newTestEnv()
+
+		output := runDefinition(t, env, definition(`{"timeout": "1h", "default_action": "approve"}`))
+
+		assert.Equal(t, "completed", output.Status)
+		var result ApprovalResult
+		decodeOutput(t, output.StepResults[0].Output, &result)
+		assert.True(t, result.Approved)
+		assert.True(t, result.TimedOut)
+		assert.Equal(t, ApprovalApprove, result.Action)
+	})
+
+	t.Run("ignores decisions for unknown approvals", func(t *testing.T) {
+		env := newTestEnv()
+		decide(env, ApprovalSignal{ApprovalID: "other", Action: ApprovalApprove, User: "alice"})
+
+		output := runDefinition(t, env, definition(`{"timeout": "1h"}`))
+
+		assert.Equal(t, "failed", output.Status)
+		require.Len(t, output.StepResults, 1)
+		assert.Equal(t, "approval timed out after 1h0m0s", output.StepResults[0].Error)
+	})
+}
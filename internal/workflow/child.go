@@ -0,0 +1,126 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// MaxChildWorkflowDepth bounds how deeply child workflows can nest, which
+// also stops workflows that (indirectly) call themselves
+const MaxChildWorkflowDepth = 5
+
+// ChildWorkflowConfig for child_workflow step type
+type ChildWorkflowConfig struct {
+	WorkflowID   string                 `json:"workflow_id,omitempty"`
+	WorkflowName string                 `json:"workflow_name,omitempty"`
+	Input        map[string]interface{} `json:"input,omitempty"`
+	Timeout      string                 `json:"timeout,omitempty"` // e.g., "30m", no limit by default
+}
+
+// ParseChildWorkflowConfig parses the config map into ChildWorkflowConfig
+func ParseChildWorkflowConfig(config map[string]interface{}) (*ChildWorkflowConfig, error) {
+	var cfg ChildWorkflowConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if (cfg.WorkflowID == "") == (cfg.WorkflowName == "") {
+		return nil, fmt.Errorf("exactly one of workflow_id or workflow_name is required")
+	}
+	return &cfg, nil
+}
+
+// executeChildWorkflowStep runs another stored workflow as a Temporal child
+// workflow. The child gets its own execution record linked to this one and
+// its DynamicWorkflowOutput becomes the step output.
+func (r *runner) executeChildWorkflowStep(ctx workflow.Context, step StepDefinition) (*DynamicWorkflowOutput, error) {
+	cfg, err := ParseChildWorkflowConfig(step.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid child_workflow config: %w", err)
+	}
+
+	var timeout time.Duration
+	if cfg.Timeout != "" {
+		if timeout, err = time.ParseDuration(cfg.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid child_workflow config: invalid timeout %q: %w", cfg.Timeout, err)
+		}
+	}
+
+	if r.input.Depth >= MaxChildWorkflowDepth {
+		return nil, fmt.Errorf("child workflows cannot be nested more than %d levels deep", MaxChildWorkflowDepth)
+	}
+
+	var child activity.StartChildExecutionResult
+	err = workflow.ExecuteActivity(ctx, "StartChildExecution", activity.StartChildExecutionInput{
+		TenantID:          r.input.TenantID,
+		ParentExecutionID: r.input.ExecutionID,
+		WorkflowID:        cfg.WorkflowID,
+		WorkflowName:      cfg.WorkflowName,
+		Input:             cfg.Input,
+	}).Get(ctx, &child)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start child workflow: %w", err)
+	}
+
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:               child.TemporalWorkflowID,
+		WorkflowExecutionTimeout: timeout,
+	})
+
+	var output DynamicWorkflowOutput
+	childErr := workflow.ExecuteChildWorkflow(childCtx, DynamicWorkflow, DynamicWorkflowInput{
+		ExecutionID:       child.ExecutionID,
+		WorkflowID:        child.WorkflowID,
+		Name:              child.Name,
+		Definition:        child.Definition,
+		Input:             child.Input,
+		TenantID:          r.input.TenantID,
+		ParentExecutionID: r.input.ExecutionID,
+		Depth:             r.input.Depth + 1,
+	}).Get(childCtx, &output)
+
+	workflow.GetLogger(ctx).Info("Child workflow finished",
+		"step_id", step.ID,
+		"child_execution_id", child.ExecutionID,
+		"status", output.Status)
+
+	// The child records its own outcome. Record a failure on its behalf
+	// when it didn't get that far, e.g. because it was terminated.
+	if recordChildOutcome(childErr) {
+		complete := activity.CompleteExecutionInput{
+			TenantID:    r.input.TenantID,
+			ExecutionID: child.ExecutionID,
+			Status:      output.Status,
+			Output:      output.Output,
+			Error:       output.Error,
+		}
+		if childErr != nil {
+			complete.Status = "failed"
+			complete.Error = childErr.Error()
+		}
+		if err := workflow.ExecuteActivity(ctx, "CompleteExecution", complete).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Warn("failed to record child execution outcome",
+				"child_execution_id", child.ExecutionID,
+				"error", err)
+		}
+	}
+
+	if childErr != nil {
+		return nil, fmt.Errorf("child workflow %s failed: %w", child.Name, childErr)
+	}
+	if output.Status != "completed" {
+		return &output, fmt.Errorf("child workflow %s %s: %s", child.Name, output.Status, output.Error)
+	}
+	return &output, nil
+}
+
+// recordChildOutcome reports whether the parent has to record the outcome of
+// a child run. Children record their own outcome, unless they were stopped
+// before they could; cancelled children still get to record theirs.
+func recordChildOutcome(childErr error) bool {
+	return childErr != nil && !temporal.IsCanceledError(childErr)
+}
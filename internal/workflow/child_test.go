@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChildWorkflowConfig(t *testing.T) {
+	t.Run("by name", func(t *testing.T) {
+		cfg, err := ParseChildWorkflowConfig(map[string]interface{}{
+			"workflow_name": "restart-service",
+			"input":         map[string]interface{}{"host": "db-1"},
+			"timeout":       "10m",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "restart-service", cfg.WorkflowName)
+		assert.Equal(t, "db-1", cfg.Input["host"])
+		assert.Equal(t, "10m", cfg.Timeout)
+	})
+
+	t.Run("requires a workflow reference", func(t *testing.T) {
+		_, err := ParseChildWorkflowConfig(map[string]interface{}{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects both id and name", func(t *testing.T) {
+		_, err := ParseChildWorkflowConfig(map[string]interface{}{
+			"workflow_id":   "3f2c9a4e-8d1b-4c55-9f0a-7b6e2d1c0a99",
+			"workflow_name": "restart-service",
+		})
+
+		assert.Error(t, err)
+	})
+}
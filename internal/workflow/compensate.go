@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"go.temporal.io/sdk/workflow"
+)
+
+// CompensationResult is the outcome of the compensate steps of a completed
+// step, run after a later step failed the run
+type CompensationResult struct {
+	StepID  string       `json:"step_id"`
+	Success bool         `json:"success"`
+	Error   string       `json:"error,omitempty"`
+	Steps   []StepResult `json:"steps"`
+}
+
+// compensate undoes the completed top-level steps at the given positions by
+// running their compensate steps, most recent first. Compensate steps see the
+// state of the run when it failed, so they can reference the outputs of the
+// step they undo. A failed compensation is recorded and doesn't stop the
+// compensations of earlier steps.
+func (r *runner) compensate(ctx workflow.Context, defs []StepDefinition, completed []int, t *timeline) []CompensationResult {
+	var results []CompensationResult
+	for i := len(completed) - 1; i >= 0; i-- {
+		position := completed[i]
+		step := defs[position]
+		if len(step.Compensate) == 0 {
+			continue
+		}
+
+		workflow.GetLogger(ctx).Info("compensating step", "step_id", step.ID)
+		stepResults, err := r.fork(nil).runSteps(ctx, step.Compensate)
+		result := CompensationResult{
+			StepID:  step.ID,
+			Success: err == nil,
+			Steps:   stepResults,
+		}
+		if err != nil {
+			result.Error = err.Error()
+			workflow.GetLogger(ctx).Error("compensation failed", "step_id", step.ID, "error", err)
+		} else {
+			t.compensated(ctx, position)
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,85 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+func TestCompensation(t *testing.T) {
+	t.Run("undoes completed steps most recent first", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "create", "type": "http", "config": {"url": "http://create"}, "compensate": [
+				{"id": "delete", "type": "http", "config": {"url": "http://delete/${steps.create.output.status_code}"}}
+			]},
+			{"id": "note", "type": "log", "config": {"message": "created"}},
+			{"id": "scale", "type": "http", "config": {"url": "http://scale"}, "compensate": [
+				{"id": "unscale", "type": "log", "config": {"message": "scaling back"}}
+			]},
+			{"id": "deploy", "type": "http", "config": {"url": "http://fail"}, "compensate": [
+				{"id": "undeploy", "type": "log", "config": {"message": "never runs"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "failed", output.Status)
+		require.Len(t, output.Compensations, 2)
+		assert.Equal(t, "scale", output.Compensations[0].StepID)
+		assert.True(t, output.Compensations[0].Success)
+		assert.Equal(t, "create", output.Compensations[1].StepID)
+		assert.True(t, output.Compensations[1].Success)
+
+		// Compensate steps see the outputs of the step they undo
+		require.Len(t, output.Compensations[1].Steps, 1)
+		var deleted activity.HTTPResult
+		decodeOutput(t, output.Compensations[1].Steps[0].Output, &deleted)
+		assert.Equal(t, "http://delete/200", deleted.Body)
+	})
+
+	t.Run("a failed compensation doesn't stop the others", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "create", "type": "http", "config": {"url": "http://create"}, "compensate": [
+				{"id": "delete", "type": "log", "config": {"message": "deleting"}}
+			]},
+			{"id": "scale", "type": "http", "config": {"url": "http://scale"}, "compensate": [
+				{"id": "unscale", "type": "http", "config": {"url": "http://fail"}}
+			]},
+			{"id": "deploy", "type": "http", "config": {"url": "http://fail"}}
+		]}`)
+
+		assert.Equal(t, "failed", output.Status)
+		require.Len(t, output.Compensations, 2)
+		assert.Equal(t, "scale", output.Compensations[0].StepID)
+		assert.False(t, output.Compensations[0].Success)
+		assert.Contains(t, output.Compensations[0].Error, "step unscale failed")
+		assert.Equal(t, "create", output.Compensations[1].StepID)
+		assert.True(t, output.Compensations[1].Success)
+	})
+
+	t.Run("compensates a run that timed out", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"timeout": "1m", "steps": [
+			{"id": "create", "type": "http", "config": {"url": "http://create"}, "compensate": [
+				{"id": "delete", "type": "log", "config": {"message": "deleting"}}
+			]},
+			{"id": "wait", "type": "delay", "config": {"duration": "1h"}}
+		]}`)
+
+		assert.Equal(t, "timed_out", output.Status)
+		require.Len(t, output.Compensations, 1)
+		assert.Equal(t, "create", output.Compensations[0].StepID)
+		assert.True(t, output.Compensations[0].Success)
+	})
+
+	t.Run("doesn't compensate a completed run", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "create", "type": "http", "config": {"url": "http://create"}, "compensate": [
+				{"id": "delete", "type": "log", "config": {"message": "deleting"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "completed", output.Status)
+		assert.Empty(t, output.Compensations)
+	})
+}
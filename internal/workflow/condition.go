@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// Condition branches
+const (
+	BranchOnTrue  = "on_true"
+	BranchOnFalse = "on_false"
+)
+
+// ConditionResult is the output of a condition step
+type ConditionResult struct {
+	Condition string `json:"condition"`
+	Result    bool   `json:"result"`
+	Branch    string `json:"branch"`
+}
+
+// executeConditionStep evaluates the step condition and runs the matching
+// branch. Nested step results are returned as children of the condition step.
+func (r *runner) executeConditionStep(ctx workflow.Context, step StepDefinition) (*ConditionResult, []StepResult, error) {
+	if step.Condition == "" {
+		return nil, nil, fmt.Errorf("condition step requires a condition")
+	}
+
+	ok, err := EvaluateCondition(step.Condition, r.scope())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &ConditionResult{
+		Condition: step.Condition,
+		Result:    ok,
+		Branch:    BranchOnFalse,
+	}
+	branch := step.OnFalse
+	if ok {
+		result.Branch = BranchOnTrue
+		branch = step.OnTrue
+	}
+
+	workflow.GetLogger(ctx).Info("Condition evaluated",
+		"step_id", step.ID,
+		"result", ok,
+		"branch", result.Branch)
+
+	children, err := r.runSteps(ctx, branch)
+	if err != nil {
+		return result, children, fmt.Errorf("%s branch: %w", result.Branch, err)
+	}
+	return result, children, nil
+}
@@ -2,22 +2,33 @@ package workflow
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
 )
 
 // StepType defines the type of workflow step
 type StepType string
 
 const (
-	StepTypeHTTP       StepType = "http"
-	StepTypeDelay      StepType = "delay"
-	StepTypeLog        StepType = "log"
-	StepTypeValidate   StepType = "validate"
-	StepTypeProcess    StepType = "process"
-	StepTypeNotify     StepType = "notify"
-	StepTypeCondition  StepType = "condition"
-	StepTypeScript     StepType = "script"
-	StepTypeParallel   StepType = "parallel"
+	StepTypeHTTP          StepType = "http"
+	StepTypeDelay         StepType = "delay"
+	StepTypeLog           StepType = "log"
+	StepTypeValidate      StepType = "validate"
+	StepTypeProcess       StepType = "process"
+	StepTypeNotify        StepType = "notify"
+	StepTypeCondition     StepType = "condition"
+	StepTypeScript        StepType = "script"
+	StepTypeParallel      StepType = "parallel"
+	StepTypeForeach       StepType = "foreach"
+	StepTypeChildWorkflow StepType = "child_workflow"
+	StepTypeApproval      StepType = "approval"
+	StepTypeWaitForMetric StepType = "wait_for_metric"
 )
 
 // WorkflowDefinition represents the structure of a workflow
@@ -27,6 +38,7 @@ type WorkflowDefinition struct {
 	Description string           `json:"description,omitempty"`
 	Timeout     string           `json:"timeout,omitempty"` // e.g., "30m", "1h"
 	RetryPolicy *RetryPolicyDef  `json:"retry_policy,omitempty"`
+	Inputs      *domain.InputSchema `json:"inputs,omitempty"` // validated and defaulted before the run starts
 	Steps       []StepDefinition `json:"steps"`
 	OnError     []StepDefinition `json:"on_error,omitempty"`
 	OnSuccess   []StepDefinition `json:"on_success,omitempty"`
@@ -40,6 +52,55 @@ type RetryPolicyDef struct {
 	Multiplier      float64 `json:"multiplier"`
 }
 
+// TimeoutDuration returns how long a run of the workflow may take, 0 when
+// the definition sets no timeout
+func (d *WorkflowDefinition) TimeoutDuration() (time.Duration, error) {
+	if d.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(d.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", d.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", d.Timeout)
+	}
+	return timeout, nil
+}
+
+// TemporalPolicy converts the retry policy to a Temporal retry policy. Unset
+// fields keep Temporal's defaults. When a duration is invalid the policy is
+// returned without it, along with the error.
+func (p *RetryPolicyDef) TemporalPolicy() (*temporal.RetryPolicy, error) {
+	policy := &temporal.RetryPolicy{
+		MaximumAttempts: int32(p.MaxAttempts),
+	}
+	if p.Multiplier > 0 {
+		policy.BackoffCoefficient = p.Multiplier
+	}
+
+	var errs []error
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"initial_interval", p.InitialInterval, &policy.InitialInterval},
+		{"max_interval", p.MaxInterval, &policy.MaximumInterval},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil || parsed <= 0 {
+			errs = append(errs, fmt.Errorf("invalid retry policy %s %q", d.name, d.value))
+			continue
+		}
+		*d.dest = parsed
+	}
+	return policy, errors.Join(errs...)
+}
+
 // StepDefinition represents a single step in the workflow
 type StepDefinition struct {
 	ID          string                 `json:"id"`
@@ -57,6 +118,12 @@ type StepDefinition struct {
 
 	// Parallel execution
 	Parallel    []StepDefinition `json:"parallel,omitempty"`
+
+	// Loop body, run once per item by foreach steps
+	Steps       []StepDefinition `json:"steps,omitempty"`
+
+	// Steps that undo this step if a later step fails the run
+	Compensate  []StepDefinition `json:"compensate,omitempty"`
 }
 
 // HTTPConfig for HTTP step type
@@ -67,11 +134,14 @@ type HTTPConfig struct {
 	Body        interface{}       `json:"body,omitempty"`
 	Timeout     string            `json:"timeout,omitempty"`
 	SuccessCodes []int            `json:"success_codes,omitempty"` // default [200, 201, 202, 204]
+	Extract     []activity.HTTPExtract `json:"extract,omitempty"` // values copied into output.extracted
+	Assert      []activity.HTTPAssert  `json:"assert,omitempty"`  // conditions the response must meet
 }
 
 // DelayConfig for delay step type
 type DelayConfig struct {
-	Duration string `json:"duration"` // e.g., "5s", "1m", "1h"
+	Duration string `json:"duration,omitempty"` // e.g., "5s", "1m", "1h"
+	Until    string `json:"until,omitempty"`    // RFC 3339 time to sleep until, e.g. "2025-01-02T03:00:00Z"
 }
 
 // LogConfig for log step type
@@ -90,8 +160,10 @@ type NotifyConfig struct {
 
 // ScriptConfig for script execution step type
 type ScriptConfig struct {
-	Language string `json:"language"` // javascript, python (future)
-	Code     string `json:"code"`
+	Language    string `json:"language"` // javascript, python (future)
+	Code        string `json:"code"`
+	Timeout     string `json:"timeout,omitempty"`       // interpreter time limit, default "5s"
+	MaxMemoryMB int    `json:"max_memory_mb,omitempty"` // default 64, at most 256
 }
 
 // ParseDefinition parses a JSON definition into a WorkflowDefinition
@@ -111,25 +183,38 @@ func ParseDefinition(data json.RawMessage) (*WorkflowDefinition, error) {
 		if step.Type == "" {
 			return nil, fmt.Errorf("step %d: type is required", i)
 		}
+	}
+
+	applyStepDefaults(def.Steps, "step")
+	applyStepDefaults(def.OnSuccess, "on_success")
+	applyStepDefaults(def.OnError, "on_error")
+
+	return &def, nil
+}
+
+// applyStepDefaults fills in missing IDs and names, recursing into nested
+// branches so that every step can be referenced by ID
+func applyStepDefaults(steps []StepDefinition, prefix string) {
+	for i := range steps {
+		step := &steps[i]
 		if step.ID == "" {
-			def.Steps[i].ID = fmt.Sprintf("step_%d", i+1)
+			step.ID = fmt.Sprintf("%s_%d", prefix, i+1)
 		}
 		if step.Name == "" {
-			def.Steps[i].Name = string(step.Type)
+			step.Name = string(step.Type)
 		}
+		applyStepDefaults(step.OnTrue, step.ID+"_true")
+		applyStepDefaults(step.OnFalse, step.ID+"_false")
+		applyStepDefaults(step.Parallel, step.ID+"_branch")
+		applyStepDefaults(step.Steps, step.ID+"_item")
+		applyStepDefaults(step.Compensate, step.ID+"_compensate")
 	}
-
-	return &def, nil
 }
 
 // ParseHTTPConfig parses the config map into HTTPConfig
 func ParseHTTPConfig(config map[string]interface{}) (*HTTPConfig, error) {
-	data, err := json.Marshal(config)
-	if err != nil {
-		return nil, err
-	}
 	var cfg HTTPConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := decodeConfig(config, &cfg); err != nil {
 		return nil, err
 	}
 	if cfg.Method == "" {
@@ -138,33 +223,66 @@ func ParseHTTPConfig(config map[string]interface{}) (*HTTPConfig, error) {
 	if len(cfg.SuccessCodes) == 0 {
 		cfg.SuccessCodes = []int{200, 201, 202, 204}
 	}
+	names := make(map[string]bool, len(cfg.Extract))
+	for i, rule := range cfg.Extract {
+		if err := rule.Check(); err != nil {
+			return nil, fmt.Errorf("extract[%d]: %w", i, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("extract[%d]: duplicate name %q", i, rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	for i, rule := range cfg.Assert {
+		if err := rule.Check(); err != nil {
+			return nil, fmt.Errorf("assert[%d]: %w", i, err)
+		}
+	}
 	return &cfg, nil
 }
 
 // ParseDelayConfig parses the config map into DelayConfig
 func ParseDelayConfig(config map[string]interface{}) (*DelayConfig, error) {
-	data, err := json.Marshal(config)
-	if err != nil {
-		return nil, err
-	}
 	var cfg DelayConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := decodeConfig(config, &cfg); err != nil {
 		return nil, err
 	}
-	if cfg.Duration == "" {
+	if cfg.Duration != "" && cfg.Until != "" {
+		return nil, fmt.Errorf("only one of duration or until can be set")
+	}
+	if cfg.Duration == "" && cfg.Until == "" {
 		cfg.Duration = "1s"
 	}
 	return &cfg, nil
 }
 
-// ParseLogConfig parses the config map into LogConfig
-func ParseLogConfig(config map[string]interface{}) (*LogConfig, error) {
-	data, err := json.Marshal(config)
+// Wait returns how long to sleep from now. Times in the past don't wait.
+func (c *DelayConfig) Wait(now time.Time) (time.Duration, error) {
+	if c.Until != "" {
+		until, err := time.Parse(time.RFC3339, c.Until)
+		if err != nil {
+			return 0, fmt.Errorf("invalid until %q: must be an RFC 3339 time", c.Until)
+		}
+		if !until.After(now) {
+			return 0, nil
+		}
+		return until.Sub(now), nil
+	}
+
+	d, err := time.ParseDuration(c.Duration)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("invalid duration %q: %w", c.Duration, err)
 	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration cannot be negative")
+	}
+	return d, nil
+}
+
+// ParseLogConfig parses the config map into LogConfig
+func ParseLogConfig(config map[string]interface{}) (*LogConfig, error) {
 	var cfg LogConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	if err := decodeConfig(config, &cfg); err != nil {
 		return nil, err
 	}
 	if cfg.Level == "" {
@@ -175,13 +293,25 @@ func ParseLogConfig(config map[string]interface{}) (*LogConfig, error) {
 
 // ParseNotifyConfig parses the config map into NotifyConfig
 func ParseNotifyConfig(config map[string]interface{}) (*NotifyConfig, error) {
-	data, err := json.Marshal(config)
-	if err != nil {
+	var cfg NotifyConfig
+	if err := decodeConfig(config, &cfg); err != nil {
 		return nil, err
 	}
-	var cfg NotifyConfig
-	if err := json.Unmarshal(data, &cfg); err != nil {
+	return &cfg, nil
+}
+
+
+// ParseScriptConfig parses the config map into ScriptConfig
+func ParseScriptConfig(config map[string]interface{}) (*ScriptConfig, error) {
+	var cfg ScriptConfig
+	if err := decodeConfig(config, &cfg); err != nil {
 		return nil, err
 	}
+	if cfg.Language == "" {
+		cfg.Language = "javascript"
+	}
+	if cfg.Timeout == "" {
+		cfg.Timeout = "5s"
+	}
 	return &cfg, nil
 }
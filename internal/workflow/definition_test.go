@@ -0,0 +1,100 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayConfig_Wait(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		expected time.Duration
+	}{
+		{"default duration", map[string]interface{}{}, time.Second},
+		{"duration", map[string]interface{}{"duration": "30m"}, 30 * time.Minute},
+		{"until", map[string]interface{}{"until": "2026-03-02T12:00:00Z"}, 24 * time.Hour},
+		{"until with offset", map[string]interface{}{"until": "2026-03-01T14:00:00+01:00"}, time.Hour},
+		{"until in the past", map[string]interface{}{"until": "2026-02-01T00:00:00Z"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseDelayConfig(tt.config)
+			require.NoError(t, err)
+
+			wait, err := cfg.Wait(now)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, wait)
+		})
+	}
+
+	t.Run("rejects duration and until together", func(t *testing.T) {
+		_, err := ParseDelayConfig(map[string]interface{}{"duration": "1m", "until": "2026-03-02T12:00:00Z"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for _, config := range []map[string]interface{}{
+			{"duration": "soon"},
+			{"duration": "-5m"},
+			{"until": "tomorrow"},
+		} {
+			cfg, err := ParseDelayConfig(config)
+			require.NoError(t, err)
+
+			_, err = cfg.Wait(now)
+
+			assert.Error(t, err, config)
+		}
+	})
+}
+
+func TestWorkflowDefinition_TimeoutDuration(t *testing.T) {
+	timeout, err := (&WorkflowDefinition{}).TimeoutDuration()
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	timeout, err = (&WorkflowDefinition{Timeout: "30m"}).TimeoutDuration()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, timeout)
+
+	_, err = (&WorkflowDefinition{Timeout: "soon"}).TimeoutDuration()
+	assert.Error(t, err)
+
+	_, err = (&WorkflowDefinition{Timeout: "-1m"}).TimeoutDuration()
+	assert.Error(t, err)
+}
+
+func TestRetryPolicyDef_TemporalPolicy(t *testing.T) {
+	t.Run("converts every field", func(t *testing.T) {
+		policy, err := (&RetryPolicyDef{
+			MaxAttempts:     5,
+			InitialInterval: "2s",
+			MaxInterval:     "1m",
+			Multiplier:      3,
+		}).TemporalPolicy()
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), policy.MaximumAttempts)
+		assert.Equal(t, 2*time.Second, policy.InitialInterval)
+		assert.Equal(t, time.Minute, policy.MaximumInterval)
+		assert.Equal(t, 3.0, policy.BackoffCoefficient)
+	})
+
+	t.Run("leaves out invalid durations", func(t *testing.T) {
+		policy, err := (&RetryPolicyDef{MaxAttempts: 2, InitialInterval: "x", MaxInterval: "1m"}).TemporalPolicy()
+
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), policy.MaximumAttempts)
+		assert.Equal(t, time.Duration(0), policy.InitialInterval)
+		assert.Equal(t, time.Minute, policy.MaximumInterval)
+	})
+}
@@ -0,0 +1,180 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// DryRunInput is set when a workflow is simulated rather than run
+type DryRunInput struct {
+	// Fixtures override the simulated output of steps, by step ID, e.g.
+	// {"check": {"status_code": 503, "body": "down"}} for an HTTP step
+	Fixtures map[string]interface{} `json:"fixtures,omitempty"`
+}
+
+// simulatedSteps are the step types with side effects. In a dry run they
+// aren't run but return what a successful run would, or their fixture. Steps
+// without side effects run as usual.
+var simulatedSteps = map[StepType]bool{
+	StepTypeHTTP:          true,
+	StepTypeNotify:        true,
+	StepTypeDelay:         true,
+	StepTypeApproval:      true,
+	StepTypeWaitForMetric: true,
+	StepTypeChildWorkflow: true,
+}
+
+// httpFixture is the fixture of an HTTP step. Bodies that aren't strings
+// are JSON encoded.
+type httpFixture struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       interface{}       `json:"body"`
+}
+
+// simulateStep returns the output of a step with side effects in a dry run.
+// The rendered config is checked as it would be by the real step, so config
+// errors still fail the step.
+func (r *runner) simulateStep(ctx workflow.Context, step StepDefinition) (interface{}, error) {
+	fixture, hasFixture := r.input.DryRun.Fixtures[step.ID]
+	decode := func(v interface{}) error {
+		if !hasFixture {
+			return nil
+		}
+		if err := decodeFixture(fixture, v); err != nil {
+			return fmt.Errorf("invalid fixture for step %s: %w", step.ID, err)
+		}
+		return nil
+	}
+
+	switch step.Type {
+	case StepTypeHTTP:
+		cfg, err := ParseHTTPConfig(step.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HTTP config: %w", err)
+		}
+		response := httpFixture{StatusCode: 200, Headers: map[string]string{}}
+		if err := decode(&response); err != nil {
+			return nil, err
+		}
+		result := &activity.HTTPResult{
+			StatusCode: response.StatusCode,
+			Headers:    response.Headers,
+		}
+		switch body := response.Body.(type) {
+		case nil:
+		case string:
+			result.Body = body
+		default:
+			data, err := json.Marshal(body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fixture for step %s: %w", step.ID, err)
+			}
+			result.Body = string(data)
+		}
+		for _, code := range cfg.SuccessCodes {
+			if response.StatusCode == code {
+				result.Success = true
+			}
+		}
+		if len(cfg.Extract) > 0 || len(cfg.Assert) > 0 {
+			if result.Extracted, err = activity.CheckHTTPResponse(cfg.Extract, cfg.Assert, result.Body, result.Headers, 0); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+
+	case StepTypeNotify:
+		if _, err := ParseNotifyConfig(step.Config); err != nil {
+			return nil, fmt.Errorf("invalid notify config: %w", err)
+		}
+		return &activity.NotifyResult{Sent: false}, nil
+
+	case StepTypeDelay:
+		cfg, err := ParseDelayConfig(step.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delay config: %w", err)
+		}
+		now := workflow.Now(ctx)
+		wait, err := cfg.Wait(now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delay config: %w", err)
+		}
+		return &DelayResult{DurationMs: wait.Milliseconds(), Until: now.Add(wait)}, nil
+
+	case StepTypeApproval:
+		cfg, err := ParseApprovalConfig(step.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid approval config: %w", err)
+		}
+		if _, err := cfg.TimeoutDuration(); err != nil {
+			return nil, fmt.Errorf("invalid approval config: %w", err)
+		}
+		result := &ApprovalResult{Action: ApprovalApprove, DecidedAt: workflow.Now(ctx)}
+		if err := decode(result); err != nil {
+			return nil, err
+		}
+		result.Approved = result.Action == ApprovalApprove
+		if !result.Approved {
+			return result, fmt.Errorf("approval rejected by %s", decidedBy(result))
+		}
+		return result, nil
+
+	case StepTypeWaitForMetric:
+		cfg, err := ParseWaitForMetricConfig(step.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wait_for_metric config: %w", err)
+		}
+		result := &WaitForMetricResult{
+			Satisfied:    true,
+			Metric:       cfg.Metric,
+			Labels:       cfg.Labels,
+			Aggregation:  cfg.Aggregation,
+			Operator:     cfg.Operator,
+			Threshold:    *cfg.Threshold,
+			Observations: []activity.CheckMetricResult{},
+		}
+		if err := decode(result); err != nil {
+			return nil, err
+		}
+		if !result.Satisfied && cfg.OnTimeout == "fail" {
+			return result, fmt.Errorf("metric condition %s not met within %s", describeMetricCondition(cfg), cfg.Timeout)
+		}
+		return result, nil
+
+	case StepTypeChildWorkflow:
+		cfg, err := ParseChildWorkflowConfig(step.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid child_workflow config: %w", err)
+		}
+		if r.input.Depth >= MaxChildWorkflowDepth {
+			return nil, fmt.Errorf("child workflows cannot be nested more than %d levels deep", MaxChildWorkflowDepth)
+		}
+		output := &DynamicWorkflowOutput{Status: "completed", StepResults: []StepResult{}}
+		if err := decode(output); err != nil {
+			return nil, err
+		}
+		if output.Status != "completed" {
+			name := cfg.WorkflowName
+			if name == "" {
+				name = cfg.WorkflowID
+			}
+			return output, fmt.Errorf("child workflow %s %s: %s", name, output.Status, output.Error)
+		}
+		return output, nil
+	}
+	return nil, fmt.Errorf("step type %s is not simulated", step.Type)
+}
+
+// decodeFixture decodes a fixture given as JSON into v
+func decodeFixture(fixture interface{}, v interface{}) error {
+	data, err := json.Marshal(fixture)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -11,6 +11,11 @@ import (
 	"github.com/orchestrix/orchestrix-api/internal/activity"
 )
 
+// TimeoutGrace is how long a run that timed out is given to compensate its
+// completed steps, run its on_error steps and record its outcome before
+// Temporal stops it
+const TimeoutGrace = 10 * time.Minute
+
 // DynamicWorkflowInput defines the input for the dynamic workflow
 type DynamicWorkflowInput struct {
 	ExecutionID string                 `json:"execution_id"`
@@ -18,6 +23,17 @@ type DynamicWorkflowInput struct {
 	Name        string                 `json:"name"`
 	Definition  json.RawMessage        `json:"definition"`
 	Input       map[string]interface{} `json:"input,omitempty"`
+	TenantID    string                 `json:"tenant_id,omitempty"`
+
+	// Set when started by a child_workflow step
+	ParentExecutionID string `json:"parent_execution_id,omitempty"`
+	Depth             int    `json:"depth,omitempty"`
+
+	// Set when retrying a failed execution from its failed step
+	Resume *ResumeInput `json:"resume,omitempty"`
+
+	// Set when simulating the workflow without side effects
+	DryRun *DryRunInput `json:"dry_run,omitempty"`
 }
 
 // DynamicWorkflowOutput defines the output of the dynamic workflow
@@ -29,17 +45,174 @@ type DynamicWorkflowOutput struct {
 	Error       string                   `json:"error,omitempty"`
 	Duration    int64                    `json:"duration_ms"`
 	Timestamp   int64                    `json:"timestamp"`
+
+	// Set when completed steps were compensated after a step failed the run
+	Compensations []CompensationResult `json:"compensations,omitempty"`
 }
 
 // StepResult represents the result of a single step execution
 type StepResult struct {
-	StepID      string      `json:"step_id"`
-	StepName    string      `json:"step_name"`
-	StepType    string      `json:"step_type"`
-	Success     bool        `json:"success"`
-	Output      interface{} `json:"output,omitempty"`
-	Error       string      `json:"error,omitempty"`
-	DurationMs  int64       `json:"duration_ms"`
+	StepID      string       `json:"step_id"`
+	StepName    string       `json:"step_name"`
+	StepType    string       `json:"step_type"`
+	Success     bool         `json:"success"`
+	Output      interface{}  `json:"output,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	DurationMs  int64        `json:"duration_ms"`
+	Attempts    int32        `json:"attempts,omitempty"` // activity attempts made, unset when unknown
+	StartedAt   time.Time    `json:"started_at"`
+	Children    []StepResult `json:"children,omitempty"` // nested steps run by condition/parallel steps
+	Config      map[string]interface{} `json:"config,omitempty"` // rendered config, set in dry runs
+}
+
+// runner holds the state shared by every step of a single DynamicWorkflow run
+type runner struct {
+	input     DynamicWorkflowInput
+	defaultAO workflow.ActivityOptions
+
+	// stepOutputs stores raw step outputs for use in subsequent steps
+	stepOutputs map[string]interface{}
+
+	// steps exposes normalized step state (output, success, error) to expressions
+	steps    map[string]interface{}
+	previous map[string]interface{}
+
+	// vars holds loop variables such as ${item} and ${index}
+	vars map[string]interface{}
+
+	// approvals is shared with forked runners
+	approvals *approvals
+
+	// countAttempts retries activity-backed steps in the workflow so their
+	// attempts can be counted
+	countAttempts bool
+
+	// strictDurations fails steps with an invalid timeout or retry policy
+	// rather than ignoring it
+	strictDurations bool
+}
+
+func newRunner(input DynamicWorkflowInput, defaultAO workflow.ActivityOptions) *runner {
+	return &runner{
+		input:     input,
+		defaultAO: defaultAO,
+		stepOutputs: map[string]interface{}{
+			"input": input.Input,
+		},
+		steps:     make(map[string]interface{}),
+		approvals: newApprovals(),
+	}
+}
+
+// fork returns a runner for a nested scope (e.g. a loop iteration) that sees
+// the current step state plus vars, without leaking its own step results
+// back to the parent
+func (r *runner) fork(vars map[string]interface{}) *runner {
+	child := &runner{
+		input:       r.input,
+		defaultAO:   r.defaultAO,
+		stepOutputs: make(map[string]interface{}, len(r.stepOutputs)),
+		steps:       make(map[string]interface{}, len(r.steps)),
+		previous:    r.previous,
+		vars:        make(map[string]interface{}, len(r.vars)+len(vars)),
+		approvals:   r.approvals,
+
+		countAttempts:   r.countAttempts,
+		strictDurations: r.strictDurations,
+	}
+	for k, v := range r.stepOutputs {
+		child.stepOutputs[k] = v
+	}
+	for k, v := range r.steps {
+		child.steps[k] = v
+	}
+	for k, v := range r.vars {
+		child.vars[k] = v
+	}
+	for k, v := range vars {
+		child.vars[k] = normalizeValue(v)
+	}
+	return child
+}
+
+// merge brings the step results of a forked runner back into r, so steps
+// after a parallel step can reference the steps its branches ran
+func (r *runner) merge(child *runner) {
+	for k, v := range child.stepOutputs {
+		r.stepOutputs[k] = v
+	}
+	for k, v := range child.steps {
+		r.steps[k] = v
+	}
+}
+
+// scope returns the data visible to expressions and templates, e.g.
+// ${input.host}, ${steps.check_health.output.status_code}, ${execution.id}
+// or ${env.tenant_id}, plus any loop variables
+func (r *runner) scope() map[string]interface{} {
+	scope := map[string]interface{}{
+		"input":    normalizeValue(r.input.Input),
+		"steps":    r.steps,
+		"previous": r.previous,
+		"execution": map[string]interface{}{
+			"id":          r.input.ExecutionID,
+			"workflow_id": r.input.WorkflowID,
+			"name":        r.input.Name,
+		},
+		"env": map[string]interface{}{
+			"tenant_id": r.input.TenantID,
+		},
+	}
+	for k, v := range r.vars {
+		scope[k] = v
+	}
+	return scope
+}
+
+// renderStepConfig resolves ${...} references in the step config. Script code
+// is left untouched since JavaScript template literals use the same syntax.
+func (r *runner) renderStepConfig(step StepDefinition) (map[string]interface{}, error) {
+	if len(step.Config) == 0 {
+		return step.Config, nil
+	}
+
+	config := step.Config
+	var code interface{}
+	if step.Type == StepTypeScript {
+		if c, ok := step.Config["code"]; ok {
+			code = c
+			config = make(map[string]interface{}, len(step.Config))
+			for k, v := range step.Config {
+				if k != "code" {
+					config[k] = v
+				}
+			}
+		}
+	}
+
+	rendered, err := RenderConfig(config, r.scope())
+	if err != nil {
+		return nil, err
+	}
+	if code != nil {
+		rendered["code"] = code
+	}
+	return rendered, nil
+}
+
+// record stores the outcome of a step so later steps can reference it
+func (r *runner) record(step StepDefinition, result StepResult) {
+	state := map[string]interface{}{
+		"success": result.Success,
+		"output":  normalizeValue(result.Output),
+		"error":   result.Error,
+	}
+	r.steps[step.ID] = state
+	r.previous = state
+
+	if result.Success {
+		r.stepOutputs[step.ID] = result.Output
+	}
 }
 
 // DynamicWorkflow executes a workflow based on its definition
@@ -56,6 +229,9 @@ func DynamicWorkflow(ctx workflow.Context, input DynamicWorkflowInput) (*Dynamic
 		StepResults: []StepResult{},
 	}
 
+	// Persist the outcome on the execution record however the run ends
+	defer recordOutcome(ctx, input, output, startTime)
+
 	// Parse the workflow definition
 	def, err := ParseDefinition(input.Definition)
 	if err != nil {
@@ -65,10 +241,6 @@ func DynamicWorkflow(ctx workflow.Context, input DynamicWorkflowInput) (*Dynamic
 		return output, nil
 	}
 
-	// Create context for storing step outputs
-	stepOutputs := make(map[string]interface{})
-	stepOutputs["input"] = input.Input
-
 	// Default activity options
 	defaultAO := workflow.ActivityOptions{
 		StartToCloseTimeout: 10 * time.Minute,
@@ -80,142 +252,374 @@ func DynamicWorkflow(ctx workflow.Context, input DynamicWorkflowInput) (*Dynamic
 		},
 	}
 
-	// Execute each step
-	for i, step := range def.Steps {
-		stepStart := workflow.Now(ctx)
-		logger.Info("Executing step", "step_id", step.ID, "step_name", step.Name, "step_type", step.Type)
+	// Executions started before the definition's timeout and retry policy
+	// were honored ran with the defaults and ignored invalid durations
+	var timeout time.Duration
+	strict := workflow.GetVersion(ctx, "definition-policy", workflow.DefaultVersion, 1) != workflow.DefaultVersion
+	if strict {
+		if timeout, err = def.TimeoutDuration(); err == nil && def.RetryPolicy != nil {
+			defaultAO.RetryPolicy, err = def.RetryPolicy.TemporalPolicy()
+		}
+		if err != nil {
+			logger.Error("invalid definition", "error", err)
+			output.Status = "failed"
+			output.Error = fmt.Sprintf("invalid definition: %v", err)
+			return output, nil
+		}
+	}
+
+	r := newRunner(input, defaultAO)
+	r.strictDurations = strict
+	if err := r.approvals.register(ctx); err != nil {
+		logger.Error("failed to register approval handlers", "error", err)
+		output.Status = "failed"
+		output.Error = fmt.Sprintf("failed to register approval handlers: %v", err)
+		return output, nil
+	}
+
+	steps := newTimeline(input)
+	if err := steps.register(ctx); err != nil {
+		logger.Error("failed to register steps query", "error", err)
+		output.Status = "failed"
+		output.Error = fmt.Sprintf("failed to register steps query: %v", err)
+		return output, nil
+	}
 
-		result, err := executeStep(ctx, step, stepOutputs, defaultAO)
+	pauses := &pause{}
+	pauses.register(ctx)
+	if err := registerProgress(ctx, pauses, steps, r, len(def.Steps)); err != nil {
+		logger.Error("failed to register progress query", "error", err)
+		output.Status = "failed"
+		output.Error = fmt.Sprintf("failed to register progress query: %v", err)
+		return output, nil
+	}
 
-		stepDuration := workflow.Now(ctx).Sub(stepStart).Milliseconds()
+	// Executions started before the step timeline retried activities in
+	// Temporal and didn't record their steps
+	if workflow.GetVersion(ctx, "step-timeline", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		r.countAttempts = true
+		steps.record = true
+	}
 
-		stepResult := StepResult{
-			StepID:     step.ID,
-			StepName:   step.Name,
-			StepType:   string(step.Type),
-			DurationMs: stepDuration,
+	// Steps run on a context that is cancelled when the run times out, so
+	// the run can still record that it timed out and clean up after itself
+	runCtx := ctx
+	timedOut := false
+	if timeout > 0 {
+		var cancelRun workflow.CancelFunc
+		runCtx, cancelRun = workflow.WithCancel(ctx)
+		defer cancelRun()
+		workflow.Go(runCtx, func(gCtx workflow.Context) {
+			if workflow.NewTimer(gCtx, timeout).Get(gCtx, nil) == nil {
+				timedOut = true
+				cancelRun()
+			}
+		})
+	}
+
+	// Execute each step, keeping track of the completed ones to compensate
+	var completed []int
+	for i, step := range def.Steps {
+		if runCtx.Err() != nil {
+			break // cancelled or timed out, don't start any more steps
 		}
 
-		if err != nil {
-			stepResult.Success = false
-			stepResult.Error = err.Error()
-			output.StepResults = append(output.StepResults, stepResult)
+		if input.Resume != nil && i < input.Resume.StartAt {
+			// Already ran in the execution being retried
+			if state, ok := input.Resume.resumedStep(i); ok {
+				stepResult := resumedResult(step, state)
+				r.record(step, stepResult)
+				steps.restore(state)
+				output.StepResults = append(output.StepResults, stepResult)
+				if stepResult.Success {
+					r.stepOutputs[fmt.Sprintf("step_%d", i)] = stepResult.Output
+					completed = append(completed, i)
+				}
+			}
+			continue
+		}
+
+		if err := pauses.wait(runCtx); err != nil {
+			break // cancelled or timed out while paused
+		}
 
+		logger.Info("Executing step", "step_id", step.ID, "step_name", step.Name, "step_type", step.Type)
+
+		steps.start(runCtx, i, step)
+		stepResult := r.runStep(runCtx, step)
+		steps.finish(runCtx, stepResult)
+		output.StepResults = append(output.StepResults, stepResult)
+
+		if !stepResult.Success {
 			if !step.ContinueOnError {
-				logger.Error("step failed", "step_id", step.ID, "error", err)
+				logger.Error("step failed", "step_id", step.ID, "error", stepResult.Error)
 				output.Status = "failed"
-				output.Error = fmt.Sprintf("step %s failed: %v", step.ID, err)
+				output.Error = fmt.Sprintf("step %s failed: %s", step.ID, stepResult.Error)
 				break
 			}
-			logger.Warn("step failed but continuing", "step_id", step.ID, "error", err)
+			logger.Warn("step failed but continuing", "step_id", step.ID, "error", stepResult.Error)
 		} else {
-			stepResult.Success = true
-			stepResult.Output = result
-			output.StepResults = append(output.StepResults, stepResult)
-
-			// Store step output for use in subsequent steps
-			stepOutputs[step.ID] = result
-			stepOutputs[fmt.Sprintf("step_%d", i)] = result
+			r.stepOutputs[fmt.Sprintf("step_%d", i)] = stepResult.Output
+			completed = append(completed, i)
 		}
 	}
 
 	// Set final status
-	if output.Status == "" {
+	switch {
+	case timedOut:
+		output.Status = "timed_out"
+		output.Error = fmt.Sprintf("execution timed out after %s", def.Timeout)
+	case ctx.Err() != nil:
+		output.Status = "cancelled"
+		output.Error = "execution cancelled"
+	case output.Status == "":
 		output.Status = "completed"
 	}
 
+	// Undo the completed steps before on_error runs
+	failed := output.Status == "failed" || output.Status == "timed_out"
+	if failed {
+		output.Compensations = r.compensate(ctx, def.Steps, completed, steps)
+	}
+
 	// Execute on_success or on_error steps
 	if output.Status == "completed" && len(def.OnSuccess) > 0 {
 		for _, step := range def.OnSuccess {
-			_, _ = executeStep(ctx, step, stepOutputs, defaultAO)
+			_, _, _, _ = r.executeStep(ctx, step)
 		}
-	} else if output.Status == "failed" && len(def.OnError) > 0 {
+	} else if failed && len(def.OnError) > 0 {
 		for _, step := range def.OnError {
-			_, _ = executeStep(ctx, step, stepOutputs, defaultAO)
+			_, _, _, _ = r.executeStep(ctx, step)
 		}
 	}
 
 	endTime := workflow.Now(ctx)
 	output.Duration = endTime.Sub(startTime).Milliseconds()
 	output.Timestamp = endTime.Unix()
-	output.Output = stepOutputs
+	output.Output = r.stepOutputs
 
 	logger.Info("DynamicWorkflow completed",
 		"execution_id", input.ExecutionID,
 		"status", output.Status,
 		"duration_ms", output.Duration)
 
+	// Report cancellation to Temporal so the run shows up as cancelled there too
+	if output.Status == "cancelled" {
+		return output, ctx.Err()
+	}
 	return output, nil
 }
 
-// executeStep executes a single step based on its type
-func executeStep(ctx workflow.Context, step StepDefinition, stepOutputs map[string]interface{}, defaultAO workflow.ActivityOptions) (interface{}, error) {
-	// Apply step-specific timeout if specified
-	ao := defaultAO
+// recordOutcome writes the final status, output and error of a run to its
+// execution record. It runs on a disconnected context so cancelled runs are
+// recorded too. Runs without an execution record are skipped.
+func recordOutcome(ctx workflow.Context, input DynamicWorkflowInput, output *DynamicWorkflowOutput, startTime time.Time) {
+	if input.ExecutionID == "" || input.TenantID == "" {
+		return
+	}
+	ctx, cancel := workflow.NewDisconnectedContext(ctx)
+	defer cancel()
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	err := workflow.ExecuteActivity(ctx, "CompleteExecution", activity.CompleteExecutionInput{
+		TenantID:    input.TenantID,
+		ExecutionID: input.ExecutionID,
+		Status:      output.Status,
+		Output:      output,
+		Error:       output.Error,
+		StartedAt:   startTime,
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("failed to record execution outcome",
+			"execution_id", input.ExecutionID,
+			"error", err)
+	}
+}
+
+// runStep executes a single step, records its outcome and returns its result
+func (r *runner) runStep(ctx workflow.Context, step StepDefinition) StepResult {
+	stepStart := workflow.Now(ctx)
+
+	// Render the config for the trace before the step changes the state
+	// references resolve against
+	var config map[string]interface{}
+	if r.input.DryRun != nil {
+		config, _ = r.renderStepConfig(step)
+	}
+
+	result, children, attempts, err := r.executeStep(ctx, step)
+
+	stepResult := StepResult{
+		StepID:     step.ID,
+		StepName:   step.Name,
+		StepType:   string(step.Type),
+		DurationMs: workflow.Now(ctx).Sub(stepStart).Milliseconds(),
+		Attempts:   attempts,
+		StartedAt:  stepStart,
+		Children:   children,
+		Config:     config,
+	}
+
+	if err != nil {
+		stepResult.Success = false
+		stepResult.Error = err.Error()
+	} else {
+		stepResult.Success = true
+		stepResult.Output = result
+	}
+
+	r.record(step, stepResult)
+	return stepResult
+}
+
+// runSteps executes a nested list of steps sequentially, stopping at the
+// first failure unless the failing step has continue_on_error set
+func (r *runner) runSteps(ctx workflow.Context, steps []StepDefinition) ([]StepResult, error) {
+	results := make([]StepResult, 0, len(steps))
+	for _, step := range steps {
+		stepResult := r.runStep(ctx, step)
+		results = append(results, stepResult)
+
+		if !stepResult.Success && !step.ContinueOnError {
+			return results, fmt.Errorf("step %s failed: %s", step.ID, stepResult.Error)
+		}
+	}
+	return results, nil
+}
+
+// activityOptions returns the run's default activity options with the
+// step's timeout and retry policy applied
+func (r *runner) activityOptions(step StepDefinition) (workflow.ActivityOptions, error) {
+	ao := r.defaultAO
 	if step.Timeout != "" {
-		if timeout, err := time.ParseDuration(step.Timeout); err == nil {
+		timeout, err := time.ParseDuration(step.Timeout)
+		if err == nil && timeout > 0 {
 			ao.StartToCloseTimeout = timeout
+		} else if r.strictDurations {
+			return ao, fmt.Errorf("invalid timeout %q", step.Timeout)
 		}
 	}
 
-	// Apply step-specific retry policy if specified
 	if step.RetryPolicy != nil {
-		ao.RetryPolicy = &temporal.RetryPolicy{
-			MaximumAttempts: int32(step.RetryPolicy.MaxAttempts),
-		}
-		if step.RetryPolicy.InitialInterval != "" {
-			if d, err := time.ParseDuration(step.RetryPolicy.InitialInterval); err == nil {
-				ao.RetryPolicy.InitialInterval = d
-			}
-		}
-		if step.RetryPolicy.MaxInterval != "" {
-			if d, err := time.ParseDuration(step.RetryPolicy.MaxInterval); err == nil {
-				ao.RetryPolicy.MaximumInterval = d
-			}
-		}
-		if step.RetryPolicy.Multiplier > 0 {
-			ao.RetryPolicy.BackoffCoefficient = step.RetryPolicy.Multiplier
+		policy, err := step.RetryPolicy.TemporalPolicy()
+		if err != nil && r.strictDurations {
+			return ao, err
 		}
+		ao.RetryPolicy = policy
+	}
+	return ao, nil
+}
+
+// executeStep executes a single step based on its type. It returns the
+// activity attempts made by activity-backed steps, 1 for other steps.
+func (r *runner) executeStep(ctx workflow.Context, step StepDefinition) (interface{}, []StepResult, int32, error) {
+	ao, err := r.activityOptions(step)
+	if err != nil {
+		return nil, nil, 1, err
+	}
+
+	config, err := r.renderStepConfig(step)
+	if err != nil {
+		return nil, nil, 1, err
+	}
+	step.Config = config
+
+	if r.input.DryRun != nil && simulatedSteps[step.Type] {
+		result, err := r.simulateStep(ctx, step)
+		return result, nil, 1, err
 	}
 
 	actCtx := workflow.WithActivityOptions(ctx, ao)
+	stepOutputs := r.stepOutputs
+
+	var result interface{}
+	attempts := int32(1)
 
 	switch step.Type {
 	case StepTypeHTTP:
-		return executeHTTPStep(actCtx, step.Config)
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return executeHTTPStep(ctx, step.Config, r.input.TenantID)
+		})
 
 	case StepTypeDelay:
-		return executeDelayStep(actCtx, step.Config)
+		result, err = executeDelayStep(actCtx, step.Config)
 
 	case StepTypeLog:
-		return executeLogStep(actCtx, step.Config)
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return executeLogStep(ctx, step.Config)
+		})
 
 	case StepTypeNotify:
-		return executeNotifyStep(actCtx, step.Config, stepOutputs)
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return executeNotifyStep(ctx, step.Config, r.input.ExecutionID, stepOutputs)
+		})
 
 	case StepTypeValidate:
-		return executeValidateStep(actCtx, step.Config, stepOutputs)
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return executeValidateStep(ctx, step.Config, stepOutputs)
+		})
 
 	case StepTypeProcess:
-		return executeProcessStep(actCtx, step.Config, stepOutputs)
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return executeProcessStep(ctx, step.Config, stepOutputs)
+		})
+
+	case StepTypeScript:
+		result, attempts, err = r.withRetries(actCtx, func(ctx workflow.Context) (interface{}, error) {
+			return r.executeScriptStep(ctx, step)
+		})
+
+	case StepTypeWaitForMetric:
+		result, err = r.executeWaitForMetricStep(actCtx, step)
+
+	case StepTypeApproval:
+		result, err = r.executeApprovalStep(ctx, step)
+
+	case StepTypeChildWorkflow:
+		result, err = r.executeChildWorkflowStep(actCtx, step)
+
+	case StepTypeCondition:
+		condResult, children, err := r.executeConditionStep(ctx, step)
+		return condResult, children, 1, err
+
+	case StepTypeParallel:
+		parResult, children, err := r.executeParallelStep(ctx, step)
+		return parResult, children, 1, err
+
+	case StepTypeForeach:
+		loopResult, children, err := r.executeForeachStep(ctx, step)
+		return loopResult, children, 1, err
 
 	default:
-		return nil, fmt.Errorf("unknown step type: %s", step.Type)
+		return nil, nil, 1, fmt.Errorf("unknown step type: %s", step.Type)
 	}
+
+	return result, nil, attempts, err
 }
 
-func executeHTTPStep(ctx workflow.Context, config map[string]interface{}) (*activity.HTTPResult, error) {
+func executeHTTPStep(ctx workflow.Context, config map[string]interface{}, tenantID string) (*activity.HTTPResult, error) {
 	cfg, err := ParseHTTPConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("invalid HTTP config: %w", err)
 	}
 
 	input := activity.HTTPInput{
+		TenantID:     tenantID,
 		URL:          cfg.URL,
 		Method:       cfg.Method,
 		Headers:      cfg.Headers,
 		Body:         cfg.Body,
 		SuccessCodes: cfg.SuccessCodes,
+		Extract:      cfg.Extract,
+		Assert:       cfg.Assert,
 	}
 
 	var result activity.HTTPResult
@@ -223,19 +627,46 @@ func executeHTTPStep(ctx workflow.Context, config map[string]interface{}) (*acti
 	return &result, err
 }
 
-func executeDelayStep(ctx workflow.Context, config map[string]interface{}) (*activity.DelayResult, error) {
+// DelayResult is the output of a delay step
+type DelayResult struct {
+	Delayed    bool      `json:"delayed"`
+	DurationMs int64     `json:"duration_ms"`
+	Until      time.Time `json:"until"`
+}
+
+// executeDelayStep waits on a durable workflow timer, so long waits don't hold
+// a worker slot and survive worker restarts
+func executeDelayStep(ctx workflow.Context, config map[string]interface{}) (interface{}, error) {
 	cfg, err := ParseDelayConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("invalid delay config: %w", err)
 	}
 
-	input := activity.DelayInput{
-		Duration: cfg.Duration,
+	// Executions started before durable timers slept inside the Delay activity
+	if workflow.GetVersion(ctx, "durable-delay", workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		var result activity.DelayResult
+		err = workflow.ExecuteActivity(ctx, "Delay", activity.DelayInput{Duration: cfg.Duration}).Get(ctx, &result)
+		return &result, err
 	}
 
-	var result activity.DelayResult
-	err = workflow.ExecuteActivity(ctx, "Delay", input).Get(ctx, &result)
-	return &result, err
+	now := workflow.Now(ctx)
+	wait, err := cfg.Wait(now)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delay config: %w", err)
+	}
+
+	if wait > 0 {
+		workflow.GetLogger(ctx).Info("Sleeping", "duration", wait)
+		if err := workflow.Sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DelayResult{
+		Delayed:    true,
+		DurationMs: wait.Milliseconds(),
+		Until:      now.Add(wait),
+	}, nil
 }
 
 func executeLogStep(ctx workflow.Context, config map[string]interface{}) (*activity.LogResult, error) {
@@ -254,17 +685,18 @@ func executeLogStep(ctx workflow.Context, config map[string]interface{}) (*activ
 	return &result, err
 }
 
-func executeNotifyStep(ctx workflow.Context, config map[string]interface{}, stepOutputs map[string]interface{}) (*activity.NotifyResult, error) {
+func executeNotifyStep(ctx workflow.Context, config map[string]interface{}, executionID string, stepOutputs map[string]interface{}) (*activity.NotifyResult, error) {
 	cfg, err := ParseNotifyConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("invalid notify config: %w", err)
 	}
 
-	// Get execution ID from step outputs
-	executionID := ""
-	if input, ok := stepOutputs["input"].(map[string]interface{}); ok {
-		if id, ok := input["execution_id"].(string); ok {
-			executionID = id
+	// Fall back to an execution ID passed in the input
+	if executionID == "" {
+		if input, ok := stepOutputs["input"].(map[string]interface{}); ok {
+			if id, ok := input["execution_id"].(string); ok {
+				executionID = id
+			}
 		}
 	}
 
@@ -0,0 +1,61 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdkactivity "go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// stubHTTP answers HTTP steps without a network: requests to a URL
+// containing "fail" fail without retries, others succeed with the URL as
+// the body
+func stubHTTP(ctx context.Context, input activity.HTTPInput) (*activity.HTTPResult, error) {
+	if strings.Contains(input.URL, "fail") {
+		return nil, temporal.NewNonRetryableApplicationError("connection refused", "HTTPError", nil)
+	}
+	return &activity.HTTPResult{StatusCode: 200, Body: input.URL, Success: true}, nil
+}
+
+// newTestEnv returns a test environment for DynamicWorkflow with the Log
+// activity and stubHTTP registered
+func newTestEnv() *testsuite.TestWorkflowEnvironment {
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(DynamicWorkflow)
+	env.RegisterActivity(activity.NewActivities().Log)
+	env.RegisterActivityWithOptions(stubHTTP, sdkactivity.RegisterOptions{Name: "HTTP"})
+	return env
+}
+
+// runDefinition runs definition in env and returns the output of the run.
+// The run has no execution record, so nothing is recorded.
+func runDefinition(t *testing.T, env *testsuite.TestWorkflowEnvironment, definition string) *DynamicWorkflowOutput {
+	t.Helper()
+	env.ExecuteWorkflow(DynamicWorkflow, DynamicWorkflowInput{
+		WorkflowID: "test",
+		Name:       "test",
+		Definition: json.RawMessage(definition),
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var output DynamicWorkflowOutput
+	require.NoError(t, env.GetWorkflowResult(&output))
+	return &output
+}
+
+// decodeOutput decodes a step output into out
+func decodeOutput(t *testing.T, output interface{}, out interface{}) {
+	t.Helper()
+	data, err := json.Marshal(output)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, out))
+}
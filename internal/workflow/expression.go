@@ -0,0 +1,865 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expressions are evaluated inside workflow code, so evaluation must be
+// deterministic: no I/O, no clock, no randomness and no map-order dependence.
+//
+// Grammar (lowest to highest precedence):
+//
+//	or      = and { ("||" | "or") and }
+//	and     = equality { ("&&" | "and") equality }
+//	equality = compare { ("==" | "!=") compare }
+//	compare = additive { ("<" | "<=" | ">" | ">=") additive }
+//	additive = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = ("!" | "not" | "-") unary | primary
+//	primary = number | string | "true" | "false" | "null"
+//	        | "${" path "}" | path | ident "(" [ or { "," or } ] ")" | "(" or ")"
+//
+// Paths are dot separated (e.g. steps.check_health.output.status_code) and
+// numeric segments index into arrays. Step IDs containing dashes must use the
+// ${...} form, since a bare "-" is the minus operator.
+
+// Expression is a compiled condition expression
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// CompileExpression parses an expression so it can be evaluated repeatedly
+func CompileExpression(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("expression %q: unexpected %q at position %d", source, tok.text, tok.pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression against the given scope
+func (e *Expression) Eval(scope map[string]interface{}) (interface{}, error) {
+	v, err := e.root.eval(scope)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.source, err)
+	}
+	return v, nil
+}
+
+// EvalBool evaluates the expression and converts the result to a boolean
+func (e *Expression) EvalBool(scope map[string]interface{}) (bool, error) {
+	v, err := e.Eval(scope)
+	if err != nil {
+		return false, err
+	}
+	return truthy(v), nil
+}
+
+// References returns the paths referenced by the expression
+func (e *Expression) References() []string {
+	var refs []string
+	e.root.walk(func(n exprNode) {
+		if p, ok := n.(*pathNode); ok {
+			refs = append(refs, strings.Join(p.segments, "."))
+		}
+	})
+	return refs
+}
+
+// EvaluateCondition compiles and evaluates a condition in one call
+func EvaluateCondition(source string, scope map[string]interface{}) (bool, error) {
+	expr, err := CompileExpression(source)
+	if err != nil {
+		return false, err
+	}
+	return expr.EvalBool(scope)
+}
+
+// ============================================================================
+// Lexer
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokRef
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var twoCharOps = []string{"==", "!=", "<=", ">=", "&&", "||"}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '$' && i+1 < len(src) && src[i+1] == '{':
+			end := strings.IndexByte(src[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated reference at position %d", i)
+			}
+			path := strings.TrimSpace(src[i+2 : i+end])
+			if path == "" {
+				return nil, fmt.Errorf("empty reference at position %d", i)
+			}
+			tokens = append(tokens, token{kind: tokRef, text: path, pos: i})
+			i += end + 1
+		case c == '"' || c == '\'':
+			s, n, err := readString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			tokens = append(tokens, token{kind: tokString, text: s, pos: i})
+			i += n
+		case isDigit(c):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: src[start:i], pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentPart(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		case c == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+		default:
+			matched := false
+			for _, op := range twoCharOps {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+					i += 2
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if strings.ContainsRune("<>!+-*/%", rune(c)) {
+				tokens = append(tokens, token{kind: tokOp, text: string(c), pos: i})
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+func readString(src string) (string, int, error) {
+	quote := src[0]
+	var sb strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		if c == '\\' && i+1 < len(src) {
+			i++
+			switch src[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(src[i])
+			}
+			continue
+		}
+		if c == quote {
+			return sb.String(), i + 1, nil
+		}
+		sb.WriteByte(c)
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool      { return c >= '0' && c <= '9' }
+func isIdentStart(c byte) bool { return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isIdentPart(c byte) bool  { return isIdentStart(c) || isDigit(c) }
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// matchOp consumes the next token if it is one of the given operators.
+// The keywords and/or/not are accepted as aliases of &&, || and !.
+func (p *exprParser) matchOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	text := tok.text
+	if tok.kind == tokIdent {
+		switch text {
+		case "and":
+			text = "&&"
+		case "or":
+			text = "||"
+		case "not":
+			text = "!"
+		default:
+			return "", false
+		}
+	} else if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if text == op {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseBinary(next func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := next()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.matchOp(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := next()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseEquality, "&&")
+}
+
+func (p *exprParser) parseEquality() (exprNode, error) {
+	return p.parseBinary(p.parseCompare, "==", "!=")
+}
+
+func (p *exprParser) parseCompare() (exprNode, error) {
+	return p.parseBinary(p.parseAdditive, "<=", ">=", "<", ">")
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	return p.parseBinary(p.parseTerm, "+", "-")
+}
+
+func (p *exprParser) parseTerm() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.matchOp("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return &literalNode{value: f}, nil
+	case tokString:
+		return &literalNode{value: tok.text}, nil
+	case tokRef:
+		return newPathNode(tok.text), nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null", "nil":
+			return &literalNode{value: nil}, nil
+		}
+		if p.peek().kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		return newPathNode(tok.text), nil
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return inner, nil
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+}
+
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+	}
+	p.next() // consume '('
+
+	var args []exprNode
+	if p.peek().kind != tokRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+	}
+	if len(args) != fn.arity {
+		return nil, fmt.Errorf("%s expects %d argument(s), got %d", name.text, fn.arity, len(args))
+	}
+	return &callNode{name: name.text, fn: fn.call, args: args}, nil
+}
+
+// ============================================================================
+// AST
+// ============================================================================
+
+type exprNode interface {
+	eval(scope map[string]interface{}) (interface{}, error)
+	walk(fn func(exprNode))
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) eval(map[string]interface{}) (interface{}, error) { return n.value, nil }
+func (n *literalNode) walk(fn func(exprNode))                           { fn(n) }
+
+type pathNode struct {
+	segments []string
+}
+
+func newPathNode(path string) *pathNode {
+	return &pathNode{segments: splitPath(path)}
+}
+
+// eval resolves the path; missing values evaluate to null so conditions can
+// test for the absence of a step output (e.g. a step that failed).
+func (n *pathNode) eval(scope map[string]interface{}) (interface{}, error) {
+	v, _ := lookupPath(scope, n.segments)
+	return v, nil
+}
+
+func (n *pathNode) walk(fn func(exprNode)) { fn(n) }
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(scope map[string]interface{}) (interface{}, error) {
+	v, err := n.operand.eval(scope)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "!" {
+		return !truthy(v), nil
+	}
+	f, ok := toNumber(v)
+	if !ok {
+		return nil, fmt.Errorf("cannot negate %s", describe(v))
+	}
+	return -f, nil
+}
+
+func (n *unaryNode) walk(fn func(exprNode)) {
+	fn(n)
+	n.operand.walk(fn)
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(scope map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short-circuit boolean operators
+	switch n.op {
+	case "&&":
+		if !truthy(left) {
+			return false, nil
+		}
+		right, err := n.right.eval(scope)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(right), nil
+	case "||":
+		if truthy(left) {
+			return true, nil
+		}
+		right, err := n.right.eval(scope)
+		if err != nil {
+			return nil, err
+		}
+		return truthy(right), nil
+	}
+
+	right, err := n.right.eval(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return valuesEqual(left, right), nil
+	case "!=":
+		return !valuesEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compareValues(n.op, left, right)
+	default:
+		return arithmetic(n.op, left, right)
+	}
+}
+
+func (n *binaryNode) walk(fn func(exprNode)) {
+	fn(n)
+	n.left.walk(fn)
+	n.right.walk(fn)
+}
+
+type callNode struct {
+	name string
+	fn   func(args []interface{}) (interface{}, error)
+	args []exprNode
+}
+
+func (n *callNode) eval(scope map[string]interface{}) (interface{}, error) {
+	args := make([]interface{}, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(scope)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+func (n *callNode) walk(fn func(exprNode)) {
+	fn(n)
+	for _, a := range n.args {
+		a.walk(fn)
+	}
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+type exprFunc struct {
+	arity int
+	call  func(args []interface{}) (interface{}, error)
+}
+
+var exprFuncs = map[string]exprFunc{
+	"len": {1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(len(v)), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, fmt.Errorf("unsupported argument %s", describe(args[0]))
+	}},
+	"contains": {2, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.Contains(v, toString(args[1])), nil
+		case []interface{}:
+			for _, item := range v {
+				if valuesEqual(item, args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case map[string]interface{}:
+			_, ok := v[toString(args[1])]
+			return ok, nil
+		case nil:
+			return false, nil
+		}
+		return nil, fmt.Errorf("unsupported argument %s", describe(args[0]))
+	}},
+	"starts_with": {2, func(args []interface{}) (interface{}, error) {
+		return strings.HasPrefix(toString(args[0]), toString(args[1])), nil
+	}},
+	"ends_with": {2, func(args []interface{}) (interface{}, error) {
+		return strings.HasSuffix(toString(args[0]), toString(args[1])), nil
+	}},
+	"matches": {2, func(args []interface{}) (interface{}, error) {
+		re, err := regexp.Compile(toString(args[1]))
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(toString(args[0])), nil
+	}},
+	"lower": {1, func(args []interface{}) (interface{}, error) {
+		return strings.ToLower(toString(args[0])), nil
+	}},
+	"upper": {1, func(args []interface{}) (interface{}, error) {
+		return strings.ToUpper(toString(args[0])), nil
+	}},
+	"number": {1, func(args []interface{}) (interface{}, error) {
+		f, ok := toNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("cannot convert %s to number", describe(args[0]))
+		}
+		return f, nil
+	}},
+	"string": {1, func(args []interface{}) (interface{}, error) {
+		return toString(args[0]), nil
+	}},
+}
+
+// ============================================================================
+// Value helpers
+// ============================================================================
+
+// splitPath splits a dotted path, accepting "a[0]" as an alias of "a.0"
+func splitPath(path string) []string {
+	path = strings.NewReplacer("[", ".", "]", "").Replace(path)
+	parts := strings.Split(path, ".")
+	segments := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			segments = append(segments, p)
+		}
+	}
+	return segments
+}
+
+// lookupPath walks a normalized value (maps, slices, scalars) by segments.
+// Strings holding a JSON object or array are decoded on the fly so fields of
+// raw HTTP response bodies can be addressed directly.
+func lookupPath(root interface{}, segments []string) (interface{}, bool) {
+	current := root
+	for _, seg := range segments {
+		if s, ok := current.(string); ok {
+			current = decodeJSONString(s)
+		}
+		switch v := current.(type) {
+		case map[string]interface{}:
+			next, ok := v[seg]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []interface{}:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false
+			}
+			current = v[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func decodeJSONString(s string) interface{} {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return s
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+		return s
+	}
+	return v
+}
+
+// normalizeValue converts arbitrary Go values (structs, typed maps) into the
+// generic JSON representation used by expressions and templates.
+func normalizeValue(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, string, bool, float64, map[string]interface{}, []interface{}:
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != ""
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	if f, ok := toNumber(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isNumeric(v interface{}) bool {
+	if _, ok := v.(string); ok {
+		return false
+	}
+	_, ok := toNumber(v)
+	return ok
+}
+
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		if val == math.Trunc(val) && math.Abs(val) < 1e15 {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	if f, ok := toNumber(v); ok {
+		return toString(f)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	if isNumeric(v) {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// valuesEqual compares two values; numbers compare numerically and a numeric
+// string equals the number it represents (e.g. "200" == 200).
+func valuesEqual(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if isNumeric(a) || isNumeric(b) {
+		fa, okA := toNumber(a)
+		fb, okB := toNumber(b)
+		return okA && okB && fa == fb
+	}
+	switch av := a.(type) {
+	case string:
+		bv, ok := b.(string)
+		return ok && av == bv
+	case bool:
+		bv, ok := b.(bool)
+		return ok && av == bv
+	}
+	return toString(a) == toString(b)
+}
+
+func compareValues(op string, a, b interface{}) (bool, error) {
+	var cmp int
+	if isNumeric(a) || isNumeric(b) {
+		fa, okA := toNumber(a)
+		fb, okB := toNumber(b)
+		if !okA || !okB {
+			return false, fmt.Errorf("cannot compare %s %s %s", describe(a), op, describe(b))
+		}
+		switch {
+		case fa < fb:
+			cmp = -1
+		case fa > fb:
+			cmp = 1
+		}
+	} else {
+		sa, okA := a.(string)
+		sb, okB := b.(string)
+		if !okA || !okB {
+			return false, fmt.Errorf("cannot compare %s %s %s", describe(a), op, describe(b))
+		}
+		cmp = strings.Compare(sa, sb)
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+func arithmetic(op string, a, b interface{}) (interface{}, error) {
+	if op == "+" {
+		if _, ok := a.(string); ok {
+			return toString(a) + toString(b), nil
+		}
+		if _, ok := b.(string); ok {
+			return toString(a) + toString(b), nil
+		}
+	}
+
+	fa, okA := toNumber(a)
+	fb, okB := toNumber(b)
+	if !okA || !okB {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", op, describe(a), describe(b))
+	}
+
+	switch op {
+	case "+":
+		return fa + fb, nil
+	case "-":
+		return fa - fb, nil
+	case "*":
+		return fa * fb, nil
+	case "/":
+		if fb == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return fa / fb, nil
+	default:
+		if fb == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(fa, fb), nil
+	}
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testScope() map[string]interface{} {
+	return map[string]interface{}{
+		"input": map[string]interface{}{
+			"host":     "db-1",
+			"replicas": float64(3),
+			"tags":     []interface{}{"prod", "eu"},
+		},
+		"steps": map[string]interface{}{
+			"check_health": map[string]interface{}{
+				"success": true,
+				"output": map[string]interface{}{
+					"status_code": float64(503),
+					"body":        `{"status":"degraded","checks":[{"name":"db","ok":false}]}`,
+				},
+			},
+			"restart-pod": map[string]interface{}{
+				"success": false,
+				"error":   "timeout",
+			},
+		},
+	}
+}
+
+func TestEvaluateCondition(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		expected bool
+	}{
+		{"reference comparison", "${steps.check_health.output.status_code} != 200", true},
+		{"bare path comparison", "steps.check_health.output.status_code >= 500", true},
+		{"numeric string equality", `${steps.check_health.output.status_code} == "503"`, true},
+		{"boolean and", "${steps.check_health.success} && input.replicas > 2", true},
+		{"boolean or with keyword", "input.replicas > 5 or input.host == 'db-1'", true},
+		{"negation", "!${steps.restart-pod.success}", true},
+		{"not keyword", "not steps.check_health.success", false},
+		{"json body field", "${steps.check_health.output.body.status} == 'degraded'", true},
+		{"array index", "steps.check_health.output.body.checks.0.ok == false", true},
+		{"bracket index", "${steps.check_health.output.body.checks[0].name} == 'db'", true},
+		{"missing reference is null", "${steps.unknown.output} == null", true},
+		{"arithmetic", "input.replicas * 2 - 1 == 5", true},
+		{"parentheses", "(input.replicas > 5 || true) && false", false},
+		{"contains on array", "contains(input.tags, 'prod')", true},
+		{"len function", "len(input.tags) == 2", true},
+		{"string functions", "starts_with(upper(input.host), 'DB')", true},
+		{"matches", "matches(input.host, '^db-[0-9]+$')", true},
+		{"truthy string", "input.host", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := EvaluateCondition(tt.expr, testScope())
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCompileExpression_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"unterminated string", "input.host == 'db"},
+		{"unterminated reference", "${input.host == 'db'"},
+		{"dangling operator", "input.replicas >"},
+		{"unknown function", "exec('rm -rf /')"},
+		{"wrong arity", "len(input.tags, 1)"},
+		{"unbalanced parentheses", "(input.replicas > 1"},
+		{"invalid character", "input.host ~ 'db'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileExpression(tt.expr)
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExpression_EvalErrors(t *testing.T) {
+	t.Run("division by zero", func(t *testing.T) {
+		_, err := EvaluateCondition("input.replicas / 0 > 1", testScope())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("ordering incompatible types", func(t *testing.T) {
+		_, err := EvaluateCondition("input.tags > 1", testScope())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestExpression_References(t *testing.T) {
+	expr, err := CompileExpression("${steps.a.output.x} > 1 && contains(input.tags, steps.b.output)")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"steps.a.output.x", "input.tags", "steps.b.output"}, expr.References())
+}
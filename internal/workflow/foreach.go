@@ -0,0 +1,228 @@
+package workflow
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// Foreach limits
+const (
+	DefaultForeachMaxIterations = 100
+	MaxForeachIterations        = 1000
+)
+
+// Iteration statuses reported by foreach steps
+const (
+	IterationSucceeded = "succeeded"
+	IterationFailed    = "failed"
+	IterationCancelled = "cancelled"
+	IterationSkipped   = "skipped"
+)
+
+// reservedScopeNames cannot be used as loop variable names
+var reservedScopeNames = map[string]bool{
+	"input":     true,
+	"steps":     true,
+	"previous":  true,
+	"execution": true,
+	"env":       true,
+	"index":     true,
+	"secrets":   true,
+}
+
+// ForeachConfig for foreach step type
+type ForeachConfig struct {
+	Items          interface{} `json:"items"`                     // list or ${...} reference to one
+	As             string      `json:"as,omitempty"`              // loop variable name, default "item"
+	MaxConcurrency int         `json:"max_concurrency,omitempty"` // default 1 (sequential)
+	FailFast       bool        `json:"fail_fast,omitempty"`       // stop on the first failed iteration
+	MaxIterations  int         `json:"max_iterations,omitempty"`  // default 100, at most 1000
+}
+
+// ForeachIteration is the outcome of running the loop body for one item
+type ForeachIteration struct {
+	Index  int                    `json:"index"`
+	Item   interface{}            `json:"item"`
+	Status string                 `json:"status"`
+	Output map[string]interface{} `json:"output,omitempty"` // body step ID -> output
+	Error  string                 `json:"error,omitempty"`
+}
+
+// ForeachResult is the output of a foreach step
+type ForeachResult struct {
+	Total      int                `json:"total"`
+	Succeeded  int                `json:"succeeded"`
+	Failed     int                `json:"failed"`
+	Cancelled  int                `json:"cancelled"`
+	Skipped    int                `json:"skipped"`
+	Iterations []ForeachIteration `json:"iterations"`
+}
+
+// ParseForeachConfig parses the config map into ForeachConfig
+func ParseForeachConfig(config map[string]interface{}) (*ForeachConfig, error) {
+	var cfg ForeachConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.As == "" {
+		cfg.As = "item"
+	}
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+	if cfg.MaxIterations <= 0 {
+		cfg.MaxIterations = DefaultForeachMaxIterations
+	}
+	if reservedScopeNames[cfg.As] {
+		return nil, fmt.Errorf("loop variable name %q is reserved", cfg.As)
+	}
+	if cfg.MaxIterations > MaxForeachIterations {
+		return nil, fmt.Errorf("max_iterations cannot exceed %d", MaxForeachIterations)
+	}
+	return &cfg, nil
+}
+
+// ItemList returns the rendered items as a list. Items given as a JSON
+// string (e.g. a raw HTTP response body) are decoded.
+func (c *ForeachConfig) ItemList() ([]interface{}, error) {
+	items := c.Items
+	if s, ok := items.(string); ok {
+		items = decodeJSONString(s)
+	}
+	switch v := items.(type) {
+	case []interface{}:
+		return v, nil
+	case nil:
+		return nil, fmt.Errorf("items is required")
+	default:
+		return nil, fmt.Errorf("items must be a list, got %s", describe(v))
+	}
+}
+
+// executeForeachStep runs the loop body once per item with ${item} and
+// ${index} bound, at most max_concurrency iterations at a time. Each
+// iteration runs in its own scope so concurrent iterations don't see each
+// other's step results.
+func (r *runner) executeForeachStep(ctx workflow.Context, step StepDefinition) (*ForeachResult, []StepResult, error) {
+	if len(step.Steps) == 0 {
+		return nil, nil, fmt.Errorf("foreach step requires at least one nested step")
+	}
+
+	cfg, err := ParseForeachConfig(step.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid foreach config: %w", err)
+	}
+	items, err := cfg.ItemList()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid foreach config: %w", err)
+	}
+	if len(items) > cfg.MaxIterations {
+		return nil, nil, fmt.Errorf("foreach over %d items exceeds max_iterations %d", len(items), cfg.MaxIterations)
+	}
+
+	result := &ForeachResult{
+		Total:      len(items),
+		Iterations: make([]ForeachIteration, len(items)),
+	}
+	children := make([]StepResult, len(items))
+	for i, item := range items {
+		result.Iterations[i] = ForeachIteration{Index: i, Item: item, Status: IterationSkipped}
+	}
+
+	loopCtx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	done := workflow.NewBufferedChannel(ctx, len(items))
+	next, running := 0, 0
+	stopped := false
+
+	start := func(i int) {
+		running++
+		iteration := r.fork(map[string]interface{}{
+			cfg.As:  items[i],
+			"index": i,
+		})
+		workflow.Go(loopCtx, func(gctx workflow.Context) {
+			iterStart := workflow.Now(gctx)
+			stepResults, err := iteration.runSteps(gctx, step.Steps)
+
+			children[i] = StepResult{
+				StepID:     fmt.Sprintf("%s[%d]", step.ID, i),
+				StepName:   step.Name,
+				StepType:   string(step.Type),
+				Success:    err == nil,
+				DurationMs: workflow.Now(gctx).Sub(iterStart).Milliseconds(),
+				Children:   stepResults,
+			}
+
+			output := make(map[string]interface{})
+			for _, sr := range stepResults {
+				if sr.Success {
+					output[sr.StepID] = sr.Output
+				}
+			}
+			result.Iterations[i].Output = output
+			if err != nil {
+				children[i].Error = err.Error()
+				result.Iterations[i].Error = err.Error()
+			}
+
+			done.Send(gctx, i)
+		})
+	}
+
+	for next < len(items) && running < cfg.MaxConcurrency {
+		start(next)
+		next++
+	}
+
+	for running > 0 {
+		var i int
+		done.Receive(ctx, &i)
+		running--
+
+		switch {
+		case children[i].Success:
+			result.Iterations[i].Status = IterationSucceeded
+			result.Succeeded++
+		case stopped:
+			result.Iterations[i].Status = IterationCancelled
+			result.Cancelled++
+		default:
+			result.Iterations[i].Status = IterationFailed
+			result.Failed++
+			if cfg.FailFast {
+				stopped = true
+				cancel()
+			}
+		}
+
+		if !stopped && next < len(items) {
+			start(next)
+			next++
+		}
+	}
+	result.Skipped = len(items) - next
+
+	// Drop the never-started iterations from the step results
+	started := children[:0]
+	for _, child := range children {
+		if child.StepID != "" {
+			started = append(started, child)
+		}
+	}
+
+	workflow.GetLogger(ctx).Info("Foreach step completed",
+		"step_id", step.ID,
+		"total", result.Total,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+		"cancelled", result.Cancelled,
+		"skipped", result.Skipped)
+
+	if result.Failed > 0 {
+		return result, started, fmt.Errorf("%d of %d iterations failed", result.Failed, result.Total)
+	}
+	return result, started, nil
+}
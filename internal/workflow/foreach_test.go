@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+func TestParseForeachConfig(t *testing.T) {
+	t.Run("applies defaults", func(t *testing.T) {
+		cfg, err := ParseForeachConfig(map[string]interface{}{"items": []interface{}{"a"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, "item", cfg.As)
+		assert.Equal(t, 1, cfg.MaxConcurrency)
+		assert.Equal(t, DefaultForeachMaxIterations, cfg.MaxIterations)
+	})
+
+	t.Run("rejects reserved variable name", func(t *testing.T) {
+		_, err := ParseForeachConfig(map[string]interface{}{"as": "input"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects iteration cap above limit", func(t *testing.T) {
+		_, err := ParseForeachConfig(map[string]interface{}{"max_iterations": MaxForeachIterations + 1})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestForeachConfig_ItemList(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    interface{}
+		expected []interface{}
+		wantErr  bool
+	}{
+		{"list", []interface{}{"pod-1", "pod-2"}, []interface{}{"pod-1", "pod-2"}, false},
+		{"json string", `[{"host":"a"}]`, []interface{}{map[string]interface{}{"host": "a"}}, false},
+		{"missing", nil, nil, true},
+		{"not a list", "pod-1", nil, true},
+		{"object", map[string]interface{}{"a": 1}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &ForeachConfig{Items: tt.items}
+
+			items, err := cfg.ItemList()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, items)
+		})
+	}
+}
+
+func TestForeachStep(t *testing.T) {
+	t.Run("runs the body per item and keeps each iteration's output", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "hosts", "type": "foreach", "config": {"items": ["web-1", "web-2", "web-3"], "as": "host", "max_concurrency": 2}, "steps": [
+				{"id": "check", "type": "http", "config": {"url": "http://${host}/${index}"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "completed", output.Status)
+		require.Len(t, output.StepResults, 1)
+		var result ForeachResult
+		decodeOutput(t, output.StepResults[0].Output, &result)
+		assert.Equal(t, 3, result.Succeeded)
+		require.Len(t, result.Iterations, 3)
+		for i, host := range []string{"web-1", "web-2", "web-3"} {
+			assert.Equal(t, IterationSucceeded, result.Iterations[i].Status)
+			var check activity.HTTPResult
+			decodeOutput(t, result.Iterations[i].Output["check"], &check)
+			assert.Equal(t, "http://"+host+"/"+strconv.Itoa(i), check.Body)
+		}
+	})
+
+	t.Run("runs at most max_concurrency iterations at a time", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "waits", "type": "foreach", "config": {"items": [1, 2, 3, 4], "max_concurrency": 2}, "steps": [
+				{"id": "wait", "type": "delay", "config": {"duration": "1m"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "completed", output.Status)
+		assert.GreaterOrEqual(t, output.Duration, (2 * time.Minute).Milliseconds())
+		assert.Less(t, output.Duration, (3 * time.Minute).Milliseconds())
+	})
+
+	t.Run("fail_fast cancels running iterations and skips the rest", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "hosts", "type": "foreach", "config": {"items": ["fail", "web-1", "web-2"], "max_concurrency": 2, "fail_fast": true}, "steps": [
+				{"id": "check", "type": "http", "config": {"url": "http://${item}"}},
+				{"id": "wait", "type": "delay", "config": {"duration": "1h"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "failed", output.Status)
+		assert.Contains(t, output.Error, "1 of 3 iterations failed")
+		require.Len(t, output.StepResults, 1)
+		children := output.StepResults[0].Children
+		require.Len(t, children, 2) // the third iteration never started
+		assert.Contains(t, children[0].Error, "connection refused")
+		assert.False(t, children[1].Success)
+		assert.Less(t, output.Duration, time.Hour.Milliseconds())
+	})
+}
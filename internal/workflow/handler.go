@@ -290,6 +290,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 		Definition:  definition,
 		Schedule:    schedule,
 		Status:      status,
+		Version:     existing.Version,
 	})
 	if err != nil {
 		slog.Error("failed to update workflow", "error", err)
@@ -369,6 +370,7 @@ func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
 	// Create execution record
 	temporalWorkflowID := fmt.Sprintf("orchestrix-%s", uuid.New().String())
 	execution, err := h.queries.CreateExecution(ctx, db.CreateExecutionParams{
+		ID:                 uuid.New(),
 		TenantID:           user.TenantID,
 		WorkflowID:         workflowID,
 		TemporalWorkflowID: &temporalWorkflowID,
@@ -405,6 +407,7 @@ func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
 			Name:        wf.Name,
 			Definition:  wf.Definition,
 			Input:       req.Input,
+			TenantID:    user.TenantID.String(),
 		}
 		run, err = temporal.ExecuteWorkflow(ctx, temporalWorkflowID, DynamicWorkflow, workflowInput)
 	} else {
@@ -0,0 +1,266 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// wait_for_metric defaults and limits
+const (
+	DefaultMetricWindow       = "5m"
+	DefaultMetricWaitTimeout  = "10m"
+	DefaultMetricPollInterval = "30s"
+	MinMetricPollInterval     = time.Second
+
+	// maxMetricObservations bounds how many checks are kept in the step output
+	maxMetricObservations = 20
+)
+
+// WaitForMetricConfig for wait_for_metric step type. The condition can be
+// given as a single string, e.g. "cpu_usage{host=web-1} avg over 5m < 70",
+// or with the individual fields.
+type WaitForMetricConfig struct {
+	Condition   string            `json:"condition,omitempty"`
+	Metric      string            `json:"metric,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Aggregation string            `json:"aggregation,omitempty"` // avg (default), sum, min, max, count, p50, p95, p99, last
+	Window      string            `json:"window,omitempty"`      // default "5m"
+	Operator    string            `json:"operator,omitempty"`    // <, <=, >, >=, ==, != (or lt, lte, gt, gte, eq, neq)
+	Threshold   *float64          `json:"threshold,omitempty"`
+	Timeout     string            `json:"timeout,omitempty"`    // default "10m"
+	Interval    string            `json:"interval,omitempty"`   // time between checks, default "30s"
+	OnTimeout   string            `json:"on_timeout,omitempty"` // "fail" (default) or "continue"
+}
+
+// WaitForMetricResult is the output of a wait_for_metric step
+type WaitForMetricResult struct {
+	Satisfied    bool                         `json:"satisfied"`
+	TimedOut     bool                         `json:"timed_out"`
+	Value        *float64                     `json:"value"` // last observed value
+	Metric       string                       `json:"metric"`
+	Labels       map[string]string            `json:"labels,omitempty"`
+	Aggregation  string                       `json:"aggregation"`
+	Operator     string                       `json:"operator"`
+	Threshold    float64                      `json:"threshold"`
+	Checks       int                          `json:"checks"`
+	Observations []activity.CheckMetricResult `json:"observations"` // the most recent checks
+}
+
+var metricConditionPattern = regexp.MustCompile(
+	`^\s*([A-Za-z_][\w:.]*)\s*(?:\{([^}]*)\})?\s*` + // metric{labels}
+		`(?:(avg|sum|min|max|count|p50|p95|p99|last)(?:\s+over\s+(\S+))?)?\s*` + // aggregation over window
+		`(<=|>=|==|!=|<|>)\s*(\S+)\s*$`) // operator threshold
+
+var metricOperators = map[string]string{
+	"<": "<", "<=": "<=", ">": ">", ">=": ">=", "==": "==", "!=": "!=",
+	"lt": "<", "lte": "<=", "gt": ">", "gte": ">=", "eq": "==", "neq": "!=",
+}
+
+var metricAggregations = map[string]bool{
+	"avg": true, "sum": true, "min": true, "max": true, "count": true,
+	"p50": true, "p95": true, "p99": true, "last": true,
+}
+
+// ParseWaitForMetricConfig parses the config map into WaitForMetricConfig
+func ParseWaitForMetricConfig(config map[string]interface{}) (*WaitForMetricConfig, error) {
+	var cfg WaitForMetricConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Condition != "" {
+		if err := cfg.parseCondition(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Metric == "" {
+		return nil, fmt.Errorf("metric is required")
+	}
+	if cfg.Threshold == nil {
+		return nil, fmt.Errorf("threshold is required")
+	}
+	op, ok := metricOperators[cfg.Operator]
+	if !ok {
+		return nil, fmt.Errorf("invalid operator %q", cfg.Operator)
+	}
+	cfg.Operator = op
+
+	if cfg.Aggregation == "" {
+		cfg.Aggregation = "avg"
+	}
+	if !metricAggregations[cfg.Aggregation] {
+		return nil, fmt.Errorf("invalid aggregation %q", cfg.Aggregation)
+	}
+	if cfg.Window == "" {
+		cfg.Window = DefaultMetricWindow
+	}
+	if cfg.Timeout == "" {
+		cfg.Timeout = DefaultMetricWaitTimeout
+	}
+	if cfg.Interval == "" {
+		cfg.Interval = DefaultMetricPollInterval
+	}
+	if cfg.OnTimeout == "" {
+		cfg.OnTimeout = "fail"
+	}
+	if cfg.OnTimeout != "fail" && cfg.OnTimeout != "continue" {
+		return nil, fmt.Errorf(`on_timeout must be "fail" or "continue"`)
+	}
+
+	for _, d := range []struct{ name, value string }{
+		{"window", cfg.Window},
+		{"timeout", cfg.Timeout},
+		{"interval", cfg.Interval},
+	} {
+		if parsed, err := time.ParseDuration(d.value); err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid %s %q", d.name, d.value)
+		}
+	}
+	if interval, _ := time.ParseDuration(cfg.Interval); interval < MinMetricPollInterval {
+		return nil, fmt.Errorf("interval must be at least %s", MinMetricPollInterval)
+	}
+
+	return &cfg, nil
+}
+
+// parseCondition fills the metric, labels, aggregation, window, operator and
+// threshold from the condition string. Explicit fields are overridden.
+func (c *WaitForMetricConfig) parseCondition() error {
+	m := metricConditionPattern.FindStringSubmatch(c.Condition)
+	if m == nil {
+		return fmt.Errorf(`invalid condition %q, expected e.g. "cpu_usage{host=web-1} avg over 5m < 70"`, c.Condition)
+	}
+
+	threshold, err := strconv.ParseFloat(m[6], 64)
+	if err != nil {
+		return fmt.Errorf("invalid condition %q: threshold %q is not a number", c.Condition, m[6])
+	}
+
+	c.Metric = m[1]
+	if m[2] != "" {
+		labels, err := parseMetricLabels(m[2])
+		if err != nil {
+			return fmt.Errorf("invalid condition %q: %w", c.Condition, err)
+		}
+		c.Labels = labels
+	}
+	if m[3] != "" {
+		c.Aggregation = m[3]
+	}
+	if m[4] != "" {
+		c.Window = m[4]
+	}
+	c.Operator = m[5]
+	c.Threshold = &threshold
+	return nil
+}
+
+// parseMetricLabels parses `host=web-1, region="eu-west"`
+func parseMetricLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label %q", pair)
+		}
+		labels[k] = strings.Trim(strings.TrimSpace(v), `"'`)
+	}
+	return labels, nil
+}
+
+// executeWaitForMetricStep checks the metric condition every interval until
+// it holds or the timeout elapses. Waiting between checks uses durable
+// timers, so long verification windows don't hold a worker slot.
+func (r *runner) executeWaitForMetricStep(ctx workflow.Context, step StepDefinition) (*WaitForMetricResult, error) {
+	cfg, err := ParseWaitForMetricConfig(step.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wait_for_metric config: %w", err)
+	}
+	timeout, _ := time.ParseDuration(cfg.Timeout)
+	interval, _ := time.ParseDuration(cfg.Interval)
+
+	result := &WaitForMetricResult{
+		Metric:       cfg.Metric,
+		Labels:       cfg.Labels,
+		Aggregation:  cfg.Aggregation,
+		Operator:     cfg.Operator,
+		Threshold:    *cfg.Threshold,
+		Observations: []activity.CheckMetricResult{},
+	}
+
+	input := activity.CheckMetricInput{
+		TenantID:    r.input.TenantID,
+		Metric:      cfg.Metric,
+		Labels:      cfg.Labels,
+		Aggregation: cfg.Aggregation,
+		Window:      cfg.Window,
+		Operator:    cfg.Operator,
+		Threshold:   *cfg.Threshold,
+	}
+
+	deadline := workflow.Now(ctx).Add(timeout)
+	for {
+		var check activity.CheckMetricResult
+		if err := workflow.ExecuteActivity(ctx, "CheckMetric", input).Get(ctx, &check); err != nil {
+			return nil, fmt.Errorf("failed to check metric: %w", err)
+		}
+
+		result.Checks++
+		result.Value = check.Value
+		result.Observations = append(result.Observations, check)
+		if len(result.Observations) > maxMetricObservations {
+			result.Observations = result.Observations[1:]
+		}
+
+		if check.Satisfied {
+			result.Satisfied = true
+			break
+		}
+		if !workflow.Now(ctx).Add(interval).Before(deadline) {
+			result.TimedOut = true
+			break
+		}
+		if err := workflow.Sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+
+	workflow.GetLogger(ctx).Info("Wait for metric completed",
+		"step_id", step.ID,
+		"metric", cfg.Metric,
+		"satisfied", result.Satisfied,
+		"checks", result.Checks)
+
+	if result.TimedOut && cfg.OnTimeout == "fail" {
+		return result, fmt.Errorf("metric condition %s not met within %s (last value %s)",
+			describeMetricCondition(cfg), cfg.Timeout, formatMetricValue(result.Value))
+	}
+	return result, nil
+}
+
+func describeMetricCondition(cfg *WaitForMetricConfig) string {
+	if cfg.Aggregation == "last" {
+		return fmt.Sprintf("%s last %s %g", cfg.Metric, cfg.Operator, *cfg.Threshold)
+	}
+	return fmt.Sprintf("%s %s over %s %s %g", cfg.Metric, cfg.Aggregation, cfg.Window, cfg.Operator, *cfg.Threshold)
+}
+
+func formatMetricValue(v *float64) string {
+	if v == nil {
+		return "none"
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
@@ -0,0 +1,69 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWaitForMetricConfig(t *testing.T) {
+	t.Run("condition string", func(t *testing.T) {
+		cfg, err := ParseWaitForMetricConfig(map[string]interface{}{
+			"condition": `cpu_usage{host=web-1, region="eu-west"} p95 over 10m <= 70.5`,
+			"timeout":   "30m",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "cpu_usage", cfg.Metric)
+		assert.Equal(t, map[string]string{"host": "web-1", "region": "eu-west"}, cfg.Labels)
+		assert.Equal(t, "p95", cfg.Aggregation)
+		assert.Equal(t, "10m", cfg.Window)
+		assert.Equal(t, "<=", cfg.Operator)
+		assert.Equal(t, 70.5, *cfg.Threshold)
+		assert.Equal(t, "30m", cfg.Timeout)
+		assert.Equal(t, DefaultMetricPollInterval, cfg.Interval)
+		assert.Equal(t, "fail", cfg.OnTimeout)
+	})
+
+	t.Run("condition string with defaults", func(t *testing.T) {
+		cfg, err := ParseWaitForMetricConfig(map[string]interface{}{"condition": "error_rate > 0"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "error_rate", cfg.Metric)
+		assert.Equal(t, "avg", cfg.Aggregation)
+		assert.Equal(t, DefaultMetricWindow, cfg.Window)
+		assert.Equal(t, ">", cfg.Operator)
+	})
+
+	t.Run("structured fields with named operator", func(t *testing.T) {
+		cfg, err := ParseWaitForMetricConfig(map[string]interface{}{
+			"metric":      "queue_depth",
+			"aggregation": "last",
+			"operator":    "lt",
+			"threshold":   float64(100),
+			"on_timeout":  "continue",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<", cfg.Operator)
+		assert.Equal(t, "continue", cfg.OnTimeout)
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		for _, config := range []map[string]interface{}{
+			{"condition": "cpu_usage avg over 5m"},
+			{"condition": "cpu_usage < high"},
+			{"metric": "cpu_usage", "operator": "<"},
+			{"metric": "cpu_usage", "operator": "~", "threshold": float64(1)},
+			{"metric": "cpu_usage", "operator": "<", "threshold": float64(1), "aggregation": "median"},
+			{"metric": "cpu_usage", "operator": "<", "threshold": float64(1), "interval": "10ms"},
+			{"metric": "cpu_usage", "operator": "<", "threshold": float64(1), "timeout": "soon"},
+			{"metric": "cpu_usage", "operator": "<", "threshold": float64(1), "on_timeout": "retry"},
+		} {
+			_, err := ParseWaitForMetricConfig(config)
+
+			assert.Error(t, err, config)
+		}
+	})
+}
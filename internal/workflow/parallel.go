@@ -0,0 +1,142 @@
+package workflow
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// JoinPolicy defines when a parallel step is considered successful
+type JoinPolicy string
+
+const (
+	JoinAll  JoinPolicy = "all"    // every branch must succeed
+	JoinAny  JoinPolicy = "any"    // at least one branch must succeed
+	JoinNOfM JoinPolicy = "n_of_m" // at least min_success branches must succeed
+)
+
+// ParallelConfig for parallel step type
+type ParallelConfig struct {
+	Join       JoinPolicy `json:"join"`                  // default "all"
+	MinSuccess int        `json:"min_success,omitempty"` // required for n_of_m
+}
+
+// ParallelResult is the output of a parallel step
+type ParallelResult struct {
+	Join      JoinPolicy             `json:"join"`
+	Required  int                    `json:"required"`
+	Total     int                    `json:"total"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Cancelled int                    `json:"cancelled"`
+	Outputs   map[string]interface{} `json:"outputs"` // branch step ID -> output
+}
+
+// ParseParallelConfig parses the config map into ParallelConfig
+func ParseParallelConfig(config map[string]interface{}) (*ParallelConfig, error) {
+	var cfg ParallelConfig
+	if err := decodeConfig(config, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Join == "" {
+		cfg.Join = JoinAll
+	}
+	return &cfg, nil
+}
+
+// Required returns how many of total branches must succeed for the join
+// policy to be satisfied
+func (c *ParallelConfig) Required(total int) (int, error) {
+	switch c.Join {
+	case JoinAll:
+		return total, nil
+	case JoinAny:
+		return 1, nil
+	case JoinNOfM:
+		if c.MinSuccess < 1 || c.MinSuccess > total {
+			return 0, fmt.Errorf("min_success must be between 1 and %d, got %d", total, c.MinSuccess)
+		}
+		return c.MinSuccess, nil
+	default:
+		return 0, fmt.Errorf("unknown join policy: %s", c.Join)
+	}
+}
+
+// executeParallelStep runs every branch concurrently and joins them according
+// to the configured policy. As soon as the outcome is decided the remaining
+// branches are cancelled.
+func (r *runner) executeParallelStep(ctx workflow.Context, step StepDefinition) (*ParallelResult, []StepResult, error) {
+	if len(step.Parallel) == 0 {
+		return nil, nil, fmt.Errorf("parallel step requires at least one branch")
+	}
+
+	cfg, err := ParseParallelConfig(step.Config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid parallel config: %w", err)
+	}
+	total := len(step.Parallel)
+	required, err := cfg.Required(total)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid parallel config: %w", err)
+	}
+
+	branchCtx, cancel := workflow.WithCancel(ctx)
+	defer cancel()
+
+	// Branches run on forked runners so they don't see each other's results
+	children := make([]StepResult, total)
+	branches := make([]*runner, total)
+	done := workflow.NewBufferedChannel(ctx, total)
+	for i, branch := range step.Parallel {
+		branches[i] = r.fork(nil)
+		workflow.Go(branchCtx, func(gctx workflow.Context) {
+			children[i] = branches[i].runStep(gctx, branch)
+			done.Send(gctx, i)
+		})
+	}
+
+	result := &ParallelResult{
+		Join:     cfg.Join,
+		Required: required,
+		Total:    total,
+		Outputs:  make(map[string]interface{}),
+	}
+
+	decided := false
+	for range step.Parallel {
+		var i int
+		done.Receive(ctx, &i)
+
+		switch {
+		case children[i].Success:
+			result.Succeeded++
+			result.Outputs[children[i].StepID] = children[i].Output
+		case decided:
+			result.Cancelled++
+		default:
+			result.Failed++
+		}
+
+		if !decided && (result.Succeeded >= required || result.Failed > total-required) {
+			decided = true
+			cancel()
+		}
+	}
+
+	for _, branch := range branches {
+		r.merge(branch)
+	}
+
+	workflow.GetLogger(ctx).Info("Parallel step joined",
+		"step_id", step.ID,
+		"join", cfg.Join,
+		"succeeded", result.Succeeded,
+		"failed", result.Failed,
+		"cancelled", result.Cancelled)
+
+	if result.Succeeded < required {
+		return result, children, fmt.Errorf("join %s not satisfied: %d of %d branches succeeded, %d required",
+			cfg.Join, result.Succeeded, total, required)
+	}
+	return result, children, nil
+}
@@ -0,0 +1,112 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+func TestParallelConfig_Required(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		total    int
+		expected int
+		wantErr  bool
+	}{
+		{"defaults to all", nil, 3, 3, false},
+		{"any", map[string]interface{}{"join": "any"}, 3, 1, false},
+		{"n of m", map[string]interface{}{"join": "n_of_m", "min_success": 2}, 3, 2, false},
+		{"n of m without min_success", map[string]interface{}{"join": "n_of_m"}, 3, 0, true},
+		{"n of m above total", map[string]interface{}{"join": "n_of_m", "min_success": 4}, 3, 0, true},
+		{"unknown policy", map[string]interface{}{"join": "most"}, 3, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseParallelConfig(tt.config)
+			require.NoError(t, err)
+
+			required, err := cfg.Required(tt.total)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, required)
+		})
+	}
+}
+
+func TestParallelStep(t *testing.T) {
+	t.Run("joins all branches and keeps their outputs", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "fetch", "type": "parallel", "parallel": [
+				{"id": "a", "type": "http", "config": {"url": "http://a"}},
+				{"id": "b", "type": "http", "config": {"url": "http://b"}}
+			]},
+			{"id": "report", "type": "log", "config": {"message": "${steps.a.output.body} ${steps.b.output.body}"}}
+		]}`)
+
+		assert.Equal(t, "completed", output.Status)
+		require.Len(t, output.StepResults, 2)
+		var result ParallelResult
+		decodeOutput(t, output.StepResults[0].Output, &result)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 0, result.Failed)
+		assert.Equal(t, 0, result.Cancelled)
+		var a, b activity.HTTPResult
+		decodeOutput(t, result.Outputs["a"], &a)
+		decodeOutput(t, result.Outputs["b"], &b)
+		assert.Equal(t, "http://a", a.Body)
+		assert.Equal(t, "http://b", b.Body)
+
+		// Later steps see the branch steps
+		assert.True(t, output.StepResults[1].Success)
+		assert.Contains(t, output.Output, "a")
+		assert.Contains(t, output.Output, "b")
+	})
+
+	t.Run("cancels the other branches once the join fails", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "fetch", "type": "parallel", "parallel": [
+				{"id": "a", "type": "http", "config": {"url": "http://fail"}},
+				{"id": "b", "type": "delay", "config": {"duration": "1h"}}
+			]}
+		]}`)
+
+		assert.Equal(t, "failed", output.Status)
+		assert.Contains(t, output.Error, "join all not satisfied: 0 of 2 branches succeeded")
+		require.Len(t, output.StepResults, 1)
+		children := output.StepResults[0].Children
+		require.Len(t, children, 2)
+		assert.Contains(t, children[0].Error, "connection refused")
+		assert.False(t, children[1].Success)
+		assert.Less(t, output.Duration, time.Hour.Milliseconds())
+	})
+
+	t.Run("branches don't see each other's steps", func(t *testing.T) {
+		output := runDefinition(t, newTestEnv(), `{"steps": [
+			{"id": "fetch", "type": "parallel", "parallel": [
+				{"id": "a", "type": "http", "config": {"url": "http://a"}},
+				{"id": "b", "type": "condition", "condition": "true", "on_true": [
+					{"id": "wait", "type": "delay", "config": {"duration": "1s"}},
+					{"id": "peek", "type": "http", "config": {"url": "http://b/${steps.a.success}"}, "continue_on_error": true}
+				]}
+			]}
+		]}`)
+
+		assert.Equal(t, "completed", output.Status)
+		children := output.StepResults[0].Children
+		require.Len(t, children, 2)
+		require.Len(t, children[1].Children, 2)
+		peek := children[1].Children[1]
+		assert.False(t, peek.Success)
+		assert.Contains(t, peek.Error, "unresolved reference ${steps.a.success}")
+	})
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+// Pause and resume signal and progress query names
+const (
+	PauseSignalName  = "pause"
+	ResumeSignalName = "resume"
+	ProgressQuery    = "progress"
+)
+
+// PauseSignal carries a request to pause or resume a run
+type PauseSignal struct {
+	UserID string    `json:"user_id,omitempty"`
+	User   string    `json:"user,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Progress is a snapshot of a running DynamicWorkflow, as returned by the
+// progress query
+type Progress struct {
+	Paused         bool                   `json:"paused"`
+	PausedAt       *time.Time             `json:"paused_at,omitempty"`
+	PausedBy       string                 `json:"paused_by,omitempty"`
+	CurrentStep    *StepState             `json:"current_step,omitempty"`
+	CompletedSteps []StepState            `json:"completed_steps"`
+	TotalSteps     int                    `json:"total_steps"`
+	Outputs        map[string]interface{} `json:"outputs"`
+}
+
+// pause tracks whether a run was asked to pause. A paused run finishes the
+// step it is running and waits before starting the next top-level step.
+type pause struct {
+	paused   bool
+	pausedAt *time.Time
+	pausedBy string
+}
+
+// register starts receiving pause and resume signals
+func (p *pause) register(ctx workflow.Context) {
+	pauses := workflow.GetSignalChannel(ctx, PauseSignalName)
+	resumes := workflow.GetSignalChannel(ctx, ResumeSignalName)
+	workflow.Go(ctx, func(gctx workflow.Context) {
+		selector := workflow.NewSelector(gctx)
+		selector.AddReceive(pauses, func(c workflow.ReceiveChannel, more bool) {
+			var signal PauseSignal
+			c.Receive(gctx, &signal)
+			p.pause(signal)
+		})
+		selector.AddReceive(resumes, func(c workflow.ReceiveChannel, more bool) {
+			var signal PauseSignal
+			c.Receive(gctx, &signal)
+			p.resume()
+		})
+		for {
+			selector.Select(gctx)
+		}
+	})
+}
+
+// pause marks the run as paused. Pausing a paused run keeps who paused it
+// first.
+func (p *pause) pause(signal PauseSignal) {
+	if p.paused {
+		return
+	}
+	at := signal.At
+	p.paused = true
+	p.pausedAt = &at
+	p.pausedBy = signal.User
+}
+
+// resume lets the run start its next step
+func (p *pause) resume() {
+	p.paused = false
+	p.pausedAt = nil
+	p.pausedBy = ""
+}
+
+// wait blocks while the run is paused. It returns early with an error when
+// ctx is cancelled, so a paused run can still be cancelled or time out.
+func (p *pause) wait(ctx workflow.Context) error {
+	if !p.paused {
+		return nil
+	}
+	workflow.GetLogger(ctx).Info("Run paused", "paused_by", p.pausedBy)
+	if err := workflow.Await(ctx, func() bool { return !p.paused }); err != nil {
+		return err
+	}
+	workflow.GetLogger(ctx).Info("Run resumed")
+	return nil
+}
+
+// registerProgress exposes the progress query of a run of total top-level
+// steps
+func registerProgress(ctx workflow.Context, p *pause, t *timeline, r *runner, total int) error {
+	return workflow.SetQueryHandler(ctx, ProgressQuery, func() (*Progress, error) {
+		return newProgress(p, t.steps, r.stepOutputs, total), nil
+	})
+}
+
+// newProgress splits the timeline into the running step and the finished
+// ones. Outputs are keyed as in the output of the finished run.
+func newProgress(p *pause, steps []StepState, outputs map[string]interface{}, total int) *Progress {
+	progress := &Progress{
+		Paused:         p.paused,
+		PausedAt:       p.pausedAt,
+		PausedBy:       p.pausedBy,
+		CompletedSteps: make([]StepState, 0, len(steps)),
+		TotalSteps:     total,
+		Outputs:        outputs,
+	}
+	for i := range steps {
+		if steps[i].Status == string(domain.ExecutionStepStatusRunning) {
+			current := steps[i]
+			progress.CurrentStep = &current
+			continue
+		}
+		progress.CompletedSteps = append(progress.CompletedSteps, steps[i])
+	}
+	return progress
+}
@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPause_PauseAndResume(t *testing.T) {
+	p := &pause{}
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	p.pause(PauseSignal{User: "alice@example.com", At: first})
+	p.pause(PauseSignal{User: "bob@example.com", At: first.Add(time.Minute)})
+
+	assert.True(t, p.paused)
+	assert.Equal(t, "alice@example.com", p.pausedBy)
+	require.NotNil(t, p.pausedAt)
+	assert.Equal(t, first, *p.pausedAt)
+
+	p.resume()
+
+	assert.False(t, p.paused)
+	assert.Nil(t, p.pausedAt)
+	assert.Equal(t, "", p.pausedBy)
+}
+
+func TestNewProgress(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []StepState{
+		{Position: 0, StepID: "drain", Status: "completed", Output: map[string]interface{}{"drained": true}, CompletedAt: &now},
+		{Position: 1, StepID: "restart", Status: "running"},
+	}
+	outputs := map[string]interface{}{"drain": map[string]interface{}{"drained": true}}
+	p := &pause{}
+	p.pause(PauseSignal{User: "alice@example.com", At: now})
+
+	progress := newProgress(p, steps, outputs, 3)
+
+	assert.True(t, progress.Paused)
+	assert.Equal(t, "alice@example.com", progress.PausedBy)
+	require.NotNil(t, progress.CurrentStep)
+	assert.Equal(t, "restart", progress.CurrentStep.StepID)
+	require.Len(t, progress.CompletedSteps, 1)
+	assert.Equal(t, "drain", progress.CompletedSteps[0].StepID)
+	assert.Equal(t, 3, progress.TotalSteps)
+	assert.Equal(t, outputs, progress.Outputs)
+}
@@ -0,0 +1,49 @@
+package workflow
+
+import "github.com/orchestrix/orchestrix-api/internal/core/domain"
+
+// ResumeInput is set when a failed execution is retried from its failed
+// step. The steps before StartAt don't run again; Steps holds the results
+// they recorded in the execution being retried.
+type ResumeInput struct {
+	StartAt int         `json:"start_at"`
+	Steps   []StepState `json:"steps,omitempty"`
+}
+
+// resumedStep returns the recorded state of the step at position, if any
+func (in *ResumeInput) resumedStep(position int) (StepState, bool) {
+	for _, state := range in.Steps {
+		if state.Position == position {
+			return state, true
+		}
+	}
+	return StepState{}, false
+}
+
+// resumedResult turns a recorded step back into the result it had, so later
+// steps see the same state they would have in the original run. A skipped
+// step looks like a failed step that continued on error.
+func resumedResult(step StepDefinition, state StepState) StepResult {
+	result := StepResult{
+		StepID:    step.ID,
+		StepName:  step.Name,
+		StepType:  string(step.Type),
+		Success:   state.Status == string(domain.ExecutionStepStatusCompleted),
+		Attempts:  state.Attempts,
+		StartedAt: state.StartedAt,
+		Error:     state.Error,
+	}
+	if result.Success {
+		result.Output = state.Output
+	}
+	if state.CompletedAt != nil {
+		result.DurationMs = state.CompletedAt.Sub(state.StartedAt).Milliseconds()
+	}
+	return result
+}
+
+// restore adds a step reused from the execution being retried to the
+// timeline. It was recorded when the retry was created.
+func (t *timeline) restore(state StepState) {
+	t.steps = append(t.steps, state)
+}
@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResumedResult(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	completed := started.Add(1500 * time.Millisecond)
+	step := StepDefinition{ID: "scale_up", Name: "Scale up", Type: StepTypeHTTP}
+
+	t.Run("completed steps keep their output", func(t *testing.T) {
+		result := resumedResult(step, StepState{
+			Position:    1,
+			StepID:      "scale_up",
+			Status:      "completed",
+			Attempts:    2,
+			Output:      map[string]interface{}{"status_code": float64(200)},
+			StartedAt:   started,
+			CompletedAt: &completed,
+		})
+
+		assert.True(t, result.Success)
+		assert.Equal(t, map[string]interface{}{"status_code": float64(200)}, result.Output)
+		assert.Equal(t, int32(2), result.Attempts)
+		assert.Equal(t, int64(1500), result.DurationMs)
+		assert.Equal(t, "Scale up", result.StepName)
+	})
+
+	t.Run("skipped steps look like failed steps", func(t *testing.T) {
+		result := resumedResult(step, StepState{
+			Position:  1,
+			StepID:    "scale_up",
+			Status:    "skipped",
+			Error:     "503 Service Unavailable",
+			StartedAt: started,
+		})
+
+		assert.False(t, result.Success)
+		assert.Nil(t, result.Output)
+		assert.Equal(t, "503 Service Unavailable", result.Error)
+	})
+}
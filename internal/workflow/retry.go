@@ -0,0 +1,101 @@
+package workflow
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// withRetries runs an activity-backed step and retries failed attempts in the
+// workflow rather than in Temporal, so the number of attempts is known. The
+// retry policy of the activity options in ctx is followed with Temporal's
+// semantics and defaults. The returned attempts is 0 when the run retries in
+// Temporal and the count is unknown.
+func (r *runner) withRetries(ctx workflow.Context, run func(ctx workflow.Context) (interface{}, error)) (interface{}, int32, error) {
+	if !r.countAttempts {
+		result, err := run(ctx)
+		return result, 0, err
+	}
+
+	ao := workflow.GetActivityOptions(ctx)
+	policy := ao.RetryPolicy
+	if policy == nil {
+		policy = &temporal.RetryPolicy{}
+	}
+	ao.RetryPolicy = &temporal.RetryPolicy{MaximumAttempts: 1}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	for attempt := int32(1); ; attempt++ {
+		result, err := run(ctx)
+		if err == nil || ctx.Err() != nil || !shouldRetry(policy, attempt, err) {
+			return result, attempt, err
+		}
+
+		interval := retryInterval(policy, attempt)
+		workflow.GetLogger(ctx).Warn("step attempt failed, retrying",
+			"attempt", attempt,
+			"retry_in", interval,
+			"error", err)
+		if sleepErr := workflow.Sleep(ctx, interval); sleepErr != nil {
+			return result, attempt, err
+		}
+	}
+}
+
+// shouldRetry reports whether a failed attempt is retried under policy. Like
+// Temporal, only activity failures are retried and never non-retryable
+// application errors; errors raised by the workflow itself, such as an
+// invalid step config, fail the step straight away.
+func shouldRetry(policy *temporal.RetryPolicy, attempt int32, err error) bool {
+	if policy.MaximumAttempts > 0 && attempt >= policy.MaximumAttempts {
+		return false
+	}
+
+	var activityErr *temporal.ActivityError
+	if !errors.As(err, &activityErr) {
+		return false
+	}
+	var canceledErr *temporal.CanceledError
+	if errors.As(err, &canceledErr) {
+		return false
+	}
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		if appErr.NonRetryable() {
+			return false
+		}
+		for _, errType := range policy.NonRetryableErrorTypes {
+			if appErr.Type() == errType {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// retryInterval returns the backoff before the attempt after attempt, using
+// Temporal's defaults for unset fields: a 1s initial interval, a coefficient
+// of 2 and a maximum of 100 times the initial interval
+func retryInterval(policy *temporal.RetryPolicy, attempt int32) time.Duration {
+	initial := policy.InitialInterval
+	if initial <= 0 {
+		initial = time.Second
+	}
+	coefficient := policy.BackoffCoefficient
+	if coefficient < 1 {
+		coefficient = 2.0
+	}
+	maximum := policy.MaximumInterval
+	if maximum <= 0 {
+		maximum = 100 * initial
+	}
+
+	interval := float64(initial) * math.Pow(coefficient, float64(attempt-1))
+	if interval > float64(maximum) {
+		return maximum
+	}
+	return time.Duration(interval)
+}
@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestRetryInterval(t *testing.T) {
+	t.Run("uses Temporal defaults", func(t *testing.T) {
+		policy := &temporal.RetryPolicy{}
+
+		assert.Equal(t, time.Second, retryInterval(policy, 1))
+		assert.Equal(t, 2*time.Second, retryInterval(policy, 2))
+		assert.Equal(t, 100*time.Second, retryInterval(policy, 20))
+	})
+
+	t.Run("backs off up to the maximum interval", func(t *testing.T) {
+		policy := &temporal.RetryPolicy{
+			InitialInterval:    500 * time.Millisecond,
+			BackoffCoefficient: 3,
+			MaximumInterval:    10 * time.Second,
+		}
+
+		assert.Equal(t, 500*time.Millisecond, retryInterval(policy, 1))
+		assert.Equal(t, 1500*time.Millisecond, retryInterval(policy, 2))
+		assert.Equal(t, 4500*time.Millisecond, retryInterval(policy, 3))
+		assert.Equal(t, 10*time.Second, retryInterval(policy, 4))
+	})
+}
+
+func TestShouldRetry(t *testing.T) {
+	t.Run("does not retry workflow errors", func(t *testing.T) {
+		err := errors.New("invalid HTTP config: url is required")
+
+		assert.False(t, shouldRetry(&temporal.RetryPolicy{}, 1, err))
+	})
+}
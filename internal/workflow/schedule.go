@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// ScheduledWorkflowInput is the input a workflow's Temporal schedule starts
+// ScheduledWorkflow with
+type ScheduledWorkflowInput struct {
+	WorkflowID string `json:"workflow_id"`
+	TenantID   string `json:"tenant_id"`
+	ScheduleID string `json:"schedule_id"`
+}
+
+// ScheduledWorkflow is started by a workflow's Temporal schedule on every
+// tick. It records an execution for the run and runs the latest version of
+// the workflow as a DynamicWorkflow child. The schedule's overlap policy
+// applies to this workflow, so it covers the whole run.
+func ScheduledWorkflow(ctx workflow.Context, input ScheduledWorkflowInput) (*DynamicWorkflowOutput, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("ScheduledWorkflow started",
+		"workflow_id", input.WorkflowID,
+		"schedule_id", input.ScheduleID)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    3,
+		},
+	})
+
+	var run activity.StartScheduledExecutionResult
+	err := workflow.ExecuteActivity(ctx, "StartScheduledExecution", activity.StartScheduledExecutionInput{
+		TenantID:   input.TenantID,
+		WorkflowID: input.WorkflowID,
+		ScheduleID: input.ScheduleID,
+	}).Get(ctx, &run)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start scheduled run: %w", err)
+	}
+
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID: run.TemporalWorkflowID,
+	})
+
+	var output DynamicWorkflowOutput
+	childErr := workflow.ExecuteChildWorkflow(childCtx, DynamicWorkflow, DynamicWorkflowInput{
+		ExecutionID: run.ExecutionID,
+		WorkflowID:  input.WorkflowID,
+		Name:        run.Name,
+		Definition:  run.Definition,
+		Input:       run.Input,
+		TenantID:    input.TenantID,
+	}).Get(childCtx, &output)
+
+	// The run records its own outcome. Record a failure on its behalf when it
+	// was stopped before it could, e.g. terminated by the overlap policy.
+	if childErr != nil && !temporal.IsCanceledError(childErr) {
+		completeCtx, cancel := workflow.NewDisconnectedContext(ctx)
+		defer cancel()
+		err := workflow.ExecuteActivity(completeCtx, "CompleteExecution", activity.CompleteExecutionInput{
+			TenantID:    input.TenantID,
+			ExecutionID: run.ExecutionID,
+			Status:      "failed",
+			Error:       childErr.Error(),
+		}).Get(completeCtx, nil)
+		if err != nil {
+			logger.Warn("failed to record scheduled execution outcome",
+				"execution_id", run.ExecutionID,
+				"error", err)
+		}
+	}
+
+	logger.Info("ScheduledWorkflow completed",
+		"execution_id", run.ExecutionID,
+		"status", output.Status)
+
+	if childErr != nil {
+		return nil, childErr
+	}
+	return &output, nil
+}
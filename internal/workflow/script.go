@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+// executeScriptStep runs the step code in the sandboxed Script activity with
+// the workflow input and prior step state. The script return value becomes
+// the step output.
+func (r *runner) executeScriptStep(ctx workflow.Context, step StepDefinition) (interface{}, error) {
+	cfg, err := ParseScriptConfig(step.Config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script config: %w", err)
+	}
+	if cfg.Code == "" {
+		return nil, fmt.Errorf("invalid script config: code is required")
+	}
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script config: invalid timeout %q: %w", cfg.Timeout, err)
+	}
+
+	scope := r.scope()
+	scriptInput, _ := scope["input"].(map[string]interface{})
+
+	input := activity.ScriptInput{
+		Language:    cfg.Language,
+		Code:        cfg.Code,
+		Input:       scriptInput,
+		Steps:       r.steps,
+		Now:         workflow.Now(ctx),
+		Seed:        scriptSeed(r.input.ExecutionID, step.ID),
+		TimeoutMs:   timeout.Milliseconds(),
+		MaxMemoryMB: cfg.MaxMemoryMB,
+	}
+
+	var result activity.ScriptResult
+	err = workflow.ExecuteActivity(ctx, "Script", input).Get(ctx, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Output, nil
+}
+
+// scriptSeed derives a stable random seed so retries and replays of the same
+// step see the same Math.random() sequence
+func scriptSeed(executionID, stepID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(executionID))
+	h.Write([]byte{0})
+	h.Write([]byte(stepID))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,287 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Templates reference the expression scope with ${path}, e.g.
+// "https://${input.host}/health" or "${steps.fetch.output.body.id}".
+// A string made of a single reference keeps the referenced value's type,
+// unless it sets a string field of the step config; otherwise values are
+// formatted into the string (objects and arrays as JSON). "$${" produces a
+// literal "${".
+//
+// Secret references, ${secrets.NAME}, are left for the worker to resolve
+// when the activity runs, so secret values never enter workflow history.
+// They render as themselves, escaped ones included, and secret references
+// inside substituted values are escaped, so input can't smuggle one in.
+
+// RenderTemplate renders a single template string against scope
+func RenderTemplate(s string, scope map[string]interface{}) (interface{}, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	if path, ok := singleReference(s); ok {
+		if isSecretReference(path) {
+			return s, nil
+		}
+		value, found := lookupPath(scope, splitPath(path))
+		if !found {
+			return nil, fmt.Errorf("unresolved reference ${%s}", path)
+		}
+		return escapeSecretReferences(value), nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			if isSecretReference(strings.TrimSpace(s[i+3:])) {
+				sb.WriteString("$${")
+			} else {
+				sb.WriteString("${")
+			}
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated reference at offset %d", i)
+		}
+		path := strings.TrimSpace(s[i+2 : i+end])
+		if path == "" {
+			return nil, fmt.Errorf("empty reference at offset %d", i)
+		}
+		if isSecretReference(path) {
+			sb.WriteString(s[i : i+end+1])
+			i += end + 1
+			continue
+		}
+		value, found := lookupPath(scope, splitPath(path))
+		if !found {
+			return nil, fmt.Errorf("unresolved reference ${%s}", path)
+		}
+		sb.WriteString(escapeSecretReferences(toString(value)).(string))
+		i += end + 1
+	}
+	return sb.String(), nil
+}
+
+// RenderConfig renders every string in a step config, recursing into nested
+// maps and lists. Errors name the config key holding the bad reference.
+func RenderConfig(config map[string]interface{}, scope map[string]interface{}) (map[string]interface{}, error) {
+	rendered, err := renderValue(config, scope, "config")
+	if err != nil {
+		return nil, err
+	}
+	out, _ := rendered.(map[string]interface{})
+	return out, nil
+}
+
+func renderValue(v interface{}, scope map[string]interface{}, path string) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		out, err := RenderTemplate(val, scope)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return out, nil
+	case map[string]interface{}:
+		if val == nil {
+			return val, nil
+		}
+		out := make(map[string]interface{}, len(val))
+		for _, k := range sortedKeys(val) {
+			rendered, err := renderValue(val[k], scope, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			rendered, err := renderValue(item, scope, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// decodeConfig decodes a rendered step config into out, a pointer to a
+// config struct. A single reference keeps the referenced value's type, so
+// values rendered into string fields, e.g. a header set to
+// ${input.replicas}, are decoded as their string form.
+func decodeConfig(config map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(stringifyValues(config, reflect.TypeOf(out)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// stringifyValues formats the values of v that decode into a string of
+// type t the way templates interpolate them: numbers and booleans as text,
+// objects and arrays as JSON. Values decoding into other types, such as an
+// HTTP body, keep their type.
+func stringifyValues(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.String {
+		if v == nil {
+			return v
+		}
+		return toString(v)
+	}
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if t.Kind() != reflect.Map && t.Kind() != reflect.Struct {
+			return v
+		}
+		fields := jsonFields(t)
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			switch {
+			case t.Kind() == reflect.Map:
+				out[k] = stringifyValues(item, t.Elem())
+			case fields[k] != nil:
+				out[k] = stringifyValues(item, fields[k])
+			default:
+				out[k] = item
+			}
+		}
+		return out
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = stringifyValues(item, t.Elem())
+		}
+		return out
+	}
+	return v
+}
+
+// jsonFields returns the types of the fields of struct type t by JSON name
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// TemplateReferences returns the paths referenced by ${...} in s
+func TemplateReferences(s string) []string {
+	var refs []string
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			i++
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			break
+		}
+		if path := strings.TrimSpace(s[i+2 : i+end]); path != "" {
+			refs = append(refs, path)
+		}
+		i += end + 1
+	}
+	return refs
+}
+
+// secretReferencePrefix matches the start of a ${secrets.NAME} reference
+var secretReferencePrefix = regexp.MustCompile(`\$\{\s*secrets\.`)
+
+// isSecretReference reports whether a referenced path is in the secrets
+// scope, which only the worker resolves
+func isSecretReference(path string) bool {
+	return path == "secrets" || strings.HasPrefix(path, "secrets.")
+}
+
+// escapeSecretReferences turns secret references in the strings of v into
+// escaped ones, which the worker sends literally
+func escapeSecretReferences(v interface{}) interface{} {
+	switch val := v.(type) {
+	case string:
+		return secretReferencePrefix.ReplaceAllStringFunc(val, func(m string) string { return "$" + m })
+	case map[string]interface{}:
+		if val == nil {
+			return val
+		}
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = escapeSecretReferences(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = escapeSecretReferences(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// singleReference reports whether s consists of exactly one ${path}
+func singleReference(s string) (string, bool) {
+	if !strings.HasPrefix(s, "${") || !strings.HasSuffix(s, "}") {
+		return "", false
+	}
+	inner := s[2 : len(s)-1]
+	if strings.ContainsAny(inner, "{}") {
+		return "", false
+	}
+	path := strings.TrimSpace(inner)
+	return path, path != ""
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
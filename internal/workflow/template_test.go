@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	scope := testScope()
+	scope["execution"] = map[string]interface{}{"id": "exec-1"}
+	scope["env"] = map[string]interface{}{"tenant_id": "tenant-1"}
+
+	tests := []struct {
+		name     string
+		template string
+		expected interface{}
+	}{
+		{"plain string", "no references", "no references"},
+		{"interpolated string", "https://${input.host}/health", "https://db-1/health"},
+		{"single reference keeps type", "${input.replicas}", float64(3)},
+		{"single reference to array", "${input.tags}", []interface{}{"prod", "eu"}},
+		{"array formatted as json", "tags=${input.tags}", `tags=["prod","eu"]`},
+		{"json body field", "status: ${steps.check_health.output.body.status}", "status: degraded"},
+		{"execution and env", "${env.tenant_id}/${execution.id}", "tenant-1/exec-1"},
+		{"escaped reference", "$${input.host} is ${input.host}", "${input.host} is db-1"},
+		{"secret reference left for the worker", "Bearer ${secrets.API_TOKEN} for ${input.host}", "Bearer ${secrets.API_TOKEN} for db-1"},
+		{"single secret reference", "${secrets.API_TOKEN}", "${secrets.API_TOKEN}"},
+		{"escaped secret reference stays escaped", "$${secrets.API_TOKEN}", "$${secrets.API_TOKEN}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplate(tt.template, scope)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRenderTemplate_EscapesSecretReferencesInValues(t *testing.T) {
+	scope := map[string]interface{}{"input": map[string]interface{}{
+		"path": "x?token=${secrets.API_TOKEN}",
+		"body": map[string]interface{}{"token": "${ secrets.API_TOKEN }"},
+	}}
+
+	path, err := RenderTemplate("https://api/${input.path}", scope)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api/x?token=$${secrets.API_TOKEN}", path)
+
+	body, err := RenderTemplate("${input.body}", scope)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"token": "$${ secrets.API_TOKEN }"}, body)
+}
+
+func TestRenderConfig(t *testing.T) {
+	t.Run("renders nested values", func(t *testing.T) {
+		config := map[string]interface{}{
+			"url":           "https://${input.host}/restart",
+			"headers":       map[string]interface{}{"X-Replicas": "${input.replicas}"},
+			"body":          map[string]interface{}{"replicas": "${input.replicas}", "tags": []interface{}{"${input.tags[0]}"}},
+			"success_codes": []interface{}{float64(200)},
+		}
+
+		rendered, err := RenderConfig(config, testScope())
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://${input.host}/restart", config["url"])
+		cfg, err := ParseHTTPConfig(rendered)
+		require.NoError(t, err)
+		assert.Equal(t, "https://db-1/restart", cfg.URL)
+		assert.Equal(t, map[string]string{"X-Replicas": "3"}, cfg.Headers)
+		assert.Equal(t, map[string]interface{}{"replicas": float64(3), "tags": []interface{}{"prod"}}, cfg.Body)
+		assert.Equal(t, []int{200}, cfg.SuccessCodes)
+	})
+
+	t.Run("formats values rendered into string fields", func(t *testing.T) {
+		config := map[string]interface{}{
+			"target":  "${input.replicas}",
+			"message": "${input.tags}",
+			"data":    map[string]interface{}{"ready": "${steps.check_health.success}"},
+		}
+
+		rendered, err := RenderConfig(config, testScope())
+		require.NoError(t, err)
+		cfg, err := ParseNotifyConfig(rendered)
+
+		require.NoError(t, err)
+		assert.Equal(t, "3", cfg.Target)
+		assert.Equal(t, `["prod","eu"]`, cfg.Message)
+		assert.Equal(t, map[string]string{"ready": "true"}, cfg.Data)
+	})
+
+	t.Run("missing reference names the config key", func(t *testing.T) {
+		config := map[string]interface{}{
+			"body": map[string]interface{}{"id": "${steps.fetch.output.body.id}"},
+		}
+
+		_, err := RenderConfig(config, testScope())
+
+		require.Error(t, err)
+		assert.Equal(t, "config.body.id: unresolved reference ${steps.fetch.output.body.id}", err.Error())
+	})
+
+	t.Run("unterminated reference", func(t *testing.T) {
+		_, err := RenderConfig(map[string]interface{}{"url": "http://${input.host/x"}, testScope())
+
+		assert.Error(t, err)
+	})
+}
+
+func TestTemplateReferences(t *testing.T) {
+	refs := TemplateReferences("${input.host}:$${literal} ${ steps.a.output }")
+
+	assert.Equal(t, []string{"input.host", "steps.a.output"}, refs)
+}
@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+// StepsQuery returns the timeline of a running DynamicWorkflow
+const StepsQuery = "steps"
+
+// StepState is the state of a top-level step on the execution timeline
+type StepState struct {
+	Position    int         `json:"position"`
+	StepID      string      `json:"step_id"`
+	StepName    string      `json:"step_name,omitempty"`
+	StepType    string      `json:"step_type"`
+	Status      string      `json:"status"`
+	Attempts    int32       `json:"attempts,omitempty"`
+	Output      interface{} `json:"output,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	StartedAt   time.Time   `json:"started_at"`
+	CompletedAt *time.Time  `json:"completed_at,omitempty"`
+}
+
+// timeline tracks the top-level steps of a run for the steps query and
+// records each step on the execution as it starts and finishes
+type timeline struct {
+	input  DynamicWorkflowInput
+	record bool
+	steps  []StepState
+}
+
+func newTimeline(input DynamicWorkflowInput) *timeline {
+	return &timeline{input: input}
+}
+
+// register exposes the steps query
+func (t *timeline) register(ctx workflow.Context) error {
+	return workflow.SetQueryHandler(ctx, StepsQuery, func() ([]StepState, error) {
+		return t.steps, nil
+	})
+}
+
+// start adds a running step to the timeline and records it on the
+// execution, so a run that stops mid-step shows the step it was on
+func (t *timeline) start(ctx workflow.Context, position int, step StepDefinition) {
+	state := StepState{
+		Position:  position,
+		StepID:    step.ID,
+		StepName:  step.Name,
+		StepType:  string(step.Type),
+		Status:    string(domain.ExecutionStepStatusRunning),
+		StartedAt: workflow.Now(ctx),
+	}
+	t.steps = append(t.steps, state)
+	t.save(ctx, state)
+}
+
+// finish replaces the running step with its result and records it on the
+// execution
+func (t *timeline) finish(ctx workflow.Context, result StepResult) {
+	state := &t.steps[len(t.steps)-1]
+	completedAt := result.StartedAt.Add(time.Duration(result.DurationMs) * time.Millisecond)
+	state.Attempts = result.Attempts
+	state.StartedAt = result.StartedAt
+	state.CompletedAt = &completedAt
+	if result.Success {
+		state.Status = string(domain.ExecutionStepStatusCompleted)
+		state.Output = result.Output
+	} else {
+		state.Status = string(domain.ExecutionStepStatusFailed)
+		state.Error = result.Error
+	}
+	t.save(ctx, *state)
+}
+
+// compensated marks the step at position as undone by its compensate steps
+func (t *timeline) compensated(ctx workflow.Context, position int) {
+	for i := range t.steps {
+		if t.steps[i].Position == position {
+			t.steps[i].Status = string(domain.ExecutionStepStatusCompensated)
+			t.save(ctx, t.steps[i])
+			return
+		}
+	}
+}
+
+// save records a step on the execution. It runs on a disconnected context
+// so the step a cancellation interrupted is recorded too; failing to record
+// a step is logged and doesn't fail the run.
+func (t *timeline) save(ctx workflow.Context, state StepState) {
+	if !t.record || t.input.ExecutionID == "" || t.input.TenantID == "" {
+		return
+	}
+
+	ctx, cancel := workflow.NewDisconnectedContext(ctx)
+	defer cancel()
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	err := workflow.ExecuteActivity(ctx, "RecordStep", activity.RecordStepInput{
+		TenantID:    t.input.TenantID,
+		ExecutionID: t.input.ExecutionID,
+		Position:    state.Position,
+		StepID:      state.StepID,
+		StepName:    state.StepName,
+		StepType:    state.StepType,
+		Status:      state.Status,
+		Attempts:    state.Attempts,
+		Output:      state.Output,
+		Error:       state.Error,
+		StartedAt:   state.StartedAt,
+		CompletedAt: state.CompletedAt,
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("failed to record step",
+			"execution_id", t.input.ExecutionID,
+			"step_id", state.StepID,
+			"error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkactivity "go.temporal.io/sdk/activity"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+)
+
+func TestTimeline_RecordsSteps(t *testing.T) {
+	env := newTestEnv()
+	var recorded []activity.RecordStepInput
+	env.RegisterActivityWithOptions(func(ctx context.Context, input activity.RecordStepInput) error {
+		recorded = append(recorded, input)
+		return nil
+	}, sdkactivity.RegisterOptions{Name: "RecordStep"})
+	env.RegisterActivityWithOptions(func(ctx context.Context, input activity.CompleteExecutionInput) error {
+		return nil
+	}, sdkactivity.RegisterOptions{Name: "CompleteExecution"})
+
+	env.ExecuteWorkflow(DynamicWorkflow, DynamicWorkflowInput{
+		ExecutionID: "execution-1",
+		TenantID:    "tenant-1",
+		WorkflowID:  "test",
+		Name:        "test",
+		Definition: json.RawMessage(`{"steps": [
+			{"id": "check", "type": "http", "config": {"url": "http://a"}},
+			{"id": "deploy", "type": "http", "config": {"url": "http://fail"}}
+		]}`),
+	})
+	require.NoError(t, env.GetWorkflowError())
+
+	// Each step is recorded when it starts, then again with its outcome
+	require.Len(t, recorded, 4)
+	statuses := make([]string, len(recorded))
+	for i, step := range recorded {
+		statuses[i] = step.StepID + ":" + step.Status
+	}
+	assert.Equal(t, []string{"check:running", "check:completed", "deploy:running", "deploy:failed"}, statuses)
+	assert.Nil(t, recorded[0].CompletedAt)
+	assert.NotNil(t, recorded[1].CompletedAt)
+	assert.Equal(t, 1, recorded[2].Position)
+}
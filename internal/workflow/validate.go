@@ -0,0 +1,486 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/orchestrix/orchestrix-api/internal/activity"
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+// MaxStepDepth bounds how deeply steps can be nested inside condition,
+// parallel and foreach steps
+const MaxStepDepth = 8
+
+var knownStepTypes = map[StepType]bool{
+	StepTypeHTTP:          true,
+	StepTypeDelay:         true,
+	StepTypeLog:           true,
+	StepTypeValidate:      true,
+	StepTypeProcess:       true,
+	StepTypeNotify:        true,
+	StepTypeCondition:     true,
+	StepTypeScript:        true,
+	StepTypeParallel:      true,
+	StepTypeForeach:       true,
+	StepTypeChildWorkflow: true,
+	StepTypeApproval:      true,
+	StepTypeWaitForMetric: true,
+}
+
+var httpMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// httpSecretFields are the http step config keys the worker resolves
+// ${secrets.NAME} references in
+var httpSecretFields = map[string]bool{"url": true, "headers": true, "body": true}
+
+// DefinitionValidator implements port.DefinitionValidator
+type DefinitionValidator struct{}
+
+// NewDefinitionValidator creates a new definition validator
+func NewDefinitionValidator() *DefinitionValidator {
+	return &DefinitionValidator{}
+}
+
+// Validate checks a workflow definition and returns every problem found
+func (DefinitionValidator) Validate(definition []byte) []domain.DefinitionError {
+	return ValidateDefinition(definition)
+}
+
+// ValidateDefinition statically checks a workflow definition: step ID
+// uniqueness, known step types, required config per type, durations and
+// retry policies, references to nonexistent steps and nesting depth. Values
+// containing ${...} references are only checked once rendered, at run time.
+func ValidateDefinition(data []byte) []domain.DefinitionError {
+	v := &definitionValidator{ids: make(map[string]string)}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		v.add("$", "definition is required")
+		return v.errors
+	}
+
+	var def WorkflowDefinition
+	if err := json.Unmarshal(data, &def); err != nil {
+		v.add("$", "invalid definition: %v", err)
+		return v.errors
+	}
+
+	if len(def.Steps) == 0 {
+		v.add("$.steps", "at least one step is required")
+	}
+	v.literalDuration("$.timeout", def.Timeout)
+	v.retryPolicy("$.retry_policy", def.RetryPolicy)
+	if def.Inputs != nil {
+		v.errors = append(v.errors, def.Inputs.Check("$.inputs")...)
+	}
+
+	// Same defaults as ParseDefinition, so generated IDs can be referenced
+	applyStepDefaults(def.Steps, "step")
+	applyStepDefaults(def.OnSuccess, "on_success")
+	applyStepDefaults(def.OnError, "on_error")
+
+	v.collectIDs(def.Steps, "$.steps")
+	v.collectIDs(def.OnSuccess, "$.on_success")
+	v.collectIDs(def.OnError, "$.on_error")
+
+	v.steps(def.Steps, "$.steps", 1, nil)
+	v.steps(def.OnSuccess, "$.on_success", 1, nil)
+	v.steps(def.OnError, "$.on_error", 1, nil)
+
+	for i, step := range def.OnSuccess {
+		if len(step.Compensate) > 0 {
+			v.add(fmt.Sprintf("$.on_success[%d].compensate", i), "compensate is only supported on top-level steps")
+		}
+	}
+	for i, step := range def.OnError {
+		if len(step.Compensate) > 0 {
+			v.add(fmt.Sprintf("$.on_error[%d].compensate", i), "compensate is only supported on top-level steps")
+		}
+	}
+
+	return v.errors
+}
+
+type definitionValidator struct {
+	errors []domain.DefinitionError
+	ids    map[string]string // step ID -> path of the step that declares it
+}
+
+func (v *definitionValidator) add(path, format string, args ...interface{}) {
+	v.errors = append(v.errors, domain.DefinitionError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *definitionValidator) collectIDs(steps []StepDefinition, path string) {
+	for i, step := range steps {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if first, ok := v.ids[step.ID]; ok {
+			v.add(p+".id", "duplicate step id %q, already used by %s", step.ID, first)
+		} else {
+			v.ids[step.ID] = p
+		}
+		v.collectIDs(step.OnTrue, p+".on_true")
+		v.collectIDs(step.OnFalse, p+".on_false")
+		v.collectIDs(step.Parallel, p+".parallel")
+		v.collectIDs(step.Steps, p+".steps")
+		v.collectIDs(step.Compensate, p+".compensate")
+	}
+}
+
+// steps validates a list of steps. vars holds the loop variables in scope.
+func (v *definitionValidator) steps(steps []StepDefinition, path string, depth int, vars map[string]bool) {
+	for i, step := range steps {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		if depth > MaxStepDepth {
+			v.add(p, "steps cannot be nested more than %d levels deep", MaxStepDepth)
+			return
+		}
+		v.step(step, p, depth, vars)
+	}
+}
+
+func (v *definitionValidator) step(step StepDefinition, path string, depth int, vars map[string]bool) {
+	if step.Type == "" {
+		v.add(path+".type", "type is required")
+		return
+	}
+	if !knownStepTypes[step.Type] {
+		v.add(path+".type", "unknown step type %q", step.Type)
+		return
+	}
+
+	v.literalDuration(path+".timeout", step.Timeout)
+	v.retryPolicy(path+".retry_policy", step.RetryPolicy)
+
+	if len(step.Compensate) > 0 {
+		if depth > 1 {
+			v.add(path+".compensate", "compensate is only supported on top-level steps")
+		} else {
+			v.steps(step.Compensate, path+".compensate", depth+1, vars)
+		}
+	}
+
+	for _, key := range sortedKeys(step.Config) {
+		if step.Type == StepTypeScript && key == "code" {
+			continue // JavaScript template literals use the same syntax
+		}
+		secrets := step.Type == StepTypeHTTP && httpSecretFields[key]
+		v.templateReferences(path+".config."+key, step.Config[key], vars, secrets)
+	}
+
+	config := path + ".config"
+	switch step.Type {
+	case StepTypeHTTP:
+		if v.required(config, step.Config, "url") {
+			if s, ok := staticString(step.Config["url"]); ok {
+				if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					v.add(config+".url", "must be an absolute http or https URL")
+				}
+			}
+		}
+		if s, ok := staticString(step.Config["method"]); ok && !httpMethods[strings.ToUpper(s)] {
+			v.add(config+".method", "unsupported method %q", s)
+		}
+		v.durationField(config, step.Config, "timeout")
+		if static(step.Config, "url", "headers", "body") {
+			if _, err := ParseHTTPConfig(without(step.Config, "url", "headers", "body")); err != nil {
+				v.add(config, "%v", err)
+			}
+		}
+
+	case StepTypeDelay:
+		if step.Config["duration"] != nil && step.Config["until"] != nil {
+			v.add(config, "only one of duration or until can be set")
+		}
+		if s, ok := staticString(step.Config["duration"]); ok {
+			if d, err := time.ParseDuration(s); err != nil || d < 0 {
+				v.add(config+".duration", "invalid duration %q", s)
+			}
+		}
+		if s, ok := staticString(step.Config["until"]); ok {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v.add(config+".until", "must be an RFC 3339 time")
+			}
+		}
+
+	case StepTypeLog:
+		if s, ok := staticString(step.Config["level"]); ok {
+			switch s {
+			case "info", "warn", "error":
+			default:
+				v.add(config+".level", "unsupported level %q", s)
+			}
+		}
+
+	case StepTypeNotify:
+		v.required(config, step.Config, "message")
+
+	case StepTypeScript:
+		if v.required(config, step.Config, "code") {
+			if s, ok := step.Config["code"].(string); ok && len(s) > activity.MaxScriptCodeBytes {
+				v.add(config+".code", "code exceeds %d bytes", activity.MaxScriptCodeBytes)
+			}
+		}
+		if s, ok := staticString(step.Config["language"]); ok && s != "javascript" {
+			v.add(config+".language", "unsupported language %q", s)
+		}
+		v.durationField(config, step.Config, "timeout")
+		if value, ok := step.Config["max_memory_mb"]; ok {
+			n, isNumber := value.(float64)
+			_, isReference := value.(string)
+			if !isReference && (!isNumber || n != float64(int(n)) || n < 1 || n > activity.MaxScriptMemoryMB) {
+				v.add(config+".max_memory_mb", "must be an integer between 1 and %d", activity.MaxScriptMemoryMB)
+			}
+		}
+
+	case StepTypeCondition:
+		if step.Condition == "" {
+			v.add(path+".condition", "condition is required")
+		} else if expr, err := CompileExpression(step.Condition); err != nil {
+			v.add(path+".condition", "%v", err)
+		} else {
+			for _, ref := range expr.References() {
+				v.reference(path+".condition", ref, vars, false)
+			}
+		}
+		v.steps(step.OnTrue, path+".on_true", depth+1, vars)
+		v.steps(step.OnFalse, path+".on_false", depth+1, vars)
+
+	case StepTypeParallel:
+		if len(step.Parallel) == 0 {
+			v.add(path+".parallel", "at least one branch is required")
+		} else if static(step.Config) {
+			if cfg, err := ParseParallelConfig(step.Config); err != nil {
+				v.add(config, "%v", err)
+			} else if _, err := cfg.Required(len(step.Parallel)); err != nil {
+				v.add(config, "%v", err)
+			}
+		}
+		v.steps(step.Parallel, path+".parallel", depth+1, vars)
+
+	case StepTypeForeach:
+		v.required(config, step.Config, "items")
+		as := "item"
+		if static(step.Config, "items") {
+			cfg, err := ParseForeachConfig(without(step.Config, "items"))
+			if err != nil {
+				v.add(config, "%v", err)
+			} else {
+				as = cfg.As
+			}
+		}
+		if len(step.Steps) == 0 {
+			v.add(path+".steps", "at least one nested step is required")
+		}
+		loopVars := map[string]bool{as: true, "index": true}
+		for name := range vars {
+			loopVars[name] = true
+		}
+		v.steps(step.Steps, path+".steps", depth+1, loopVars)
+
+	case StepTypeChildWorkflow:
+		if static(step.Config, "input") {
+			if _, err := ParseChildWorkflowConfig(without(step.Config, "input")); err != nil {
+				v.add(config, "%v", err)
+			}
+		}
+		if s, ok := staticString(step.Config["workflow_id"]); ok {
+			if _, err := uuid.Parse(s); err != nil {
+				v.add(config+".workflow_id", "must be a valid UUID")
+			}
+		}
+		v.durationField(config, step.Config, "timeout")
+
+	case StepTypeApproval:
+		if static(step.Config, "message") {
+			cfg, err := ParseApprovalConfig(without(step.Config, "message"))
+			if err == nil {
+				_, err = cfg.TimeoutDuration()
+			}
+			if err != nil {
+				v.add(config, "%v", err)
+			}
+		}
+
+	case StepTypeWaitForMetric:
+		if static(step.Config) {
+			if _, err := ParseWaitForMetricConfig(step.Config); err != nil {
+				v.add(config, "%v", err)
+			}
+		}
+	}
+}
+
+// templateReferences checks the ${...} references in a config value.
+// secrets allows ${secrets.NAME} references.
+func (v *definitionValidator) templateReferences(path string, value interface{}, vars map[string]bool, secrets bool) {
+	switch val := value.(type) {
+	case string:
+		for _, ref := range TemplateReferences(val) {
+			v.reference(path, ref, vars, secrets)
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(val) {
+			v.templateReferences(path+"."+k, val[k], vars, secrets)
+		}
+	case []interface{}:
+		for i, item := range val {
+			v.templateReferences(fmt.Sprintf("%s[%d]", path, i), item, vars, secrets)
+		}
+	}
+}
+
+// reference checks that a referenced path starts at a known scope root and
+// that referenced steps exist. Secrets are only resolved by the HTTP
+// activity, so they can only be referenced where secrets is set; whether
+// the secret exists is only known when the step runs.
+func (v *definitionValidator) reference(path, ref string, vars map[string]bool, secrets bool) {
+	segments := splitPath(ref)
+	if len(segments) == 0 {
+		return
+	}
+	root := segments[0]
+	if isSecretReference(ref) {
+		if !secrets {
+			v.add(path, "secrets can only be referenced in the url, headers and body of http steps")
+		} else if len(segments) != 2 || !domain.ValidSecretName(segments[1]) {
+			v.add(path, "invalid secret reference %q, expected secrets.NAME", ref)
+		}
+		return
+	}
+	// index is reserved but only bound inside foreach loops
+	if !vars[root] && (!reservedScopeNames[root] || root == "index") {
+		v.add(path, "unknown reference %q", ref)
+		return
+	}
+	if root == "steps" && len(segments) > 1 {
+		if _, ok := v.ids[segments[1]]; !ok {
+			v.add(path, "reference %q points to nonexistent step %q", ref, segments[1])
+		}
+	}
+}
+
+func (v *definitionValidator) required(path string, config map[string]interface{}, key string) bool {
+	value, ok := config[key]
+	if s, isString := value.(string); !ok || value == nil || (isString && strings.TrimSpace(s) == "") {
+		v.add(path+"."+key, "%s is required", key)
+		return false
+	}
+	return true
+}
+
+func (v *definitionValidator) durationField(path string, config map[string]interface{}, key string) {
+	if value, ok := config[key]; ok {
+		if s, ok := staticString(value); ok {
+			v.duration(path+"."+key, s)
+		} else if _, isString := value.(string); !isString {
+			v.add(path+"."+key, "must be a duration string such as \"30s\"")
+		}
+	}
+}
+
+func (v *definitionValidator) duration(path, value string) {
+	if value == "" || strings.Contains(value, "${") {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		v.add(path, "invalid duration %q", value)
+		return
+	}
+	if d <= 0 {
+		v.add(path, "duration must be positive")
+	}
+}
+
+// literalDuration checks a duration outside step config, where ${...}
+// references aren't rendered
+func (v *definitionValidator) literalDuration(path, value string) {
+	if strings.Contains(value, "${") {
+		v.add(path, "must be a duration such as \"30s\", references are not supported here")
+		return
+	}
+	v.duration(path, value)
+}
+
+func (v *definitionValidator) retryPolicy(path string, rp *RetryPolicyDef) {
+	if rp == nil {
+		return
+	}
+	if rp.MaxAttempts < 0 {
+		v.add(path+".max_attempts", "must not be negative")
+	}
+	if rp.Multiplier != 0 && rp.Multiplier < 1 {
+		v.add(path+".multiplier", "must be at least 1")
+	}
+	v.literalDuration(path+".initial_interval", rp.InitialInterval)
+	v.literalDuration(path+".max_interval", rp.MaxInterval)
+
+	initial, err1 := time.ParseDuration(rp.InitialInterval)
+	max, err2 := time.ParseDuration(rp.MaxInterval)
+	if err1 == nil && err2 == nil && max < initial {
+		v.add(path+".max_interval", "must not be less than initial_interval")
+	}
+}
+
+// staticString returns value as a string when it is one without ${...}
+// references
+func staticString(value interface{}) (string, bool) {
+	s, ok := value.(string)
+	if !ok || strings.Contains(s, "${") {
+		return "", false
+	}
+	return s, true
+}
+
+// static reports whether config has no ${...} references outside the
+// ignored keys, so it can be parsed as is
+func static(config map[string]interface{}, ignore ...string) bool {
+	for _, value := range without(config, ignore...) {
+		if hasTemplate(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTemplate(value interface{}) bool {
+	switch val := value.(type) {
+	case string:
+		return strings.Contains(val, "${")
+	case map[string]interface{}:
+		for _, item := range val {
+			if hasTemplate(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range val {
+			if hasTemplate(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func without(config map[string]interface{}, keys ...string) map[string]interface{} {
+	if len(keys) == 0 {
+		return config
+	}
+	out := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		out[k] = v
+	}
+	for _, k := range keys {
+		delete(out, k)
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/orchestrix/orchestrix-api/internal/core/domain"
+)
+
+func TestValidateDefinition(t *testing.T) {
+	t.Run("accepts a valid definition", func(t *testing.T) {
+		errs := ValidateDefinition([]byte(`{
+			"timeout": "30m",
+			"retry_policy": {"max_attempts": 3, "initial_interval": "1s", "max_interval": "1m", "multiplier": 2},
+			"steps": [
+				{"id": "fetch", "type": "http", "config": {"url": "https://example.com/${input.path}", "method": "post",
+					"headers": {"Authorization": "Bearer ${secrets.API_TOKEN}"}, "body": {"key": "${secrets.api_key}"},
+					"extract": [{"name": "version", "jsonpath": "$.build.version"}],
+					"assert": [{"jsonpath": "$.status", "equals": "ok"}, {"latency_below": "500ms"}]}},
+				{"id": "check", "type": "condition", "condition": "${steps.fetch.status_code} == 200",
+					"on_true": [{"type": "log", "config": {"message": "ok"}}]},
+				{"id": "each", "type": "foreach", "config": {"items": "${input.hosts}", "as": "host"},
+					"steps": [{"type": "log", "config": {"message": "${host} ${index}"}}]},
+				{"id": "wait", "type": "delay", "config": {"duration": "5s"}}
+			]
+		}`))
+
+		assert.Empty(t, errs)
+	})
+
+	tests := []struct {
+		name       string
+		definition string
+		expected   []domain.DefinitionError
+	}{
+		{
+			name:       "invalid JSON",
+			definition: `{"steps": [`,
+			expected:   []domain.DefinitionError{{Path: "$", Message: "invalid definition: unexpected end of JSON input"}},
+		},
+		{
+			name:       "empty definition",
+			definition: ``,
+			expected:   []domain.DefinitionError{{Path: "$", Message: "definition is required"}},
+		},
+		{
+			name:       "no steps",
+			definition: `{"steps": []}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps", Message: "at least one step is required"}},
+		},
+		{
+			name:       "duplicate step ids",
+			definition: `{"steps": [{"id": "a", "type": "log"}, {"id": "a", "type": "log"}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[1].id", Message: `duplicate step id "a", already used by $.steps[0]`}},
+		},
+		{
+			name:       "missing and unknown types",
+			definition: `{"steps": [{"id": "a"}, {"id": "b", "type": "ftp"}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].type", Message: "type is required"},
+				{Path: "$.steps[1].type", Message: `unknown step type "ftp"`},
+			},
+		},
+		{
+			name:       "http without url",
+			definition: `{"steps": [{"type": "http", "config": {"method": "GET"}}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[0].config.url", Message: "url is required"}},
+		},
+		{
+			name:       "http with relative url and bad method",
+			definition: `{"steps": [{"type": "http", "config": {"url": "/health", "method": "FETCH"}}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.url", Message: "must be an absolute http or https URL"},
+				{Path: "$.steps[0].config.method", Message: `unsupported method "FETCH"`},
+			},
+		},
+		{
+			name: "http with invalid extract and assert rules",
+			definition: `{"steps": [{"type": "http", "config": {"url": "https://example.com", ` +
+				`"extract": [{"name": "id", "regex": "(", "header": "X-Id"}], "assert": [{"header": "X-Id", "equals": 1}]}}]}`,
+			expected: []domain.DefinitionError{{Path: "$.steps[0].config", Message: "extract[0]: exactly one of jsonpath, regex or header is required"}},
+		},
+		{
+			name: "secret references outside http requests",
+			definition: `{"steps": [
+				{"type": "http", "config": {"url": "https://example.com/${secrets.PATH}", "headers": {"X-Key": "${secrets.api-key}"},
+					"extract": [{"name": "${secrets.NAME}", "header": "X-Id"}]}},
+				{"type": "log", "config": {"message": "token ${secrets.API_TOKEN}"}}
+			]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.extract[0].name", Message: "secrets can only be referenced in the url, headers and body of http steps"},
+				{Path: "$.steps[0].config.headers.X-Key", Message: `invalid secret reference "secrets.api-key", expected secrets.NAME`},
+				{Path: "$.steps[1].config.message", Message: "secrets can only be referenced in the url, headers and body of http steps"},
+			},
+		},
+		{
+			name:       "delay with bad duration",
+			definition: `{"steps": [{"type": "delay", "config": {"duration": "5 minutes"}}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[0].config.duration", Message: `invalid duration "5 minutes"`}},
+		},
+		{
+			name: "invalid retry policies",
+			definition: `{
+				"timeout": "soon",
+				"retry_policy": {"max_attempts": -1, "initial_interval": "1m", "max_interval": "1s"},
+				"steps": [{"type": "log", "retry_policy": {"multiplier": 0.5, "initial_interval": "x"}}]
+			}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.timeout", Message: `invalid duration "soon"`},
+				{Path: "$.retry_policy.max_attempts", Message: "must not be negative"},
+				{Path: "$.retry_policy.max_interval", Message: "must not be less than initial_interval"},
+				{Path: "$.steps[0].retry_policy.multiplier", Message: "must be at least 1"},
+				{Path: "$.steps[0].retry_policy.initial_interval", Message: `invalid duration "x"`},
+			},
+		},
+		{
+			name: "timeouts that are references or not positive",
+			definition: `{
+				"timeout": "${input.timeout}",
+				"steps": [{"type": "log", "timeout": "0s", "retry_policy": {"max_interval": "${input.backoff}"}}]
+			}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.timeout", Message: `must be a duration such as "30s", references are not supported here`},
+				{Path: "$.steps[0].timeout", Message: "duration must be positive"},
+				{Path: "$.steps[0].retry_policy.max_interval"},
+			},
+		},
+		{
+			name: "references to nonexistent steps",
+			definition: `{"steps": [
+				{"id": "a", "type": "log", "config": {"message": "${steps.missing.output}", "data": {"x": "${nope}"}}},
+				{"id": "b", "type": "condition", "condition": "steps.gone.success",
+					"on_true": [{"type": "log", "config": {"message": "${item}"}}]}
+			]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.data.x", Message: `unknown reference "nope"`},
+				{Path: "$.steps[0].config.message", Message: `reference "steps.missing.output" points to nonexistent step "missing"`},
+				{Path: "$.steps[1].condition", Message: `reference "steps.gone.success" points to nonexistent step "gone"`},
+				{Path: "$.steps[1].on_true[0].config.message", Message: `unknown reference "item"`},
+			},
+		},
+		{
+			name:       "condition that does not compile",
+			definition: `{"steps": [{"type": "condition", "condition": "input.x ==", "on_true": [{"type": "log"}]}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[0].condition"}},
+		},
+		{
+			name:       "parallel without branches",
+			definition: `{"steps": [{"type": "parallel"}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[0].parallel", Message: "at least one branch is required"}},
+		},
+		{
+			name:       "foreach without items or steps",
+			definition: `{"steps": [{"type": "foreach", "config": {"as": "input"}}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.items", Message: "items is required"},
+				{Path: "$.steps[0].config", Message: `loop variable name "input" is reserved`},
+				{Path: "$.steps[0].steps", Message: "at least one nested step is required"},
+			},
+		},
+		{
+			name:       "child workflow with both id and name",
+			definition: `{"steps": [{"type": "child_workflow", "config": {"workflow_id": "x", "workflow_name": "y"}}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config", Message: "exactly one of workflow_id or workflow_name is required"},
+				{Path: "$.steps[0].config.workflow_id", Message: "must be a valid UUID"},
+			},
+		},
+		{
+			name:       "invalid input schema",
+			definition: `{"inputs": {"properties": {"port": {"type": "integer", "default": "443"}}}, "steps": [{"type": "log"}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.inputs.properties.port.default", Message: "must be an integer"}},
+		},
+		{
+			name:       "script without code",
+			definition: `{"steps": [{"type": "script", "config": {"language": "python"}}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.code", Message: "code is required"},
+				{Path: "$.steps[0].config.language", Message: `unsupported language "python"`},
+			},
+		},
+		{
+			name: "script memory limit out of range",
+			definition: `{"steps": [{"type": "script", "config": {"code": "return 1", "max_memory_mb": 4096}},
+				{"type": "script", "config": {"code": "return 1", "max_memory_mb": 0.5}}]}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].config.max_memory_mb", Message: "must be an integer between 1 and 256"},
+				{Path: "$.steps[1].config.max_memory_mb", Message: "must be an integer between 1 and 256"},
+			},
+		},
+		{
+			name:       "invalid compensate steps",
+			definition: `{"steps": [{"type": "log", "compensate": [{"type": "http", "config": {"method": "DELETE"}}]}]}`,
+			expected:   []domain.DefinitionError{{Path: "$.steps[0].compensate[0].config.url", Message: "url is required"}},
+		},
+		{
+			name: "compensate on nested and error handling steps",
+			definition: `{
+				"steps": [{"type": "parallel", "parallel": [{"type": "log", "compensate": [{"type": "log"}]}]}],
+				"on_error": [{"type": "log", "compensate": [{"type": "log"}]}]
+			}`,
+			expected: []domain.DefinitionError{
+				{Path: "$.steps[0].parallel[0].compensate", Message: "compensate is only supported on top-level steps"},
+				{Path: "$.on_error[0].compensate", Message: "compensate is only supported on top-level steps"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateDefinition([]byte(tt.definition))
+
+			if assert.Len(t, errs, len(tt.expected)) {
+				for i, expected := range tt.expected {
+					assert.Equal(t, expected.Path, errs[i].Path)
+					if expected.Message != "" {
+						assert.Equal(t, expected.Message, errs[i].Message)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("ignores template references in script code", func(t *testing.T) {
+		errs := ValidateDefinition([]byte("{\"steps\": [{\"type\": \"script\", \"config\": {\"code\": \"return `${x}`\"}}]}"))
+
+		assert.Empty(t, errs)
+	})
+
+	t.Run("rejects steps nested too deeply", func(t *testing.T) {
+		step := `{"type": "log"}`
+		for i := 0; i < MaxStepDepth; i++ {
+			step = fmt.Sprintf(`{"type": "parallel", "parallel": [%s]}`, step)
+		}
+
+		errs := ValidateDefinition([]byte(`{"steps": [` + step + `]}`))
+
+		if assert.Len(t, errs, 1) {
+			assert.Equal(t, "$.steps[0]"+strings.Repeat(".parallel[0]", MaxStepDepth), errs[0].Path)
+		}
+	})
+}
@@ -87,6 +87,8 @@ func (h *Handler) mapDomainError(err error) *AppError {
 		return Validation("invalid workflow definition")
 	case domain.ErrNoSteps:
 		return Validation("workflow must have at least one step")
+	case domain.ErrInvalidInput:
+		return Validation("invalid workflow input")
 
 	// Execution errors
 	case domain.ErrExecutionNotFound:
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_executions_parent_execution_id;
+ALTER TABLE executions DROP COLUMN IF EXISTS parent_execution_id;
@@ -0,0 +1,5 @@
+-- Link executions started by a child_workflow step to the parent execution
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS parent_execution_id UUID REFERENCES executions(id) ON DELETE SET NULL;
+
+-- Index for listing the children of an execution
+CREATE INDEX IF NOT EXISTS idx_executions_parent_execution_id ON executions(parent_execution_id);
@@ -0,0 +1,5 @@
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS trigger_workflow_version;
+ALTER TABLE executions DROP COLUMN IF EXISTS workflow_version;
+
+DROP POLICY IF EXISTS tenant_isolation_workflow_versions ON workflow_versions;
+DROP TABLE IF EXISTS workflow_versions;
@@ -0,0 +1,35 @@
+-- Every published workflow definition, kept immutable so executions can be
+-- traced back to the exact definition they ran
+CREATE TABLE IF NOT EXISTS workflow_versions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    workflow_id UUID NOT NULL REFERENCES workflows(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    definition JSONB NOT NULL,
+    restored_from INT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (workflow_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workflow_versions_tenant_id ON workflow_versions(tenant_id);
+
+-- Record the current definition of existing workflows as their first version
+INSERT INTO workflow_versions (tenant_id, workflow_id, version, definition, created_by, created_at)
+SELECT tenant_id, id, version, definition, created_by, updated_at
+FROM workflows
+ON CONFLICT (workflow_id, version) DO NOTHING;
+
+ALTER TABLE workflow_versions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE workflow_versions FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation_workflow_versions ON workflow_versions;
+CREATE POLICY tenant_isolation_workflow_versions ON workflow_versions
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::uuid);
+
+-- The workflow version an execution ran
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS workflow_version INT;
+
+-- Version an alert rule triggers, NULL follows the latest version
+ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS trigger_workflow_version INT;
@@ -0,0 +1,3 @@
+-- Timezone, jitter and overlap policy of a workflow's cron schedule, e.g.
+-- {"timezone": "Europe/Berlin", "jitter": "30s", "overlap": "skip"}
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS schedule_options JSONB;
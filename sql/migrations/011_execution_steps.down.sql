@@ -0,0 +1,2 @@
+DROP POLICY IF EXISTS tenant_isolation_execution_steps ON execution_steps;
+DROP TABLE IF EXISTS execution_steps;
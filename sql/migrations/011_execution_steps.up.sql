@@ -0,0 +1,29 @@
+-- Outcome of each top-level step of an execution, written by the worker as
+-- the workflow progresses
+CREATE TABLE IF NOT EXISTS execution_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    execution_id UUID NOT NULL REFERENCES executions(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    step_id VARCHAR(255) NOT NULL,
+    step_name VARCHAR(255),
+    step_type VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    attempts INT NOT NULL DEFAULT 1,
+    output JSONB,
+    error TEXT,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (execution_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_execution_steps_tenant_id ON execution_steps(tenant_id);
+
+ALTER TABLE execution_steps ENABLE ROW LEVEL SECURITY;
+ALTER TABLE execution_steps FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation_execution_steps ON execution_steps;
+CREATE POLICY tenant_isolation_execution_steps ON execution_steps
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::uuid);
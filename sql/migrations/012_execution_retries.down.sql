@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_executions_retry_of;
+ALTER TABLE executions DROP COLUMN IF EXISTS retry_of;
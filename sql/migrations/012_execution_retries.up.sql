@@ -0,0 +1,4 @@
+-- Link a retried execution to the execution it retries
+ALTER TABLE executions ADD COLUMN IF NOT EXISTS retry_of UUID REFERENCES executions(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_executions_retry_of ON executions(retry_of);
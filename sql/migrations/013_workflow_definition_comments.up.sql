@@ -0,0 +1,2 @@
+-- Comments of definitions written in YAML, keyed by the path of the node
+ALTER TABLE workflows ADD COLUMN IF NOT EXISTS definition_comments JSONB;
@@ -0,0 +1,2 @@
+DROP POLICY IF EXISTS tenant_isolation_secrets ON secrets;
+DROP TABLE IF EXISTS secrets;
@@ -0,0 +1,29 @@
+-- Tenant secrets, referenced from workflow steps as ${secrets.NAME}. Values
+-- are envelope encrypted: encrypted_value is sealed with a per-secret data
+-- key, and encrypted_key is that data key sealed with the master key.
+CREATE TABLE IF NOT EXISTS secrets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(128) NOT NULL,
+    description TEXT,
+    encrypted_value BYTEA NOT NULL,
+    encrypted_key BYTEA NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_tenant_id ON secrets(tenant_id);
+
+DROP TRIGGER IF EXISTS update_secrets_updated_at ON secrets;
+CREATE TRIGGER update_secrets_updated_at BEFORE UPDATE ON secrets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE secrets ENABLE ROW LEVEL SECURITY;
+ALTER TABLE secrets FORCE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation_secrets ON secrets;
+CREATE POLICY tenant_isolation_secrets ON secrets
+    USING (tenant_id = current_setting('app.current_tenant_id', true)::uuid)
+    WITH CHECK (tenant_id = current_setting('app.current_tenant_id', true)::uuid);
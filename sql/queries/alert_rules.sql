@@ -4,9 +4,9 @@ INSERT INTO alert_rules (
     condition_type, condition_config,
     severity, alert_title_template, alert_message_template,
     trigger_workflow_id, trigger_input_template,
-    cooldown_seconds, created_by
+    cooldown_seconds, created_by, trigger_workflow_version
 )
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 RETURNING *;
 
 -- name: GetAlertRule :one
@@ -41,7 +41,8 @@ SET
     alert_message_template = COALESCE($10, alert_message_template),
     trigger_workflow_id = $11,
     trigger_input_template = $12,
-    cooldown_seconds = COALESCE($13, cooldown_seconds)
+    cooldown_seconds = COALESCE($13, cooldown_seconds),
+    trigger_workflow_version = $14
 WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
@@ -65,3 +66,14 @@ WHERE tenant_id = $1
     AND condition_type = 'metric_threshold'
     AND condition_config->>'metric_name' = $2
     AND (last_triggered_at IS NULL OR last_triggered_at < NOW() - (cooldown_seconds || ' seconds')::interval);
+
+-- name: GetAlertRuleByName :one
+SELECT * FROM alert_rules
+WHERE tenant_id = $1 AND name = $2
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListAlertRulesByWorkflow :many
+SELECT * FROM alert_rules
+WHERE tenant_id = $1 AND trigger_workflow_id = $2
+ORDER BY name;
@@ -0,0 +1,19 @@
+-- name: UpsertExecutionStep :one
+INSERT INTO execution_steps (
+    tenant_id, execution_id, position, step_id, step_name, step_type,
+    status, attempts, output, error, started_at, completed_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (execution_id, position) DO UPDATE SET
+    status = EXCLUDED.status,
+    attempts = EXCLUDED.attempts,
+    output = EXCLUDED.output,
+    error = EXCLUDED.error,
+    started_at = EXCLUDED.started_at,
+    completed_at = EXCLUDED.completed_at
+RETURNING *;
+
+-- name: ListExecutionSteps :many
+SELECT * FROM execution_steps
+WHERE execution_id = $1
+ORDER BY position;
@@ -29,15 +29,25 @@ ORDER BY created_at DESC
 LIMIT $3;
 
 -- name: CreateExecution :one
-INSERT INTO executions (tenant_id, workflow_id, temporal_workflow_id, status, input, triggered_by)
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO executions (id, tenant_id, workflow_id, temporal_workflow_id, status, input, triggered_by, parent_execution_id, workflow_version, retry_of)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
+-- name: ListChildExecutions :many
+SELECT * FROM executions
+WHERE parent_execution_id = $1
+ORDER BY created_at;
+
 -- name: UpdateExecutionStatus :exec
 UPDATE executions
 SET status = $2, error = $3
 WHERE id = $1;
 
+-- name: UpdateExecution :exec
+UPDATE executions
+SET status = $2, output = $3, error = $4, started_at = $5, completed_at = $6
+WHERE id = $1;
+
 -- name: UpdateExecutionRunID :exec
 UPDATE executions
 SET temporal_run_id = $2, status = $3, started_at = $4
@@ -58,6 +68,16 @@ RETURNING *;
 -- name: CountExecutions :one
 SELECT COUNT(*) FROM executions WHERE tenant_id = $1;
 
+-- name: MarkExecutionRunning :one
+-- Only pending executions become running: the worker may already have
+-- recorded the outcome of a run that finished quickly
+UPDATE executions
+SET temporal_workflow_id = $2, temporal_run_id = $3,
+    status = CASE WHEN status = 'pending' THEN 'running' ELSE status END,
+    started_at = COALESCE(started_at, NOW())
+WHERE id = $1
+RETURNING *;
+
 -- name: UpdateExecutionTemporalIDs :exec
 UPDATE executions
 SET temporal_workflow_id = $2, temporal_run_id = $3
@@ -29,6 +29,12 @@ WHERE tenant_id = $1 AND name = $2
 ORDER BY timestamp DESC
 LIMIT 1;
 
+-- name: GetLatestMetricByLabels :one
+SELECT * FROM metrics
+WHERE tenant_id = $1 AND name = $2 AND labels @> $3
+ORDER BY timestamp DESC
+LIMIT 1;
+
 -- name: GetMetricNames :many
 SELECT DISTINCT name FROM metrics
 WHERE tenant_id = $1
@@ -118,6 +124,23 @@ WHERE tenant_id = $1
     AND timestamp >= $3
     AND timestamp <= $4;
 
+-- name: GetMetricsAggregateWithLabels :one
+SELECT
+    COUNT(*) as count,
+    AVG(value) as avg_value,
+    MIN(value) as min_value,
+    MAX(value) as max_value,
+    SUM(value) as sum_value,
+    approx_percentile(0.50, percentile_agg(value)) as p50,
+    approx_percentile(0.95, percentile_agg(value)) as p95,
+    approx_percentile(0.99, percentile_agg(value)) as p99
+FROM metrics
+WHERE tenant_id = $1
+    AND name = $2
+    AND labels @> $3
+    AND timestamp >= $4
+    AND timestamp <= $5;
+
 -- Hourly Pre-aggregated Data (from continuous aggregate)
 -- name: GetMetricsHourly :many
 SELECT * FROM metrics_hourly
@@ -0,0 +1,29 @@
+-- name: CreateSecret :one
+INSERT INTO secrets (
+    tenant_id, name, description, encrypted_value, encrypted_key, created_by
+)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetSecret :one
+SELECT * FROM secrets
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetSecretByName :one
+SELECT * FROM secrets
+WHERE tenant_id = $1 AND name = $2;
+
+-- name: ListSecrets :many
+SELECT * FROM secrets
+WHERE tenant_id = $1
+ORDER BY name;
+
+-- name: UpdateSecret :one
+UPDATE secrets
+SET description = $3, encrypted_value = $4, encrypted_key = $5
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: DeleteSecret :exec
+DELETE FROM secrets
+WHERE id = $1 AND tenant_id = $2;
@@ -0,0 +1,22 @@
+-- name: CreateWorkflowVersion :one
+INSERT INTO workflow_versions (tenant_id, workflow_id, version, definition, restored_from, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetWorkflowVersion :one
+SELECT * FROM workflow_versions
+WHERE workflow_id = $1 AND version = $2;
+
+-- name: GetLatestWorkflowVersion :one
+SELECT COALESCE(MAX(version), 0)::int AS latest_version
+FROM workflow_versions
+WHERE workflow_id = $1;
+
+-- name: ListWorkflowVersions :many
+SELECT * FROM workflow_versions
+WHERE workflow_id = $1
+ORDER BY version DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountWorkflowVersions :one
+SELECT COUNT(*) FROM workflow_versions WHERE workflow_id = $1;
@@ -14,13 +14,13 @@ ORDER BY created_at DESC
 LIMIT $3 OFFSET $4;
 
 -- name: CreateWorkflow :one
-INSERT INTO workflows (tenant_id, name, description, definition, schedule, status, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+INSERT INTO workflows (tenant_id, name, description, definition, schedule, status, created_by, schedule_options, definition_comments)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING *;
 
 -- name: UpdateWorkflow :one
 UPDATE workflows
-SET name = $2, description = $3, definition = $4, schedule = $5, status = $6, version = version + 1
+SET name = $2, description = $3, definition = $4, schedule = $5, status = $6, version = $7, schedule_options = $8, definition_comments = $9
 WHERE id = $1
 RETURNING *;
 
@@ -43,3 +43,9 @@ SELECT COUNT(*) FROM workflows WHERE tenant_id = $1 AND status = $2;
 SELECT * FROM workflows
 WHERE status = 'active' AND schedule IS NOT NULL
 ORDER BY created_at;
+
+-- name: GetWorkflowByName :one
+SELECT * FROM workflows
+WHERE tenant_id = $1 AND name = $2
+ORDER BY created_at DESC
+LIMIT 1;